@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NamespaceClassBuilder builds a NamespaceClass from typed objects, so that
+// callers need not encode spec.resources by hand:
+//
+//	class, err := v1alpha1.NewNamespaceClassBuilder("team").
+//		WithResource(&corev1.ConfigMap{
+//			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+//			ObjectMeta: metav1.ObjectMeta{Name: "settings"},
+//		}).
+//		Build()
+//
+// The first error, e.g. a resource without apiVersion and kind, is returned by
+// Build.
+// +kubebuilder:object:generate=false
+type NamespaceClassBuilder struct {
+	class *NamespaceClass
+	err   error
+}
+
+// NewNamespaceClassBuilder returns a builder for a NamespaceClass named name.
+func NewNamespaceClassBuilder(name string) *NamespaceClassBuilder {
+	return &NamespaceClassBuilder{class: &NamespaceClass{
+		TypeMeta:   metav1.TypeMeta{APIVersion: GroupVersion.String(), Kind: "NamespaceClass"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}}
+}
+
+// WithResource appends obj to spec.resources. obj must carry its apiVersion
+// and kind, which typed objects leave empty unless TypeMeta is set, and no
+// namespace, since resources are created in the namespaces of the class.
+func (b *NamespaceClassBuilder) WithResource(obj runtime.Object) *NamespaceClassBuilder {
+	if b.err != nil {
+		return b
+	}
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		b.err = fmt.Errorf("resource %d of NamespaceClass %q has no apiVersion and kind",
+			len(b.class.Spec.Resources), b.class.Name)
+		return b
+	}
+	if accessor, ok := obj.(metav1.Object); ok && accessor.GetNamespace() != "" {
+		b.err = fmt.Errorf("%s %q of NamespaceClass %q must not set a namespace",
+			gvk.Kind, accessor.GetName(), b.class.Name)
+		return b
+	}
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		b.err = fmt.Errorf("failed to encode %s of NamespaceClass %q: %w", gvk.Kind, b.class.Name, err)
+		return b
+	}
+	b.class.Spec.Resources = append(b.class.Spec.Resources, runtime.RawExtension{Raw: raw})
+	return b
+}
+
+// WithResourceRef appends a reference to the manifests in key of the
+// ConfigMap named name, or to all of its keys if key is empty.
+func (b *NamespaceClassBuilder) WithResourceRef(name, key string) *NamespaceClassBuilder {
+	b.class.Spec.ResourceRefs = append(b.class.Spec.ResourceRefs, ResourceRef{Name: name, Key: key})
+	return b
+}
+
+// WithExtends makes the class extend the class named parent.
+func (b *NamespaceClassBuilder) WithExtends(parent string) *NamespaceClassBuilder {
+	b.class.Spec.Extends = parent
+	return b
+}
+
+// WithNamespaceSelector binds the class to the namespaces selector matches.
+func (b *NamespaceClassBuilder) WithNamespaceSelector(selector *metav1.LabelSelector) *NamespaceClassBuilder {
+	b.class.Spec.NamespaceSelector = selector
+	return b
+}
+
+// WithCleanupPolicy sets the cleanup policy of the class.
+func (b *NamespaceClassBuilder) WithCleanupPolicy(policy CleanupPolicy) *NamespaceClassBuilder {
+	b.class.Spec.CleanupPolicy = policy
+	return b
+}
+
+// Build returns the class, or the first error of the builder.
+func (b *NamespaceClassBuilder) Build() (*NamespaceClass, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.class.DeepCopy(), nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("NamespaceClassBuilder", func() {
+	decode := func(class *NamespaceClass, i int) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		Expect(obj.UnmarshalJSON(class.Spec.Resources[i].Raw)).To(Succeed())
+		return obj
+	}
+
+	It("should encode a ConfigMap and a Secret into spec.resources", func() {
+		class, err := NewNamespaceClassBuilder("builder-class").
+			WithResource(&corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "settings"},
+				Data:       map[string]string{"foo": "bar"},
+			}).
+			WithResource(&corev1.Secret{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+				ObjectMeta: metav1.ObjectMeta{Name: "token"},
+				Type:       corev1.SecretTypeOpaque,
+				Data:       map[string][]byte{"token": []byte("s3cr3t")},
+			}).
+			WithCleanupPolicy(CleanupPolicyDelete).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(class.Name).To(Equal("builder-class"))
+		Expect(class.GroupVersionKind()).To(Equal(GroupVersion.WithKind("NamespaceClass")))
+		Expect(class.Spec.CleanupPolicy).To(Equal(CleanupPolicyDelete))
+		Expect(class.Spec.Resources).To(HaveLen(2))
+
+		cm := decode(class, 0)
+		Expect(cm.GetKind()).To(Equal("ConfigMap"))
+		Expect(cm.GetName()).To(Equal("settings"))
+		data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+		Expect(data).To(HaveKeyWithValue("foo", "bar"))
+
+		secret := decode(class, 1)
+		Expect(secret.GetKind()).To(Equal("Secret"))
+		Expect(secret.GetName()).To(Equal("token"))
+		token, _, _ := unstructured.NestedString(secret.Object, "data", "token")
+		Expect(token).To(Equal("czNjcjN0"))
+	})
+
+	It("should set references, the parent and the selector", func() {
+		selector := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}}
+		class, err := NewNamespaceClassBuilder("builder-child").
+			WithExtends("builder-parent").
+			WithResourceRef("shared-manifests", "").
+			WithNamespaceSelector(selector).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(class.Spec.Extends).To(Equal("builder-parent"))
+		Expect(class.Spec.ResourceRefs).To(Equal([]ResourceRef{{Name: "shared-manifests"}}))
+		Expect(class.Spec.NamespaceSelector).To(Equal(selector))
+	})
+
+	It("should reject a typed object without apiVersion and kind", func() {
+		_, err := NewNamespaceClassBuilder("builder-untyped").
+			WithResource(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings"}}).
+			WithResource(&corev1.Secret{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}}).
+			Build()
+		Expect(err).To(MatchError(ContainSubstring("resource 0 of NamespaceClass \"builder-untyped\" has no apiVersion and kind")))
+	})
+
+	It("should reject a resource that sets a namespace", func() {
+		_, err := NewNamespaceClassBuilder("builder-namespaced").
+			WithResource(&corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "elsewhere"},
+			}).
+			Build()
+		Expect(err).To(MatchError(ContainSubstring("must not set a namespace")))
+	})
+})
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// ConvertTo converts this NamespaceClass to the v1alpha1 hub version.
+func (src *NamespaceClass) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.NamespaceClass)
+	in := src.DeepCopy()
+	dst.ObjectMeta = in.ObjectMeta
+
+	dst.Spec = v1alpha1.NamespaceClassSpec{
+		Resources:         in.Spec.Resources,
+		ResourcesYAML:     in.Spec.ResourcesYAML,
+		Extends:           in.Spec.Extends,
+		NamespaceSelector: in.Spec.NamespaceSelector,
+		ValuesRef:         (*v1alpha1.ValuesRef)(in.Spec.ValuesRef),
+		CleanupPolicy:     v1alpha1.CleanupPolicy(in.Spec.CleanupPolicy),
+	}
+	for _, ref := range in.Spec.ResourceRefs {
+		dst.Spec.ResourceRefs = append(dst.Spec.ResourceRefs, v1alpha1.ResourceRef(ref))
+	}
+	for _, ref := range in.Spec.SecretRefs {
+		dst.Spec.SecretRefs = append(dst.Spec.SecretRefs, v1alpha1.SecretRef(ref))
+	}
+
+	dst.Status = v1alpha1.NamespaceClassStatus{
+		Phase:                     v1alpha1.ClassPhase(in.Status.Phase),
+		LastAppliedResources:      in.Status.LastAppliedResources,
+		ObservedGeneration:        in.Status.ObservedGeneration,
+		LastReconcileTime:         in.Status.LastReconcileTime,
+		AppliedResourceCount:      in.Status.AppliedResourceCount,
+		BoundNamespaces:           in.Status.BoundNamespaces,
+		ClusterScopedResources:    in.Status.ClusterScopedResources,
+		PendingChanges:            in.Status.PendingChanges,
+		LastHandledForceReconcile: in.Status.LastHandledForceReconcile,
+		Conditions:                in.Status.Conditions,
+	}
+	for _, ns := range in.Status.Namespaces {
+		dst.Status.Namespaces = append(dst.Status.Namespaces, v1alpha1.NamespaceStatus(ns))
+	}
+	for _, f := range in.Status.ResourceFailures {
+		dst.Status.ResourceFailures = append(dst.Status.ResourceFailures, v1alpha1.ResourceFailure(f))
+	}
+	for _, c := range in.Status.FieldConflicts {
+		dst.Status.FieldConflicts = append(dst.Status.FieldConflicts, v1alpha1.FieldConflict(c))
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub version to this NamespaceClass.
+func (dst *NamespaceClass) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.NamespaceClass)
+	in := src.DeepCopy()
+	dst.ObjectMeta = in.ObjectMeta
+
+	dst.Spec = NamespaceClassSpec{
+		Resources:         in.Spec.Resources,
+		ResourcesYAML:     in.Spec.ResourcesYAML,
+		Extends:           in.Spec.Extends,
+		NamespaceSelector: in.Spec.NamespaceSelector,
+		ValuesRef:         (*ValuesRef)(in.Spec.ValuesRef),
+		CleanupPolicy:     CleanupPolicy(in.Spec.CleanupPolicy),
+	}
+	for _, ref := range in.Spec.ResourceRefs {
+		dst.Spec.ResourceRefs = append(dst.Spec.ResourceRefs, ResourceRef(ref))
+	}
+	for _, ref := range in.Spec.SecretRefs {
+		dst.Spec.SecretRefs = append(dst.Spec.SecretRefs, SecretRef(ref))
+	}
+
+	dst.Status = NamespaceClassStatus{
+		Phase:                     ClassPhase(in.Status.Phase),
+		LastAppliedResources:      in.Status.LastAppliedResources,
+		ObservedGeneration:        in.Status.ObservedGeneration,
+		LastReconcileTime:         in.Status.LastReconcileTime,
+		AppliedResourceCount:      in.Status.AppliedResourceCount,
+		BoundNamespaces:           in.Status.BoundNamespaces,
+		ClusterScopedResources:    in.Status.ClusterScopedResources,
+		PendingChanges:            in.Status.PendingChanges,
+		LastHandledForceReconcile: in.Status.LastHandledForceReconcile,
+		Conditions:                in.Status.Conditions,
+	}
+	for _, ns := range in.Status.Namespaces {
+		dst.Status.Namespaces = append(dst.Status.Namespaces, NamespaceStatus(ns))
+	}
+	for _, f := range in.Status.ResourceFailures {
+		dst.Status.ResourceFailures = append(dst.Status.ResourceFailures, ResourceFailure(f))
+	}
+	for _, c := range in.Status.FieldConflicts {
+		dst.Status.FieldConflicts = append(dst.Status.FieldConflicts, FieldConflict(c))
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+var _ = Describe("NamespaceClass conversion", func() {
+	now := metav1.NewTime(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	// populated sets every field of the class so that a field the conversion
+	// forgets shows up as a difference after a round trip.
+	populated := func() *NamespaceClass {
+		return &NamespaceClass{
+			TypeMeta: metav1.TypeMeta{APIVersion: GroupVersion.String(), Kind: "NamespaceClass"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "web",
+				Generation:  3,
+				Labels:      map[string]string{"team": "web"},
+				Annotations: map[string]string{"note": "converted"},
+			},
+			Spec: NamespaceClassSpec{
+				Resources: []runtime.RawExtension{
+					{Raw: []byte(`{"apiVersion":"v1","kind":"ServiceAccount","metadata":{"name":"deployer"}}`)},
+				},
+				ResourcesYAML: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: extra\n",
+				ResourceRefs:  []ResourceRef{{Name: "policies", Key: "netpol.yaml"}},
+				SecretRefs:    []SecretRef{{Namespace: "infra", Name: "pull", TargetName: "registry"}},
+				ValuesRef:     &ValuesRef{Name: "web-values"},
+				Extends:       "base",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "web"},
+				},
+				CleanupPolicy: CleanupPolicyOrphan,
+			},
+			Status: NamespaceClassStatus{
+				Phase: ClassPhaseReady,
+				LastAppliedResources: []runtime.RawExtension{
+					{Raw: []byte(`{"apiVersion":"v1","kind":"ServiceAccount","metadata":{"name":"deployer"}}`)},
+				},
+				ObservedGeneration:     2,
+				LastReconcileTime:      now,
+				AppliedResourceCount:   1,
+				BoundNamespaces:        []string{"web-a", "web-b"},
+				ClusterScopedResources: []string{"ClusterRole.rbac.authorization.k8s.io/web"},
+				PendingChanges:         []string{"web-a: create ServiceAccount/deployer"},
+				Namespaces: []NamespaceStatus{{
+					Name:             "web-a",
+					Ready:            false,
+					Message:          "boom",
+					LastApplied:      &now,
+					SkippedResources: []string{"ConfigMap/settings"},
+				}},
+				ResourceFailures: []ResourceFailure{{
+					Namespace:  "web-a",
+					Resource:   "ServiceAccount/deployer",
+					Attempts:   2,
+					Generation: 3,
+					Failed:     true,
+					Message:    "forbidden",
+				}},
+				FieldConflicts: []FieldConflict{{
+					Namespace: "web-b",
+					Resource:  "ConfigMap/settings",
+					Field:     ".data.foo",
+					Manager:   "kubectl-edit",
+				}},
+				LastHandledForceReconcile: "1",
+				Conditions: []metav1.Condition{{
+					Type:               v1alpha1.ConditionReady,
+					Status:             metav1.ConditionFalse,
+					Reason:             v1alpha1.ReasonApplyFailed,
+					Message:            "1 of 2 namespaces failed",
+					LastTransitionTime: now,
+				}},
+			},
+		}
+	}
+
+	It("should keep every field when converting to the hub and back", func() {
+		original := populated()
+
+		hub := &v1alpha1.NamespaceClass{}
+		Expect(original.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Name).To(Equal("web"))
+		Expect(hub.Spec.SecretRefs).To(Equal([]v1alpha1.SecretRef{{Namespace: "infra", Name: "pull", TargetName: "registry"}}))
+
+		converted := &NamespaceClass{TypeMeta: original.TypeMeta}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted).To(Equal(original))
+	})
+
+	It("should keep every field when converting from the hub and back", func() {
+		spoke := populated()
+		hub := &v1alpha1.NamespaceClass{}
+		Expect(spoke.ConvertTo(hub)).To(Succeed())
+		hub.TypeMeta = metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "NamespaceClass"}
+		original := hub.DeepCopy()
+
+		converted := &NamespaceClass{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		back := &v1alpha1.NamespaceClass{TypeMeta: original.TypeMeta}
+		Expect(converted.ConvertTo(back)).To(Succeed())
+		Expect(back).To(Equal(original))
+	})
+
+	It("should serialize spec and status the same way in both versions", func() {
+		spoke := populated()
+		hub := &v1alpha1.NamespaceClass{}
+		Expect(spoke.ConvertTo(hub)).To(Succeed())
+
+		for _, pair := range [][2]any{{spoke.Spec, hub.Spec}, {spoke.Status, hub.Status}} {
+			want, err := json.Marshal(pair[0])
+			Expect(err).NotTo(HaveOccurred())
+			got, err := json.Marshal(pair[1])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(MatchJSON(want))
+		}
+	})
+
+	It("should not share memory between the versions", func() {
+		spoke := populated()
+		hub := &v1alpha1.NamespaceClass{}
+		Expect(spoke.ConvertTo(hub)).To(Succeed())
+
+		hub.Spec.Resources[0].Raw[0] = ' '
+		hub.Spec.NamespaceSelector.MatchLabels["tier"] = "api"
+		hub.Labels["team"] = "api"
+		hub.Status.Namespaces[0].LastApplied.Time = time.Time{}
+
+		Expect(spoke).To(Equal(populated()))
+	})
+
+	It("should keep empty lists nil", func() {
+		hub := &v1alpha1.NamespaceClass{ObjectMeta: metav1.ObjectMeta{Name: "empty"}}
+		converted := &NamespaceClass{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted.Spec.ResourceRefs).To(BeNil())
+		Expect(converted.Status.Namespaces).To(BeNil())
+		Expect(converted.Status.FieldConflicts).To(BeNil())
+	})
+})
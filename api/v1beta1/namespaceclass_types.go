@@ -0,0 +1,317 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NamespaceClassSpec defines the desired state of NamespaceClass
+type NamespaceClassSpec struct {
+	// Resources is a list of raw Kubernetes resources (e.g. NetworkPolicy, ServiceAccount)
+	// that should be created in any namespace using this class.
+	Resources []runtime.RawExtension `json:"resources,omitempty"`
+
+	// ResourcesYAML holds further resources as a YAML or JSON stream, with
+	// documents separated by "---", for classes that are written by hand. They
+	// are applied after Resources as if they were listed there.
+	// +optional
+	ResourcesYAML string `json:"resourcesYAML,omitempty"`
+
+	// ResourceRefs point at ConfigMaps in the operator's namespace whose
+	// contents are YAML or JSON manifests that are injected in addition to
+	// Resources.
+	// +optional
+	ResourceRefs []ResourceRef `json:"resourceRefs,omitempty"`
+
+	// ValuesRef names a ConfigMap in the operator's namespace whose keys are
+	// available to resource templates as {{ .Values.<key> }}, so that the same
+	// class can be parameterized per environment by swapping the ConfigMap.
+	// Inherited resources and those of additional classes are rendered with
+	// the values of this class; their own classes' ValuesRef is not used.
+	// +optional
+	ValuesRef *ValuesRef `json:"valuesRef,omitempty"`
+
+	// SecretRefs name existing Secrets that are copied into every namespace
+	// of the class, e.g. a registry pull Secret. Copies are updated when their
+	// source changes and are cleaned up like any other resource of the class.
+	// +optional
+	SecretRefs []SecretRef `json:"secretRefs,omitempty"`
+
+	// Extends names a parent class whose resources are applied as well. A
+	// resource of this class replaces a parent resource of the same kind and
+	// name.
+	// +optional
+	Extends string `json:"extends,omitempty"`
+
+	// NamespaceSelector binds the class to every namespace without a class
+	// label whose labels match, in addition to the namespaces that name the
+	// class in their label. A namespace matched by several classes uses the
+	// first of them by name.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// CleanupPolicy decides what happens to the resources of the class when
+	// they are no longer wanted in a namespace. The namespace webhook stamps
+	// the matching cleanup annotations onto namespaces that adopt the class,
+	// unless they set them themselves.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	CleanupPolicy CleanupPolicy `json:"cleanupPolicy,omitempty"`
+}
+
+// CleanupPolicy is the cleanup behavior a class asks of its namespaces.
+type CleanupPolicy string
+
+const (
+	// CleanupPolicyDelete deletes injected resources with the class, when a
+	// namespace leaves the class, and when they are dropped from the class.
+	CleanupPolicyDelete CleanupPolicy = "Delete"
+	// CleanupPolicyOrphan leaves injected resources in place.
+	CleanupPolicyOrphan CleanupPolicy = "Orphan"
+)
+
+// ResourceRef selects manifests stored in a ConfigMap.
+type ResourceRef struct {
+	// Name of the ConfigMap in the operator's namespace.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key of the ConfigMap to read. Every key is read, in order, when empty.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// ValuesRef selects the ConfigMap that holds the template values of a class.
+type ValuesRef struct {
+	// Name of the ConfigMap in the operator's namespace.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// SecretRef selects a Secret to copy into the namespaces of a class.
+type SecretRef struct {
+	// Namespace of the source Secret. Defaults to the operator's namespace,
+	// which is the only namespace Secrets are copied from, so that editing a
+	// class cannot read the Secrets of other namespaces.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the source Secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// TargetName is the name of the copy. Defaults to Name.
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
+}
+
+// ClassPhase summarizes the state of a NamespaceClass.
+type ClassPhase string
+
+const (
+	// ClassPhasePending is the phase of a class that was not reconciled yet.
+	ClassPhasePending ClassPhase = "Pending"
+	// ClassPhaseApplying is the phase of a class whose resources are being
+	// applied to its namespaces.
+	ClassPhaseApplying ClassPhase = "Applying"
+	// ClassPhaseReady is the phase of a class that was applied to every one of
+	// its namespaces.
+	ClassPhaseReady ClassPhase = "Ready"
+	// ClassPhaseError is the phase of a class that could not be applied to at
+	// least one of its namespaces.
+	ClassPhaseError ClassPhase = "Error"
+)
+
+// NamespaceClassStatus defines the observed state of NamespaceClass
+type NamespaceClassStatus struct {
+	// Phase is a summary of the state of the class for quick inspection. The
+	// conditions hold the details.
+	// +kubebuilder:validation:Enum=Pending;Applying;Ready;Error
+	// +optional
+	Phase ClassPhase `json:"phase,omitempty"`
+
+	// LastAppliedResources are the resources of the spec as of the last
+	// reconcile that applied them to every namespace without errors, so that
+	// the ones removed since are deleted as obsolete.
+	// +optional
+	LastAppliedResources []runtime.RawExtension `json:"lastAppliedResources,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that was last applied
+	// to every namespace without errors. It lags behind metadata.generation
+	// while a reconcile keeps failing.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastReconcileTime is when a reconcile last changed the status. Reconciles
+	// that find everything in sync do not write the status and leave it as is.
+	// +optional
+	LastReconcileTime metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// AppliedResourceCount is the number of resources, including inherited and
+	// referenced ones, the last reconcile applied to each namespace.
+	// +optional
+	AppliedResourceCount int `json:"appliedResourceCount,omitempty"`
+
+	// BoundNamespaces are the names of the namespaces that use this class,
+	// sorted. Namespaces that are being deleted are not listed.
+	// +optional
+	BoundNamespaces []string `json:"boundNamespaces,omitempty"`
+
+	// Namespaces reports, per bound namespace, whether the last reconcile
+	// applied the class to it.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Namespaces []NamespaceStatus `json:"namespaces,omitempty"`
+
+	// ClusterScopedResources lists, as "Kind.group/name", the cluster-scoped
+	// resources the class created. They are shared by every namespace of the
+	// class and therefore created only once.
+	// +optional
+	ClusterScopedResources []string `json:"clusterScopedResources,omitempty"`
+
+	// PendingChanges lists, as "namespace: action Kind/name", the changes the
+	// last reconcile would have made when the operator runs in dry-run mode.
+	// +optional
+	PendingChanges []string `json:"pendingChanges,omitempty"`
+
+	// ResourceFailures track the resources that failed to apply to a namespace
+	// in consecutive attempts. They are only maintained when the operator runs
+	// with a retry limit.
+	// +optional
+	ResourceFailures []ResourceFailure `json:"resourceFailures,omitempty"`
+
+	// FieldConflicts list the fields of injected resources that server-side
+	// apply could not take over because another field manager, e.g. a user's
+	// kubectl edit, owns them. They are only reported when the operator runs
+	// with the server-side apply strategy.
+	// +optional
+	FieldConflicts []FieldConflict `json:"fieldConflicts,omitempty"`
+
+	// LastHandledForceReconcile is the value of the force-reconcile annotation
+	// that was last handled, so that each new value forces a single full
+	// re-apply.
+	// +optional
+	LastHandledForceReconcile string `json:"lastHandledForceReconcile,omitempty"`
+
+	// Conditions describe the outcome of the last reconcile.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NamespaceStatus is the outcome of applying a class to one namespace.
+type NamespaceStatus struct {
+	// Name of the namespace.
+	Name string `json:"name"`
+
+	// Ready is true when every resource was applied to the namespace.
+	Ready bool `json:"ready"`
+
+	// Message describes why the namespace is not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastApplied is when resources were last changed in the namespace.
+	// +optional
+	LastApplied *metav1.Time `json:"lastApplied,omitempty"`
+
+	// SkippedResources lists, as "Kind/name", the objects that already existed
+	// in the namespace without belonging to the class when the namespace was
+	// provisioned, and were therefore left as they are. They are taken over,
+	// and dropped from the list, the next time the class is applied to the
+	// namespace.
+	// +optional
+	SkippedResources []string `json:"skippedResources,omitempty"`
+}
+
+// ResourceFailure counts the failed attempts to apply one resource of a class
+// to one namespace.
+type ResourceFailure struct {
+	// Namespace the resource is applied to.
+	Namespace string `json:"namespace"`
+
+	// Resource is the failing resource as "Kind/name".
+	Resource string `json:"resource"`
+
+	// Attempts is the number of consecutive failed attempts.
+	Attempts int `json:"attempts"`
+
+	// Generation of the class the attempts were made at. Attempts are counted
+	// afresh once the class changes.
+	Generation int64 `json:"generation"`
+
+	// Failed is true once the retry limit was reached. The resource is then no
+	// longer applied until the class changes.
+	// +optional
+	Failed bool `json:"failed,omitempty"`
+
+	// Message is the error of the last attempt.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// FieldConflict is a field of an injected resource that is owned by another
+// field manager.
+type FieldConflict struct {
+	// Namespace of the resource.
+	Namespace string `json:"namespace"`
+
+	// Resource is the conflicting resource as "Kind/name".
+	Resource string `json:"resource"`
+
+	// Field is the path of the conflicting field, e.g. ".data.foo".
+	Field string `json:"field"`
+
+	// Manager is the field manager that owns the field.
+	// +optional
+	Manager string `json:"manager,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Resources",type=integer,JSONPath=`.status.appliedResourceCount`
+// +kubebuilder:printcolumn:name="Observed",type=integer,JSONPath=`.status.observedGeneration`,priority=1
+// +kubebuilder:printcolumn:name="Generation",type=integer,JSONPath=`.metadata.generation`,priority=1
+// +kubebuilder:printcolumn:name="Last Reconcile",type=date,JSONPath=`.status.lastReconcileTime`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NamespaceClass is the Schema for the namespaceclasses API
+type NamespaceClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NamespaceClassSpec   `json:"spec,omitempty"`
+	Status NamespaceClassStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceClassList contains a list of NamespaceClass
+type NamespaceClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespaceClass `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespaceClass{}, &NamespaceClassList{})
+}
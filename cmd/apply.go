@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/internal/apply"
+)
+
+// runApply implements the "apply" subcommand, which bulk-applies the manifests
+// found in a directory and prints one result line per file.
+func runApply(args []string) int {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	var dir string
+	fs.StringVar(&dir, "dir", "", "Directory containing NamespaceClass and Namespace manifests.")
+	_ = fs.Parse(args)
+
+	if dir == "" {
+		fmt.Fprintln(os.Stderr, "apply: --dir is required")
+		return 2
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "apply: unable to load kubeconfig: %v\n", err)
+		return 1
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "apply: unable to create client: %v\n", err)
+		return 1
+	}
+
+	results, err := apply.Directory(context.Background(), c, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "apply: %v\n", err)
+		return 1
+	}
+
+	code := 0
+	for _, res := range results {
+		if res.Err != nil {
+			fmt.Printf("%s: failed: %v\n", res.Path, res.Err)
+			code = 1
+			continue
+		}
+		fmt.Printf("%s: applied %d object(s)\n", res.Path, res.Applied)
+	}
+	return code
+}
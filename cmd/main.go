@@ -20,15 +20,23 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/managedfields"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/openapi"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -36,7 +44,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	namespacev1alpha1 "github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	namespacev1beta1 "github.com/kardolus/namespaceclass-operator/api/v1beta1"
 	"github.com/kardolus/namespaceclass-operator/internal/controller"
+	webhookcorev1 "github.com/kardolus/namespaceclass-operator/internal/webhook/v1"
+	webhooknamespacev1alpha1 "github.com/kardolus/namespaceclass-operator/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,15 +60,56 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(namespacev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(namespacev1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "apply" {
+		os.Exit(runApply(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "render" {
+		os.Exit(runRender(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "orphans" {
+		os.Exit(runOrphans(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var defaultNamespaceClass string
+	var defaultClassExcludedNamespaces string
+	var unresolvableKindPolicy string
+	var watchedKinds string
+	var applyStrategy string
+	var obsoleteCleanup string
+	var resyncPeriod time.Duration
+	var eventCooldown time.Duration
+	var applyTimeout time.Duration
+	var applyQPS float64
+	var applyBurst int
+	var maxFinalizeAttempts int
+	var forceFinalize bool
+	var drainTimeout time.Duration
+	var maxConcurrentReconciles int
+	var namespacePageSize int64
+	var maxResourcesPerClass int
+	var maxRetries int
+	var namespaceAllowlist string
+	var namespaceDenylist string
+	var dryRun bool
+	var namespaceFinalizer bool
+	var adoptExisting bool
+	var preventOrphaning bool
+	var allowedKinds string
+	var classLabelKey string
+	var validateSchemas bool
+	var checkReferences bool
+	var staleReconcileThreshold time.Duration
+	var targetNamespaces string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -69,6 +121,95 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&defaultNamespaceClass, "default-namespace-class", "",
+		"NamespaceClass applied to namespaces without a class label. Leave empty to disable defaulting.")
+	flag.StringVar(&defaultClassExcludedNamespaces, "default-class-excluded-namespaces",
+		"kube-system,kube-public,kube-node-lease",
+		"Comma-separated namespaces that never receive the default NamespaceClass, nor a NamespaceClass by its "+
+			"namespaceSelector. "+
+			"The operator's own namespace (POD_NAMESPACE) is always excluded.")
+	flag.StringVar(&unresolvableKindPolicy, "unresolvable-kind-policy", string(controller.UnresolvableKindFailOpen),
+		"What to do with resources whose kind the cluster does not serve: 'fail-open' skips them with an event "+
+			"until the kind is available, "+
+			"'fail-closed' applies nothing to the namespace and retries until the kind is available.")
+	flag.StringVar(&watchedKinds, "watched-kinds", "ConfigMap,Secret,Service,ServiceAccount",
+		"Comma-separated injected kinds (Kind or group/version/Kind) that are re-injected when deleted. "+
+			"The operator needs RBAC to watch every listed kind.")
+	flag.StringVar(&applyStrategy, "apply-strategy", string(controller.ApplyStrategyUpdate),
+		"How existing resources are updated: 'update' replaces them, 'server-side' uses server-side apply "+
+			"with field manager "+controller.FieldManager+" and reports conflicts as events instead of overwriting.")
+	flag.StringVar(&obsoleteCleanup, "obsolete-cleanup", string(controller.ObsoleteCleanupStatus),
+		"How resources removed from a class are found in namespaces that opted into their cleanup: 'status' "+
+			"uses the last applied resources in the class status, 'labels' lists the objects carrying the ownership "+
+			"label of the class and does not depend on the status.")
+	flag.DurationVar(&resyncPeriod, "resync-period", 0,
+		"How often every NamespaceClass is re-applied to correct out-of-band changes of injected resources. "+
+			"0 disables periodic resyncs.")
+	flag.DurationVar(&eventCooldown, "event-cooldown", 10*time.Minute,
+		"How long a warning Event about a persisting condition, such as a namespace referencing a missing "+
+			"NamespaceClass, is not repeated for the same namespace. 0 emits it on every reconcile.")
+	flag.DurationVar(&applyTimeout, "apply-timeout", 0,
+		"If set, every create, update, patch or delete of an injected resource fails after this long, "+
+			"and is retried with backoff, so that a slow API server does not block a reconcile worker. 0 disables it.")
+	flag.Float64Var(&applyQPS, "apply-qps", 0,
+		"If set, each NamespaceClass is applied to at most this many namespaces per second, so that a class "+
+			"bound to many namespaces does not flood the API server. 0 disables the limit.")
+	flag.IntVar(&applyBurst, "apply-burst", 10,
+		"How many namespaces a NamespaceClass may be applied to at once before --apply-qps paces them.")
+	flag.IntVar(&maxFinalizeAttempts, "max-finalize-attempts", 0,
+		"If set, a CleanupFailed event is emitted on a deleted NamespaceClass once cleaning up its resources "+
+			"failed this many times. 0 keeps retrying without one.")
+	flag.BoolVar(&forceFinalize, "force-finalize", false,
+		"If set, the finalizer of a deleted NamespaceClass is removed once --max-finalize-attempts is reached, "+
+			"so that the class is not stuck in Terminating. Resources that could not be cleaned up are left behind.")
+	flag.DurationVar(&drainTimeout, "drain-timeout", 0,
+		"If set, applies to a namespace that are in flight on shutdown may take this long to finish, so that no "+
+			"namespace is left with part of its resources. No further namespaces are started meanwhile. "+
+			"Keep it below the terminationGracePeriodSeconds of the pod. 0 stops applies right away.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"How many reconcile requests, of NamespaceClasses or of single namespaces of a class, are handled in parallel.")
+	flag.Int64Var(&namespacePageSize, "namespace-page-size", 0,
+		"If set, NamespaceClasses list their namespaces from the API server in pages of this size instead of "+
+			"from the cache at once, which bounds memory on clusters with very many namespaces. 0 disables paging.")
+	flag.IntVar(&maxResourcesPerClass, "max-resources-per-class", 0,
+		"The most resources a NamespaceClass may define, including inherited and referenced ones. "+
+			"Larger classes are rejected by the webhook or refused by the controller. Zero means no limit.")
+	flag.IntVar(&maxRetries, "max-retries", 0,
+		"How often a resource that fails to apply to a namespace is retried, with exponential backoff, before "+
+			"it is marked failed in the NamespaceClass status and skipped until the class changes. Zero retries forever.")
+	flag.StringVar(&namespaceAllowlist, "namespace-allowlist", "",
+		"Comma-separated glob patterns, e.g. 'team-*'. If set, only matching namespaces are managed.")
+	flag.StringVar(&namespaceDenylist, "namespace-denylist", strings.Join(controller.DefaultNamespaceDenylist, ","),
+		"Comma-separated glob patterns of namespaces that are never managed, even if they match the allowlist.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"If set, writes of injected resources are sent as server-side dry runs and the planned changes are "+
+			"reported as events and in the pendingChanges status of each NamespaceClass instead of being persisted.")
+	flag.BoolVar(&namespaceFinalizer, "namespace-finalizer", false,
+		"If set, a finalizer is added to every namespace a NamespaceClass was applied to and removed once the "+
+			"namespace loses its class label or is deleted.")
+	flag.StringVar(&allowedKinds, "allowed-kinds", "ConfigMap,Secret,Service,ServiceAccount",
+		"Comma-separated kinds (Kind of any group, or group/Kind) a NamespaceClass may inject; other resources are "+
+			"skipped with an event. The operator needs RBAC for every allowed kind. '*' allows every kind.")
+	flag.BoolVar(&adoptExisting, "adopt-existing", false,
+		"If set, resources that already exist when a namespace is provisioned are updated to match the "+
+			"NamespaceClass and labeled as owned by it, unless another class owns them.")
+	flag.BoolVar(&preventOrphaning, "prevent-orphaning", false,
+		"If set, the NamespaceClass webhook rejects the deletion of a class that namespaces without the cleanup "+
+			"annotation still use, unless the class is annotated with namespaceclass.kardolus.dev/allow-orphan=true.")
+	flag.StringVar(&classLabelKey, "class-label-key", controller.NamespaceClassNameKey,
+		"Namespace label that names the NamespaceClass of a namespace.")
+	flag.BoolVar(&validateSchemas, "validate-schemas", false,
+		"If set, the resources of every NamespaceClass are validated against the OpenAPI schemas served by the "+
+			"API server at startup and problems are reported in the ResourcesValid condition of the class.")
+	flag.BoolVar(&checkReferences, "check-references", false,
+		"If set, the ServiceAccounts bound by the RoleBindings of every NamespaceClass must be defined by the class "+
+			"too; dangling subjects are reported in the ReferencesValid condition of the class and with an event.")
+	flag.DurationVar(&staleReconcileThreshold, "stale-reconcile-threshold", 0,
+		"How long the reconciles of a NamespaceClass may keep failing without a success before the readiness probe "+
+			"reports unhealthy. An unready manager also takes the webhooks offline. 0 disables the check.")
+	flag.StringVar(&targetNamespaces, "target-namespaces", "",
+		"Comma-separated namespaces that embedded resources may be redirected to with the "+
+			controller.NamespaceClassTargetNamespaceKey+" annotation. Leave empty to disallow redirection.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -77,6 +218,92 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	switch controller.UnresolvableKindPolicy(unresolvableKindPolicy) {
+	case controller.UnresolvableKindFailOpen, controller.UnresolvableKindFailClosed:
+	default:
+		setupLog.Error(nil, "invalid --unresolvable-kind-policy", "value", unresolvableKindPolicy)
+		os.Exit(1)
+	}
+
+	if resyncPeriod < 0 {
+		setupLog.Error(nil, "invalid --resync-period, must not be negative", "value", resyncPeriod)
+		os.Exit(1)
+	}
+
+	if maxConcurrentReconciles < 1 {
+		setupLog.Error(nil, "invalid --max-concurrent-reconciles, must be at least 1", "value", maxConcurrentReconciles)
+		os.Exit(1)
+	}
+
+	if namespacePageSize < 0 {
+		setupLog.Error(nil, "invalid --namespace-page-size, must not be negative", "value", namespacePageSize)
+		os.Exit(1)
+	}
+
+	if maxResourcesPerClass < 0 {
+		setupLog.Error(nil, "invalid --max-resources-per-class, must not be negative", "value", maxResourcesPerClass)
+		os.Exit(1)
+	}
+
+	if eventCooldown < 0 {
+		setupLog.Error(nil, "invalid --event-cooldown, must not be negative", "value", eventCooldown)
+		os.Exit(1)
+	}
+
+	if applyTimeout < 0 {
+		setupLog.Error(nil, "invalid --apply-timeout, must not be negative", "value", applyTimeout)
+		os.Exit(1)
+	}
+
+	if applyQPS < 0 {
+		setupLog.Error(nil, "invalid --apply-qps, must not be negative", "value", applyQPS)
+		os.Exit(1)
+	}
+
+	if applyBurst < 0 {
+		setupLog.Error(nil, "invalid --apply-burst, must not be negative", "value", applyBurst)
+		os.Exit(1)
+	}
+
+	if maxFinalizeAttempts < 0 {
+		setupLog.Error(nil, "invalid --max-finalize-attempts, must not be negative", "value", maxFinalizeAttempts)
+		os.Exit(1)
+	}
+
+	if drainTimeout < 0 {
+		setupLog.Error(nil, "invalid --drain-timeout, must not be negative", "value", drainTimeout)
+		os.Exit(1)
+	}
+
+	if maxRetries < 0 {
+		setupLog.Error(nil, "invalid --max-retries, must not be negative", "value", maxRetries)
+		os.Exit(1)
+	}
+
+	for name, patterns := range map[string]string{
+		"namespace-allowlist": namespaceAllowlist,
+		"namespace-denylist":  namespaceDenylist,
+	} {
+		if err := controller.ValidateNamespacePatterns(splitList(patterns)); err != nil {
+			setupLog.Error(err, "invalid --"+name)
+			os.Exit(1)
+		}
+	}
+
+	switch controller.ApplyStrategy(applyStrategy) {
+	case controller.ApplyStrategyUpdate, controller.ApplyStrategyServerSide:
+	default:
+		setupLog.Error(nil, "invalid --apply-strategy", "value", applyStrategy)
+		os.Exit(1)
+	}
+
+	switch controller.ObsoleteCleanup(obsoleteCleanup) {
+	case controller.ObsoleteCleanupStatus, controller.ObsoleteCleanupLabels:
+	default:
+		setupLog.Error(nil, "invalid --obsolete-cleanup", "value", obsoleteCleanup)
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -120,13 +347,27 @@ func main() {
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
 
+	var gracefulShutdownTimeout *time.Duration
+	if drainTimeout > 0 {
+		// The manager must not give up on the controller before its applies drained
+		timeout := drainTimeout + 5*time.Second
+		gracefulShutdownTimeout = &timeout
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		Metrics:                metricsServerOptions,
-		WebhookServer:          webhookServer,
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "6286e840.kardolus.dev",
+		Scheme:                  scheme,
+		Metrics:                 metricsServerOptions,
+		WebhookServer:           webhookServer,
+		HealthProbeBindAddress:  probeAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        "6286e840.kardolus.dev",
+		GracefulShutdownTimeout: gracefulShutdownTimeout,
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				// Secrets are read on demand, so that the cache does not hold every Secret of the cluster
+				DisableFor: []client.Object{&corev1.Secret{}},
+			},
+		},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -144,15 +385,110 @@ func main() {
 		os.Exit(1)
 	}
 
+	kinds, err := controller.ParseKinds(watchedKinds)
+	if err != nil {
+		setupLog.Error(err, "invalid --watched-kinds")
+		os.Exit(1)
+	}
+
+	allowed, err := controller.ParseAllowedKinds(allowedKinds)
+	if err != nil {
+		setupLog.Error(err, "invalid --allowed-kinds")
+		os.Exit(1)
+	}
+
+	if errs := validation.IsQualifiedName(classLabelKey); len(errs) > 0 {
+		setupLog.Error(nil, "invalid --class-label-key", "value", classLabelKey, "reason", strings.Join(errs, "; "))
+		os.Exit(1)
+	}
+
+	var typeConverter managedfields.TypeConverter
+	if validateSchemas {
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create discovery client")
+			os.Exit(1)
+		}
+		typeConverter, err = openapi.NewTypeConverter(discoveryClient.OpenAPIV3(), false)
+		if err != nil {
+			setupLog.Error(err, "unable to load OpenAPI schemas")
+			os.Exit(1)
+		}
+	}
+
+	excludedNamespaces := splitList(defaultClassExcludedNamespaces)
+	podNamespace := os.Getenv("POD_NAMESPACE")
+	if podNamespace != "" {
+		excludedNamespaces = append(excludedNamespaces, podNamespace)
+	}
+
+	var reconcileHealth *controller.ReconcileHealth
+	if staleReconcileThreshold > 0 {
+		reconcileHealth = controller.NewReconcileHealth(staleReconcileThreshold)
+	}
+
 	if err = (&controller.NamespaceClassReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		DefaultClass:            defaultNamespaceClass,
+		ExcludedNamespaces:      excludedNamespaces,
+		UnresolvableKindPolicy:  controller.UnresolvableKindPolicy(unresolvableKindPolicy),
+		WatchedKinds:            kinds,
+		ApplyStrategy:           controller.ApplyStrategy(applyStrategy),
+		ObsoleteCleanup:         controller.ObsoleteCleanup(obsoleteCleanup),
+		ResyncPeriod:            resyncPeriod,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		ListPageSize:            namespacePageSize,
+		APIReader:               mgr.GetAPIReader(),
+		NamespaceAllowlist:      splitList(namespaceAllowlist),
+		NamespaceDenylist:       splitList(namespaceDenylist),
+		MaxResourcesPerClass:    maxResourcesPerClass,
+		MaxRetries:              maxRetries,
+		EventCooldown:           eventCooldown,
+		ApplyTimeout:            applyTimeout,
+		ApplyQPS:                applyQPS,
+		ApplyBurst:              applyBurst,
+		MaxFinalizeAttempts:     maxFinalizeAttempts,
+		ForceFinalize:           forceFinalize,
+		DrainTimeout:            drainTimeout,
+		DryRun:                  dryRun,
+		NamespaceFinalizer:      namespaceFinalizer,
+		AdoptExisting:           adoptExisting,
+		AllowedKinds:            allowed,
+		OperatorNamespace:       podNamespace,
+		ClassLabelKey:           classLabelKey,
+		TypeConverter:           typeConverter,
+		CheckReferences:         checkReferences,
+		Health:                  reconcileHealth,
+		TargetNamespaces:        splitList(targetNamespaces),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NamespaceClass")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooknamespacev1alpha1.SetupNamespaceClassWebhookWithManager(
+			mgr, maxResourcesPerClass, classLabelKey, preventOrphaning,
+			defaultNamespaceClass, excludedNamespaces); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NamespaceClass")
+			os.Exit(1)
+		}
+		if err = webhookcorev1.SetupNamespaceWebhookWithManager(mgr, classLabelKey); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Namespace")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	podName := os.Getenv("POD_NAME")
+	if podName == "" {
+		podName, _ = os.Hostname()
+	}
+	if err := mgr.Add(controller.NewLeaderGauge(podName)); err != nil {
+		setupLog.Error(err, "unable to set up leader metric")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -161,6 +497,12 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if reconcileHealth != nil {
+		if err := mgr.AddReadyzCheck("reconcile", reconcileHealth.Check); err != nil {
+			setupLog.Error(err, "unable to set up reconcile check")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -168,3 +510,14 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// splitList parses a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
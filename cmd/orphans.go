@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+// runOrphans implements the "orphans" subcommand, which prints one line per
+// resource left behind by a deleted NamespaceClass and optionally deletes them.
+func runOrphans(args []string) int {
+	fs := flag.NewFlagSet("orphans", flag.ExitOnError)
+	var kinds string
+	var remove bool
+	fs.StringVar(&kinds, "kinds", "ConfigMap,Secret,Service,ServiceAccount",
+		"Comma-separated kinds to scan, as bare core kinds or group/version/Kind.")
+	fs.BoolVar(&remove, "delete", false, "Delete the orphaned resources after listing them.")
+	_ = fs.Parse(args)
+
+	gvks, err := controller.ParseKinds(kinds)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "orphans: invalid --kinds: %v\n", err)
+		return 2
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "orphans: unable to load kubeconfig: %v\n", err)
+		return 1
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "orphans: unable to create client: %v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	orphans, err := controller.FindOrphanedResources(ctx, c, gvks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "orphans: %v\n", err)
+		return 1
+	}
+
+	code := 0
+	for _, obj := range orphans {
+		ref := obj.GetKind() + "/" + obj.GetName()
+		if obj.GetNamespace() != "" {
+			ref = obj.GetNamespace() + ": " + ref
+		}
+		class := obj.GetLabels()[controller.NamespaceClassOwnedByKey]
+		if !remove {
+			fmt.Printf("%s (class %s)\n", ref, class)
+			continue
+		}
+		if err := c.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			fmt.Printf("%s (class %s): delete failed: %v\n", ref, class, err)
+			code = 1
+			continue
+		}
+		fmt.Printf("%s (class %s): deleted\n", ref, class)
+	}
+	return code
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kardolus/namespaceclass-operator/internal/render"
+)
+
+// runRender implements the "render" subcommand, which prints the resources a
+// class injects into a namespace, resolved from manifests on disk without
+// touching a cluster.
+func runRender(args []string) int {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	var opts render.Options
+	var files string
+	fs.StringVar(&files, "file", "",
+		"Comma-separated manifest files or directories holding the class, its parents, the ConfigMaps of "+
+			"its resourceRefs and, optionally, the Namespace.")
+	fs.StringVar(&opts.Class, "class", "", "Name of the NamespaceClass to render.")
+	fs.StringVar(&opts.Namespace, "namespace", "", "Name of the namespace to render the class for.")
+	fs.StringVar(&opts.OperatorNamespace, "operator-namespace", "namespaceclass-operator-system",
+		"Namespace of the ConfigMaps that resourceRefs point at.")
+	_ = fs.Parse(args)
+
+	if files == "" || opts.Class == "" || opts.Namespace == "" {
+		fmt.Fprintln(os.Stderr, "render: --file, --class and --namespace are required")
+		return 2
+	}
+
+	objs, err := render.Load(scheme, strings.Split(files, ",")...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "render: %v\n", err)
+		return 1
+	}
+	resources, warnings, err := render.Resources(context.Background(), scheme, objs, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "render: %v\n", err)
+		return 1
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "render: %s\n", warning)
+	}
+	if err := render.Write(os.Stdout, resources); err != nil {
+		fmt.Fprintf(os.Stderr, "render: %v\n", err)
+		return 1
+	}
+	return 0
+}
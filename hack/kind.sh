@@ -47,6 +47,17 @@ EOF
     # Wait for the local-path-provisioner to stand up ...
     LABEL=app=local-path-provisioner
     source $DIR/pod-ready-wait.sh
+
+    setup_cert_manager
+}
+
+# The webhooks of the operator get their serving certificate from cert-manager
+setup_cert_manager() {
+    CERT_MANAGER_VERSION=${CERT_MANAGER_VERSION:-v1.16.3}
+
+    kubectl apply -f "https://github.com/cert-manager/cert-manager/releases/download/${CERT_MANAGER_VERSION}/cert-manager.yaml"
+    kubectl wait deployment.apps/cert-manager-webhook --namespace cert-manager \
+      --for condition=Available --timeout 5m
 }
 
 run_kind() {
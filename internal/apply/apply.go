@@ -0,0 +1,272 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apply bulk-applies NamespaceClasses, Namespaces and related manifests
+// from a directory, which is mostly useful when bootstrapping a cluster.
+package apply
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/api/v1beta1"
+	"github.com/kardolus/namespaceclass-operator/internal/validation"
+)
+
+// FileResult reports the outcome of applying a single manifest file.
+type FileResult struct {
+	Path    string
+	Applied int
+	Err     error
+}
+
+type manifest struct {
+	file string
+	obj  *unstructured.Unstructured
+}
+
+// Directory reads every YAML file in dir, validates the NamespaceClasses it
+// contains and applies all objects of the valid files to the cluster.
+//
+// Objects are applied in a safe order: NamespaceClasses first, each after the
+// class it extends, then Namespaces, then everything else. A file that fails to
+// parse or validate, or holds a class whose spec.extends forms a cycle, is
+// reported and none of its objects are applied; the remaining files are still
+// processed.
+func Directory(ctx context.Context, c client.Client, dir string) ([]FileResult, error) {
+	files, err := manifestFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]FileResult, len(files))
+	var manifests []manifest
+	for i, file := range files {
+		results[i].Path = file
+
+		objs, err := readFile(file)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		for _, obj := range objs {
+			manifests = append(manifests, manifest{file: file, obj: obj})
+		}
+	}
+
+	sort.SliceStable(manifests, func(i, j int) bool {
+		return applyRank(manifests[i].obj) < applyRank(manifests[j].obj)
+	})
+	classes := 0
+	for classes < len(manifests) && isClass(manifests[classes].obj) {
+		classes++
+	}
+	cyclic := sortClasses(manifests[:classes])
+
+	index := make(map[string]int, len(results))
+	for i, res := range results {
+		index[res.Path] = i
+	}
+	for _, m := range cyclic {
+		if res := &results[index[m.file]]; res.Err == nil {
+			res.Err = fmt.Errorf("NamespaceClass %q: spec.extends %q forms a cycle", m.obj.GetName(), extends(m.obj))
+		}
+	}
+	for _, m := range manifests {
+		res := &results[index[m.file]]
+		if res.Err != nil {
+			continue
+		}
+		if err := createOrUpdate(ctx, c, m.obj); err != nil {
+			res.Err = fmt.Errorf("%s %q: %w", m.obj.GetKind(), m.obj.GetName(), err)
+			continue
+		}
+		res.Applied++
+	}
+
+	return results, nil
+}
+
+func manifestFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	return files, nil
+}
+
+// readFile decodes all documents of a file and validates any NamespaceClass
+// among them.
+func readFile(path string) ([]*unstructured.Unstructured, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode: %w", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if err := validate(obj); err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+func validate(obj *unstructured.Unstructured) error {
+	if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+		return fmt.Errorf("object %q is missing apiVersion or kind", obj.GetName())
+	}
+	if !isClass(obj) {
+		return nil
+	}
+
+	var class v1alpha1.NamespaceClass
+	if obj.GroupVersionKind() == v1beta1.GroupVersion.WithKind("NamespaceClass") {
+		// Validated as the version the webhook checks it as, which it converts to
+		var beta v1beta1.NamespaceClass
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &beta); err != nil {
+			return fmt.Errorf("invalid NamespaceClass %q: %w", obj.GetName(), err)
+		}
+		if err := beta.ConvertTo(&class); err != nil {
+			return fmt.Errorf("invalid NamespaceClass %q: %w", obj.GetName(), err)
+		}
+	} else if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &class); err != nil {
+		return fmt.Errorf("invalid NamespaceClass %q: %w", obj.GetName(), err)
+	}
+	if errs := validation.ValidateNamespaceClass(&class); len(errs) > 0 {
+		return fmt.Errorf("invalid NamespaceClass %q: %w", obj.GetName(), errs.ToAggregate())
+	}
+	return nil
+}
+
+// isClass reports whether obj is a NamespaceClass of any served version.
+func isClass(obj *unstructured.Unstructured) bool {
+	return obj.GroupVersionKind().GroupKind() == v1alpha1.GroupVersion.WithKind("NamespaceClass").GroupKind()
+}
+
+// extends returns the name of the class that the class obj extends, if any.
+func extends(obj *unstructured.Unstructured) string {
+	parent, _, _ := unstructured.NestedString(obj.Object, "spec", "extends")
+	return parent
+}
+
+// sortClasses orders classes so that every class comes after the class it
+// extends, where that is among them, and otherwise keeps their order. It
+// returns the classes whose spec.extends forms a cycle.
+func sortClasses(classes []manifest) []manifest {
+	byName := make(map[string]int, len(classes))
+	for i := len(classes) - 1; i >= 0; i-- {
+		byName[classes[i].obj.GetName()] = i
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make([]int, len(classes))
+	sorted := make([]manifest, 0, len(classes))
+	var stack []int
+	var cyclic []manifest
+	var visit func(i int)
+	visit = func(i int) {
+		switch state[i] {
+		case visited:
+			return
+		case visiting:
+			for _, j := range stack[slices.Index(stack, i):] {
+				cyclic = append(cyclic, classes[j])
+			}
+			return
+		}
+		state[i] = visiting
+		stack = append(stack, i)
+		if parent, ok := byName[extends(classes[i].obj)]; ok {
+			visit(parent)
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = visited
+		sorted = append(sorted, classes[i])
+	}
+	for i := range classes {
+		visit(i)
+	}
+	copy(classes, sorted)
+	return cyclic
+}
+
+func applyRank(obj *unstructured.Unstructured) int {
+	switch {
+	case isClass(obj):
+		return 0
+	case obj.GetAPIVersion() == "v1" && obj.GetKind() == "Namespace":
+		return 1
+	default:
+		return 2
+	}
+}
+
+func createOrUpdate(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+
+	key := types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+	if err := c.Get(ctx, key, existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return c.Create(ctx, obj)
+	}
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return c.Update(ctx, obj)
+}
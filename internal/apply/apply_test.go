@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply_test
+
+import (
+	"context"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/api/v1beta1"
+	"github.com/kardolus/namespaceclass-operator/internal/apply"
+)
+
+var _ = Describe("Directory", func() {
+	It("should apply classes before namespaces and namespaced objects", func() {
+		c, created := setupRecordingClient()
+
+		results, err := apply.Directory(context.Background(), c, filepath.Join("testdata", "ordered"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(*created).To(Equal([]string{
+			"NamespaceClass/public-network",
+			"Namespace/web-portal",
+			"ConfigMap/extra-config",
+		}))
+
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Path).To(HaveSuffix("01-namespaces.yaml"))
+		Expect(results[0].Err).NotTo(HaveOccurred())
+		Expect(results[0].Applied).To(Equal(2))
+		Expect(results[1].Path).To(HaveSuffix("02-classes.yaml"))
+		Expect(results[1].Err).NotTo(HaveOccurred())
+		Expect(results[1].Applied).To(Equal(1))
+	})
+
+	It("should apply every class after the class it extends", func() {
+		c, created := setupRecordingClient()
+
+		results, err := apply.Directory(context.Background(), c, filepath.Join("testdata", "extends"))
+		Expect(err).NotTo(HaveOccurred())
+		for _, res := range results {
+			Expect(res.Err).NotTo(HaveOccurred(), res.Path)
+		}
+
+		Expect(*created).To(Equal([]string{
+			"NamespaceClass/base-network",
+			"NamespaceClass/internal-network",
+			"NamespaceClass/restricted-network",
+			"Namespace/locked-down",
+		}))
+	})
+
+	It("should not apply classes whose spec.extends forms a cycle", func() {
+		c, created := setupRecordingClient()
+
+		results, err := apply.Directory(context.Background(), c, filepath.Join("testdata", "cycle"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].Err).To(MatchError(ContainSubstring(`NamespaceClass "left": spec.extends "right" forms a cycle`)))
+		Expect(results[1].Err).To(MatchError(ContainSubstring(`NamespaceClass "right": spec.extends "left" forms a cycle`)))
+		Expect(results[2].Err).NotTo(HaveOccurred())
+		Expect(*created).To(Equal([]string{"NamespaceClass/standalone"}))
+	})
+
+	It("should update objects that already exist", func() {
+		c, _ := setupRecordingClient(&v1alpha1.NamespaceClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "public-network"},
+		})
+
+		_, err := apply.Directory(context.Background(), c, filepath.Join("testdata", "ordered"))
+		Expect(err).NotTo(HaveOccurred())
+
+		var class v1alpha1.NamespaceClass
+		Expect(c.Get(context.Background(), types.NamespacedName{Name: "public-network"}, &class)).To(Succeed())
+		Expect(class.Spec.Resources).To(HaveLen(1))
+	})
+
+	It("should report invalid files without applying them and still apply valid ones", func() {
+		c, created := setupRecordingClient()
+
+		results, err := apply.Directory(context.Background(), c, filepath.Join("testdata", "invalid"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].Path).To(HaveSuffix("01-broken-class.yaml"))
+		Expect(results[0].Err).To(MatchError(ContainSubstring("spec.resources[0]")))
+		Expect(results[0].Applied).To(BeZero())
+		Expect(results[1].Err).NotTo(HaveOccurred())
+		Expect(results[1].Applied).To(Equal(1))
+		Expect(results[2].Path).To(HaveSuffix("03-broken-beta-class.yaml"))
+		Expect(results[2].Err).To(MatchError(ContainSubstring("spec.resources[0].metadata.name")))
+		Expect(results[2].Applied).To(BeZero())
+
+		Expect(*created).To(Equal([]string{"NamespaceClass/valid-class"}))
+
+		var ns corev1.Namespace
+		err = c.Get(context.Background(), types.NamespacedName{Name: "broken-ns"}, &ns)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the directory does not exist", func() {
+		c, _ := setupRecordingClient()
+
+		_, err := apply.Directory(context.Background(), c, filepath.Join("testdata", "missing"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+func setupRecordingClient(objs ...client.Object) (client.Client, *[]string) {
+	scheme := runtime.NewScheme()
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+	Expect(v1beta1.AddToScheme(scheme)).To(Succeed())
+
+	var created []string
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				created = append(created, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	return c, &created
+}
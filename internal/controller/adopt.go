@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// adopt brings an object that already existed when obj was created under the
+// management of obj's class: it is updated to match obj, which also stamps it
+// with the ownership label. Objects that already belong to the class are left
+// alone, and objects labeled as owned by another class are never taken over.
+// It reports whether the object was adopted.
+func (r *NamespaceClassReconciler) adopt(
+	ctx context.Context,
+	log logr.Logger,
+	ns *corev1.Namespace,
+	obj *unstructured.Unstructured,
+) (bool, error) {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := r.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, live); err != nil {
+		return false, err
+	}
+
+	className := obj.GetLabels()[NamespaceClassOwnedByKey]
+	switch owner := live.GetLabels()[NamespaceClassOwnedByKey]; owner {
+	case className:
+		return false, nil
+	case "":
+	default:
+		withObject(log, obj).Info("Not adopting resource owned by another class", "owner", owner)
+		r.Recorder.Eventf(ns, corev1.EventTypeWarning, "AdoptionRefused",
+			"%s '%s' is owned by NamespaceClass '%s' and is not adopted by '%s'", obj.GetKind(), obj.GetName(), owner, className)
+		return false, nil
+	}
+
+	if err := r.upsert(ctx, obj); err != nil {
+		return false, err
+	}
+	withObject(log, obj).Info("Adopted existing resource")
+	return true, nil
+}
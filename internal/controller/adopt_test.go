@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Adoption", func() {
+	var (
+		ns    *corev1.Namespace
+		class = newNamespaceClass("adopting-class", mustRawConfigMap("cfg", map[string]string{"foo": "class"}))
+	)
+
+	BeforeEach(func() {
+		ns = newNamespace("adopt-ns", class.Name)
+	})
+
+	getConfigMap := func(ctx context.Context, r *controller.NamespaceClassReconciler) corev1.ConfigMap {
+		GinkgoHelper()
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "cfg", Namespace: ns.Name}, &cm)).To(Succeed())
+		return cm
+	}
+
+	It("should leave existing resources alone without adoption", func() {
+		existing := newInjectedConfigMap("cfg", ns.Name, map[string]string{"foo": "manual"})
+		r, _, ctx := setupTestReconciler(ns, class.DeepCopy(), existing)
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		cm := getConfigMap(ctx, r)
+		Expect(cm.Data).To(HaveKeyWithValue("foo", "manual"))
+		Expect(cm.Labels).NotTo(HaveKey(controller.NamespaceClassOwnedByKey))
+	})
+
+	It("should adopt an unowned existing resource", func() {
+		existing := newInjectedConfigMap("cfg", ns.Name, map[string]string{"foo": "manual"})
+		r, _, ctx := setupTestReconciler(ns, class.DeepCopy(), existing)
+		r.AdoptExisting = true
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		cm := getConfigMap(ctx, r)
+		Expect(cm.Data).To(HaveKeyWithValue("foo", "class"))
+		Expect(cm.Labels).To(HaveKeyWithValue(controller.NamespaceClassOwnedByKey, class.Name))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("ResourcesAdopted")))
+	})
+
+	It("should not adopt a resource owned by another class", func() {
+		existing := newInjectedConfigMap("cfg", ns.Name, map[string]string{"foo": "other"})
+		existing.Labels = map[string]string{controller.NamespaceClassOwnedByKey: "other-class"}
+		r, _, ctx := setupTestReconciler(ns, class.DeepCopy(), existing)
+		r.AdoptExisting = true
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		cm := getConfigMap(ctx, r)
+		Expect(cm.Data).To(HaveKeyWithValue("foo", "other"))
+		Expect(cm.Labels).To(HaveKeyWithValue(controller.NamespaceClassOwnedByKey, "other-class"))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("AdoptionRefused")))
+	})
+})
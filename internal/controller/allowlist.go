@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AllowedKind is an entry of the kind allowlist. An empty Group matches the
+// kind in every API group.
+type AllowedKind struct {
+	Group string
+	Kind  string
+}
+
+// ParseAllowedKinds parses a comma-separated kind allowlist. Each entry is a
+// kind of any group ("NetworkPolicy") or "group/Kind" ("rbac.authorization.k8s.io/Role").
+// "*" allows every kind and yields a nil allowlist.
+func ParseAllowedKinds(value string) ([]AllowedKind, error) {
+	if strings.TrimSpace(value) == "*" {
+		return nil, nil
+	}
+
+	var kinds []AllowedKind
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, "/")
+		switch len(parts) {
+		case 1:
+			kinds = append(kinds, AllowedKind{Kind: parts[0]})
+		case 2:
+			kinds = append(kinds, AllowedKind{Group: parts[0], Kind: parts[1]})
+		default:
+			return nil, fmt.Errorf("invalid kind %q, expected Kind or group/Kind", entry)
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, fmt.Errorf("no kinds allowed, use %q to allow every kind", "*")
+	}
+	return kinds, nil
+}
+
+// kindAllowed reports whether resources of gvk may be injected. Every kind is
+// allowed when AllowedKinds is nil.
+func (r *NamespaceClassReconciler) kindAllowed(gvk schema.GroupVersionKind) bool {
+	if r.AllowedKinds == nil {
+		return true
+	}
+	for _, allowed := range r.AllowedKinds {
+		if allowed.Kind == gvk.Kind && (allowed.Group == "" || allowed.Group == gvk.Group) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterAllowedKinds drops the objects whose kind is not allowed, reporting
+// each with a warning Event on the namespace.
+func (r *NamespaceClassReconciler) filterAllowedKinds(
+	log logr.Logger,
+	ns *corev1.Namespace,
+	className string,
+	objs []*unstructured.Unstructured,
+) []*unstructured.Unstructured {
+	allowed := objs[:0]
+	for _, obj := range objs {
+		if r.kindAllowed(obj.GroupVersionKind()) {
+			allowed = append(allowed, obj)
+			continue
+		}
+		withObject(log, obj).Info("Skipping resource of a kind that is not allowed")
+		r.Recorder.Eventf(ns, corev1.EventTypeWarning, "KindNotAllowed",
+			"NamespaceClass '%s' contains %s '%s', which is not in the allowed kinds", className, obj.GetKind(), obj.GetName())
+	}
+	return allowed
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Allowed kinds", func() {
+	Describe("ParseAllowedKinds", func() {
+		It("should parse bare and group-qualified kinds", func() {
+			kinds, err := controller.ParseAllowedKinds("ConfigMap, rbac.authorization.k8s.io/Role")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kinds).To(Equal([]controller.AllowedKind{
+				{Kind: "ConfigMap"},
+				{Group: "rbac.authorization.k8s.io", Kind: "Role"},
+			}))
+		})
+
+		It("should allow every kind for *", func() {
+			kinds, err := controller.ParseAllowedKinds("*")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kinds).To(BeNil())
+		})
+
+		It("should reject malformed and empty lists", func() {
+			_, err := controller.ParseAllowedKinds("apps/v1/Deployment")
+			Expect(err).To(HaveOccurred())
+			_, err = controller.ParseAllowedKinds(" , ")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("should apply allowed kinds and reject the others", func() {
+		ns := newNamespace("allowlist-ns", "allowlist-class")
+		class := newNamespaceClass("allowlist-class",
+			mustRawConfigMap("cfg", map[string]string{"foo": "bar"}),
+			mustRawRoleBinding("admin", "default", nil),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+		r.AllowedKinds = []controller.AllowedKind{{Kind: "ConfigMap"}}
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+		err = r.Get(ctx, types.NamespacedName{Name: "admin", Namespace: ns.Name}, &rbacv1.RoleBinding{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(And(
+			ContainSubstring("KindNotAllowed"), ContainSubstring("RoleBinding 'admin'"))))
+	})
+
+	It("should only match the group of a group-qualified kind", func() {
+		ns := newNamespace("grouped-ns", "grouped-class")
+		class := newNamespaceClass("grouped-class", mustRawRoleBinding("viewer", "default", nil))
+		r, _, ctx := setupTestReconciler(ns, class)
+		r.AllowedKinds = []controller.AllowedKind{{Group: rbacv1.GroupName, Kind: "RoleBinding"}}
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, types.NamespacedName{Name: "viewer", Namespace: ns.Name}, &rbacv1.RoleBinding{})).To(Succeed())
+	})
+})
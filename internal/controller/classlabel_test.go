@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+const customClassLabelKey = "platform.example.com/class"
+
+var _ = Describe("Custom class label key", func() {
+	var (
+		ns    *corev1.Namespace
+		class *v1alpha1.NamespaceClass
+	)
+
+	BeforeEach(func() {
+		ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "custom-label-ns",
+			Labels: map[string]string{customClassLabelKey: "custom-label-class"},
+		}}
+		class = newNamespaceClass("custom-label-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+	})
+
+	It("should index namespaces by the custom key", func() {
+		index := controller.ClassLabelIndex(customClassLabelKey)
+		Expect(index(ns)).To(Equal([]string{class.Name}))
+		Expect(index(newNamespace("default-key", class.Name))).To(BeEmpty())
+	})
+
+	It("should apply the class to namespaces labeled with the custom key", func() {
+		r, ctx := setupReconcilerWithClassLabelKey(customClassLabelKey, ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+	})
+
+	It("should ignore the default key", func() {
+		labeled := newNamespace("default-key-ns", class.Name)
+		r, ctx := setupReconcilerWithClassLabelKey(customClassLabelKey, labeled, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, labeled.Name)).To(BeEmpty())
+	})
+
+	It("should map namespaces to the class named by the custom key", func() {
+		r, ctx := setupReconcilerWithClassLabelKey(customClassLabelKey, ns, class)
+
+		requests := controller.MapNamespaceToNamespaceClass(r, ctx, ns)
+		Expect(requests).To(ConsistOf(requestFor(class)))
+	})
+})
+
+func setupReconcilerWithClassLabelKey(key string, objs ...client.Object) (*controller.NamespaceClassReconciler, context.Context) {
+	scheme := runtime.NewScheme()
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(newRESTMapper(scheme)).
+		WithObjects(objs...).
+		WithIndex(&corev1.Namespace{}, controller.NamespaceClassIndexKey, controller.ClassLabelIndex(key)).
+		WithStatusSubresource(&v1alpha1.NamespaceClass{}).
+		Build()
+
+	r := &controller.NamespaceClassReconciler{
+		Client:        c,
+		Scheme:        scheme,
+		Recorder:      record.NewFakeRecorder(100),
+		ClassLabelKey: key,
+	}
+
+	log.SetLogger(zap.New(zap.WriteTo(GinkgoWriter)))
+	return r, log.IntoContext(context.Background(), log.Log)
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// isClusterScoped reports whether obj is shared by every namespace of its
+// class. resolveKinds clears the namespace of objects of cluster-scoped kinds.
+func isClusterScoped(obj *unstructured.Unstructured) bool {
+	return obj.GetNamespace() == ""
+}
+
+// objectKey identifies an object by kind and name, like resourceKey.
+func objectKey(obj *unstructured.Unstructured) string {
+	return obj.GroupVersionKind().GroupKind().String() + "/" + obj.GetName()
+}
+
+// clusterScopedApplied reports whether the class already created the
+// cluster-scoped object obj.
+func clusterScopedApplied(class *v1alpha1.NamespaceClass, obj *unstructured.Unstructured) bool {
+	return slices.Contains(class.Status.ClusterScopedResources, objectKey(obj))
+}
+
+// clusterScopedConflict returns an error if the cluster-scoped object obj
+// already exists and is owned by another class than class, which is reported
+// with a warning Event on class. Two classes that define the same object would
+// otherwise keep overwriting each other; the class that created it first keeps
+// it.
+func (r *NamespaceClassReconciler) clusterScopedConflict(
+	ctx context.Context,
+	obj *unstructured.Unstructured,
+	class *v1alpha1.NamespaceClass,
+) error {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	err := r.Get(ctx, types.NamespacedName{Name: obj.GetName()}, live)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	owner := live.GetLabels()[NamespaceClassOwnedByKey]
+	if owner == "" || owner == class.Name {
+		return nil
+	}
+	r.Recorder.Eventf(class, corev1.EventTypeWarning, "ClusterScopedConflict",
+		"%s '%s' is owned by NamespaceClass '%s' and was not applied", obj.GetKind(), obj.GetName(), owner)
+	return fmt.Errorf("%s %q is owned by NamespaceClass %q", obj.GetKind(), obj.GetName(), owner)
+}
+
+// clusterScopedResources returns the cluster-scoped resources of class after a
+// reconcile: the ones applied now and the ones recorded before that the class
+// still defines.
+func clusterScopedResources(class *v1alpha1.NamespaceClass, applied map[string]bool) []string {
+	defined := map[string]bool{}
+	for _, res := range class.Spec.Resources {
+		if key, ok := resourceKey(res); ok {
+			defined[key] = true
+		}
+	}
+
+	var keys []string
+	for key := range applied {
+		keys = append(keys, key)
+	}
+	for _, key := range class.Status.ClusterScopedResources {
+		if defined[key] {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// isTerminating reports whether ns is being deleted.
+func isTerminating(ns *corev1.Namespace) bool {
+	return ns.DeletionTimestamp != nil || ns.Status.Phase == corev1.NamespaceTerminating
+}
+
+// clusterScopedObjects returns, by objectKey, the cluster-scoped objects class
+// creates for ns. Unlike buildResources and resolveKinds it reports nothing,
+// so that it can be run for every namespace of the class. Values of the class
+// that cannot be read are returned as an error, since the objects that use
+// them would be missing.
+func (r *NamespaceClassReconciler) clusterScopedObjects(
+	ctx context.Context,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+) (map[string]*unstructured.Unstructured, error) {
+	values, err := r.templateValues(ctx, class)
+	if err != nil {
+		return nil, err
+	}
+	objs := map[string]*unstructured.Unstructured{}
+	for _, res := range class.Spec.Resources {
+		raw, err := renderResource(res.Raw, ns, class.Name, values)
+		if err != nil {
+			continue
+		}
+		obj, err := buildResource(runtime.RawExtension{Raw: raw}, ns.Name, class.Name)
+		if err != nil || isDeletion(obj) || isPatch(obj) ||
+			obj.GetAnnotations()[NamespaceClassClusterScopedKey] != "true" {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		if !r.kindAllowed(gvk) {
+			continue
+		}
+		mapping, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil || mapping.Scope.Name() != meta.RESTScopeNameRoot {
+			continue
+		}
+		obj.SetNamespace("")
+		objs[objectKey(obj)] = obj
+	}
+	return objs, nil
+}
+
+// releaseClusterScoped deletes the cluster-scoped objects class created for
+// ns, which is being deleted, that no other namespace of the class still
+// needs. Deleting the namespace takes its namespaced resources with it, but
+// nothing else would ever remove these. Objects not owned by class are left
+// alone, and so is everything while class is paused or its cleanupPolicy is
+// Orphan. Nothing is released when the resources of a namespace cannot all be
+// read, since another namespace may still need the missing ones. It returns
+// the keys of the objects that are gone.
+func (r *NamespaceClassReconciler) releaseClusterScoped(
+	ctx context.Context,
+	log logr.Logger,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+) ([]string, error) {
+	if class.Spec.CleanupPolicy == v1alpha1.CleanupPolicyOrphan || isPaused(class) {
+		return nil, nil
+	}
+	composed, err := r.composedClass(ctx, ns, class)
+	if err != nil {
+		return nil, err
+	}
+	released, err := r.clusterScopedObjects(ctx, ns, composed)
+	if err != nil || len(released) == 0 {
+		return nil, err
+	}
+
+	namespaces, err := r.namespacesForClass(ctx, class.Name, class.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range namespaces {
+		if other.Name == ns.Name || isTerminating(&other) {
+			continue
+		}
+		composed, err := r.composedClass(ctx, &other, class)
+		if err != nil {
+			return nil, err
+		}
+		needed, err := r.clusterScopedObjects(ctx, &other, composed)
+		if err != nil {
+			return nil, err
+		}
+		for key := range needed {
+			delete(released, key)
+		}
+		if len(released) == 0 {
+			return nil, nil
+		}
+	}
+
+	var gone []string
+	for _, key := range slices.Sorted(maps.Keys(released)) {
+		obj := released[key]
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		err := r.Get(ctx, types.NamespacedName{Name: obj.GetName()}, live)
+		if apierrors.IsNotFound(err) {
+			gone = append(gone, key)
+			continue
+		}
+		if err != nil {
+			return gone, err
+		}
+		if owner := live.GetLabels()[NamespaceClassOwnedByKey]; owner != class.Name {
+			withObject(log, obj).Info("Keeping cluster-scoped resource not owned by the class", "owner", owner)
+			continue
+		}
+		if err := r.writer().Delete(ctx, live, r.deleteOptions(class)...); client.IgnoreNotFound(err) != nil {
+			return gone, err
+		}
+		withObject(log, obj).Info("Deleted cluster-scoped resource no namespace needs anymore")
+		r.Recorder.Eventf(class, corev1.EventTypeNormal, "ClusterScopedResourceDeleted",
+			"Deleted %s '%s': no namespace of the class needs it after namespace '%s' was deleted",
+			obj.GetKind(), obj.GetName(), ns.Name)
+		gone = append(gone, key)
+	}
+	return gone, nil
+}
@@ -0,0 +1,332 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Cluster-scoped resources", func() {
+	optIn := map[string]string{controller.NamespaceClassClusterScopedKey: "true"}
+
+	It("should create a single opted-in ClusterRole for all namespaces of the class", func() {
+		nsA := newNamespace("cluster-a", "cluster-class")
+		nsB := newNamespace("cluster-b", "cluster-class")
+		class := newNamespaceClass("cluster-class", mustRawClusterRole("class-reader", optIn))
+		r, _, ctx := setupTestReconciler(nsA, nsB, class)
+
+		_, err := r.Reconcile(ctx, requestFor(nsA))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, requestFor(nsB))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var roles rbacv1.ClusterRoleList
+		Expect(r.List(ctx, &roles)).To(Succeed())
+		Expect(roles.Items).To(HaveLen(1))
+		Expect(roles.Items[0].Name).To(Equal("class-reader"))
+		Expect(roles.Items[0].Namespace).To(BeEmpty())
+		Expect(roles.Items[0].Labels).To(HaveKeyWithValue(controller.NamespaceClassOwnedByKey, "cluster-class"))
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(persisted.Status.Conditions, v1alpha1.ConditionReady)).To(BeTrue())
+	})
+
+	It("should create a shared ClusterRole exactly once for two namespaces", func() {
+		nsA := newNamespace("shared-a", "shared-class")
+		nsB := newNamespace("shared-b", "shared-class")
+		class := newNamespaceClass("shared-class", mustRawClusterRole("shared-reader", optIn))
+
+		creates := 0
+		r, _, ctx := setupTestReconcilerWithInterceptor(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if obj.GetObjectKind().GroupVersionKind().Kind == "ClusterRole" {
+					creates++
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}, nsA, nsB, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.ClusterScopedResources).To(ConsistOf("ClusterRole.rbac.authorization.k8s.io/shared-reader"))
+
+		By("provisioning the namespaces individually")
+		_, err = r.Reconcile(ctx, requestFor(nsA))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, requestFor(nsB))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(creates).To(Equal(1))
+		Expect(meta.IsStatusConditionTrue(persisted.Status.Conditions, v1alpha1.ConditionReady)).To(BeTrue())
+	})
+
+	It("should forget a cluster-scoped resource removed from the class", func() {
+		ns := newNamespace("forget-ns", "forget-class")
+		class := newNamespaceClass("forget-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		class.Status.ClusterScopedResources = []string{"ClusterRole.rbac.authorization.k8s.io/removed"}
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.ClusterScopedResources).To(BeEmpty())
+	})
+
+	It("should skip a ClusterRole without the opt-in annotation", func() {
+		ns := newNamespace("cluster-skip-ns", "cluster-skip-class")
+		class := newNamespaceClass("cluster-skip-class",
+			mustRawClusterRole("class-reader", nil),
+			mustRawConfigMap("cfg", map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var roles rbacv1.ClusterRoleList
+		Expect(r.List(ctx, &roles)).To(Succeed())
+		Expect(roles.Items).To(BeEmpty())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("ClusterScopedResource")))
+	})
+
+	It("should not apply a ClusterRole that another class already owns", func() {
+		nsA := newNamespace("owner-ns", "owner-class")
+		nsB := newNamespace("rival-ns", "rival-class")
+		owner := newNamespaceClass("owner-class", mustRawClusterRole("contested-reader", optIn))
+		rival := newNamespaceClass("rival-class",
+			mustRawClusterRole("contested-reader", optIn),
+			mustRawConfigMap("cfg", map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconciler(nsA, nsB, owner, rival)
+
+		_, err := r.Reconcile(ctx, requestFor(owner))
+		Expect(err).NotTo(HaveOccurred())
+		var before rbacv1.ClusterRole
+		Expect(r.Get(ctx, types.NamespacedName{Name: "contested-reader"}, &before)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(rival))
+		Expect(err).NotTo(HaveOccurred())
+
+		var after rbacv1.ClusterRole
+		Expect(r.Get(ctx, types.NamespacedName{Name: "contested-reader"}, &after)).To(Succeed())
+		Expect(after.Labels).To(HaveKeyWithValue(controller.NamespaceClassOwnedByKey, "owner-class"))
+		Expect(after.ResourceVersion).To(Equal(before.ResourceVersion))
+		Expect(listConfigMaps(r.Client, ctx, nsB.Name)).To(HaveLen(1))
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: rival.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.ClusterScopedResources).To(BeEmpty())
+		ready := meta.FindStatusCondition(persisted.Status.Conditions, v1alpha1.ConditionReady)
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Message).To(ContainSubstring(`ClusterRole "contested-reader" is owned by NamespaceClass "owner-class"`))
+
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		Expect(events).To(ContainElement(ContainSubstring("ClusterScopedConflict")))
+	})
+
+	It("should not create a ClusterRole that another class owns when provisioning a namespace", func() {
+		ns := newNamespace("rival-create-ns", "rival-create-class")
+		class := newNamespaceClass("rival-create-class", mustRawClusterRole("taken-reader", optIn))
+		taken := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{
+			Name:   "taken-reader",
+			Labels: map[string]string{controller.NamespaceClassOwnedByKey: "owner-class"},
+		}}
+		r, _, ctx := setupTestReconciler(ns, class, taken)
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Annotations).NotTo(HaveKey(controller.NamespaceClassAppliedHashKey))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("ClusterScopedConflict")))
+	})
+
+	Context("when namespaces are deleted", func() {
+		terminating := func(ns *corev1.Namespace) *corev1.Namespace {
+			ns.Status.Phase = corev1.NamespaceTerminating
+			return ns
+		}
+
+		clusterRoles := func(ctx context.Context, c client.Client) []string {
+			var roles rbacv1.ClusterRoleList
+			Expect(c.List(ctx, &roles)).To(Succeed())
+			var names []string
+			for _, role := range roles.Items {
+				names = append(names, role.Name)
+			}
+			return names
+		}
+
+		markTerminating := func(ctx context.Context, c client.Client, name string) {
+			var ns corev1.Namespace
+			Expect(c.Get(ctx, types.NamespacedName{Name: name}, &ns)).To(Succeed())
+			ns.Status.Phase = corev1.NamespaceTerminating
+			Expect(c.Status().Update(ctx, &ns)).To(Succeed())
+		}
+
+		It("should delete a shared ClusterRole only once no namespace needs it", func() {
+			nsA := newNamespace("refcount-a", "refcount-class")
+			nsB := newNamespace("refcount-b", "refcount-class")
+			class := newNamespaceClass("refcount-class", mustRawClusterRole("refcount-reader", optIn))
+			r, _, ctx := setupTestReconciler(nsA, nsB, class)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterRoles(ctx, r.Client)).To(ConsistOf("refcount-reader"))
+
+			By("deleting the first namespace")
+			markTerminating(ctx, r.Client, nsA.Name)
+			_, err = r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterRoles(ctx, r.Client)).To(ConsistOf("refcount-reader"))
+
+			By("deleting the last namespace")
+			markTerminating(ctx, r.Client, nsB.Name)
+			_, err = r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterRoles(ctx, r.Client)).To(BeEmpty())
+
+			var persisted v1alpha1.NamespaceClass
+			Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+			Expect(persisted.Status.ClusterScopedResources).To(BeEmpty())
+
+			var events []string
+			for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+				events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+			}
+			Expect(events).To(ContainElement(ContainSubstring("ClusterScopedResourceDeleted")))
+		})
+
+		It("should delete the ClusterRole created for a deleted namespace only", func() {
+			nsA := newNamespace("templated-a", "templated-class")
+			nsB := newNamespace("templated-b", "templated-class")
+			class := newNamespaceClass("templated-class", mustRawClusterRole("{{ .Namespace }}-reader", optIn))
+			r, _, ctx := setupTestReconciler(nsA, nsB, class)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterRoles(ctx, r.Client)).To(ConsistOf("templated-a-reader", "templated-b-reader"))
+
+			markTerminating(ctx, r.Client, nsA.Name)
+			_, err = r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterRoles(ctx, r.Client)).To(ConsistOf("templated-b-reader"))
+		})
+
+		It("should keep cluster-scoped resources of a class with the Orphan policy", func() {
+			ns := newNamespace("orphan-cluster-ns", "orphan-cluster-class")
+			class := newNamespaceClass("orphan-cluster-class", mustRawClusterRole("orphan-reader", optIn))
+			class.Spec.CleanupPolicy = v1alpha1.CleanupPolicyOrphan
+			r, _, ctx := setupTestReconciler(ns, class)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			markTerminating(ctx, r.Client, ns.Name)
+			_, err = r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterRoles(ctx, r.Client)).To(ConsistOf("orphan-reader"))
+		})
+
+		It("should not delete a ClusterRole owned by another class", func() {
+			ns := terminating(newNamespace("foreign-ns", "foreign-class"))
+			class := newNamespaceClass("foreign-class", mustRawClusterRole("foreign-reader", optIn))
+			foreign := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{
+				Name:   "foreign-reader",
+				Labels: map[string]string{controller.NamespaceClassOwnedByKey: "owner-class"},
+			}}
+			r, _, ctx := setupTestReconciler(ns, class, foreign)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterRoles(ctx, r.Client)).To(ConsistOf("foreign-reader"))
+		})
+
+		It("should release cluster-scoped resources of a finalized namespace", func() {
+			now := metav1.Now()
+			ns := newNamespace("finalized-ns", "finalized-class")
+			ns.DeletionTimestamp = &now
+			ns.Finalizers = []string{controller.NamespaceClassFinalizerKey}
+			ns.Annotations = map[string]string{controller.NamespaceClassAppliedClassKey: "finalized-class"}
+			class := newNamespaceClass("finalized-class", mustRawClusterRole("finalized-reader", optIn))
+			class.Status.ClusterScopedResources = []string{"ClusterRole.rbac.authorization.k8s.io/finalized-reader"}
+			role := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{
+				Name:   "finalized-reader",
+				Labels: map[string]string{controller.NamespaceClassOwnedByKey: "finalized-class"},
+			}}
+			r, _, ctx := setupTestReconciler(ns, class, role)
+
+			_, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterRoles(ctx, r.Client)).To(BeEmpty())
+
+			var persisted v1alpha1.NamespaceClass
+			Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+			Expect(persisted.Status.ClusterScopedResources).To(BeEmpty())
+		})
+	})
+})
+
+func mustRawClusterRole(name string, annotations map[string]string) runtime.RawExtension {
+	role := &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterRole",
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: annotations,
+		},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+			Verbs:     []string{"get", "list"},
+		}},
+	}
+
+	raw, err := json.Marshal(role)
+	Expect(err).NotTo(HaveOccurred())
+	return runtime.RawExtension{Raw: raw}
+}
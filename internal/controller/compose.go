@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// additionalClassNames returns the classes ns composes into its own class
+// with numbered class labels, e.g. namespaceclass.akuity.io/name-1 and
+// namespaceclass.akuity.io/name-2, in the order of their numbers. Label values
+// cannot hold a list, so every further class takes a label of its own.
+func (r *NamespaceClassReconciler) additionalClassNames(ns client.Object) []string {
+	prefix := r.classLabelKey() + "-"
+	type numbered struct {
+		n    int
+		name string
+	}
+	var found []numbered
+	for key, value := range ns.GetLabels() {
+		suffix, ok := strings.CutPrefix(key, prefix)
+		if !ok || value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(suffix)
+		if err != nil || n < 1 {
+			continue
+		}
+		found = append(found, numbered{n, value})
+	}
+	slices.SortFunc(found, func(a, b numbered) int { return a.n - b.n })
+
+	var names []string
+	for _, f := range found {
+		if !slices.Contains(names, f.name) {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// resolvedClass returns the resources class applies to ns: its effective
+// resources composed with those of the additional classes of ns. Resources
+// that cannot be read are left out and returned as an error.
+func (r *NamespaceClassReconciler) resolvedClass(
+	ctx context.Context,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+) (*v1alpha1.NamespaceClass, error) {
+	effective, err := r.effectiveClass(ctx, class)
+	composed, composeErr := r.composedClass(ctx, ns, effective)
+	return composed, errors.Join(err, composeErr)
+}
+
+// composedClass returns class with the effective resources of the additional
+// classes of ns laid over its own, in label order. A resource of a later class
+// replaces one of the same kind and name of an earlier class, so the last
+// class that defines an object wins. Everything is applied, labeled and
+// cleaned up as part of class, which stays the only class of the namespace.
+// Additional classes that cannot be read are reported with a warning Event on
+// ns and left out. Additional classes that exist but cannot be read, and their
+// referenced resources that cannot be read, are left out too and returned as
+// an error, like by effectiveClass.
+func (r *NamespaceClassReconciler) composedClass(
+	ctx context.Context,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+) (*v1alpha1.NamespaceClass, error) {
+	names := r.additionalClassNames(ns)
+	if len(names) == 0 {
+		return class, nil
+	}
+
+	log := classLogger(ctx, class.Name, ns.Name)
+	var errs []error
+	composed := class.DeepCopy()
+	index := map[string]int{}
+	for i, res := range composed.Spec.Resources {
+		if key, ok := resourceKey(res); ok {
+			index[key] = i
+		}
+	}
+	for _, name := range names {
+		if name == class.Name {
+			continue
+		}
+		var extra v1alpha1.NamespaceClass
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, &extra); err != nil {
+			log.Error(err, "Failed to get additional NamespaceClass", "additional", name)
+			if !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("additional NamespaceClass %q: %w", name, err))
+				continue
+			}
+			r.warnOnce(ns, "MissingNamespaceClass",
+				"Namespace references missing NamespaceClass '%s'", name)
+			continue
+		}
+		if extra.DeletionTimestamp != nil {
+			continue
+		}
+		effective, err := r.effectiveClass(ctx, &extra)
+		errs = append(errs, err)
+		for _, res := range effective.Spec.Resources {
+			key, ok := resourceKey(res)
+			if !ok {
+				composed.Spec.Resources = append(composed.Spec.Resources, res)
+				continue
+			}
+			if at, exists := index[key]; exists {
+				log.Info("Additional NamespaceClass replaces resource", "additional", name, "resource", key)
+				composed.Spec.Resources[at] = res
+				continue
+			}
+			index[key] = len(composed.Spec.Resources)
+			composed.Spec.Resources = append(composed.Spec.Resources, res)
+		}
+	}
+	return composed, errors.Join(errs...)
+}
+
+// mapAdditionalClassToNamespaceClasses enqueues the class of every namespace
+// that composes obj as an additional class, so that they pick up its changes.
+func (r *NamespaceClassReconciler) mapAdditionalClassToNamespaceClasses(ctx context.Context, obj client.Object) []reconcile.Request {
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Failed to list namespaces for additional NamespaceClass", "additional", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, ns := range namespaces.Items {
+		if !slices.Contains(r.additionalClassNames(&ns), obj.GetName()) {
+			continue
+		}
+		className, ok := r.classNameFor(ctx, &ns)
+		if !ok || className == obj.GetName() {
+			continue
+		}
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Name: className}}
+		if !slices.Contains(requests, request) {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
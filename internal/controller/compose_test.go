@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Multiple classes per namespace", func() {
+	// withAdditionalClasses labels ns with the numbered class labels for names.
+	withAdditionalClasses := func(ns *corev1.Namespace, names ...string) *corev1.Namespace {
+		for i, name := range names {
+			ns.Labels[controller.NamespaceClassNameKey+"-"+strconv.Itoa(i+1)] = name
+		}
+		return ns
+	}
+
+	It("should apply the resources of two classes to one namespace", func() {
+		base := newNamespaceClass("compose-base",
+			mustRawConfigMap("base-cfg", map[string]string{"from": "base"}),
+			mustRawConfigMap("shared", map[string]string{"from": "base"}),
+		)
+		monitoring := newNamespaceClass("compose-monitoring",
+			mustRawConfigMap("monitoring-cfg", map[string]string{"from": "monitoring"}),
+			mustRawConfigMap("shared", map[string]string{"from": "monitoring"}),
+		)
+		ns := withAdditionalClasses(newNamespace("compose-ns", base.Name), monitoring.Name)
+		r, _, ctx := setupTestReconciler(base, monitoring, ns)
+
+		_, err := r.Reconcile(ctx, requestFor(base))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(3))
+		for _, cm := range cms {
+			Expect(cm.Labels).To(HaveKeyWithValue(controller.NamespaceClassOwnedByKey, base.Name))
+		}
+
+		By("letting the later class win on the same kind and name")
+		var shared corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "shared", Namespace: ns.Name}, &shared)).To(Succeed())
+		Expect(shared.Data).To(HaveKeyWithValue("from", "monitoring"))
+	})
+
+	It("should apply additional classes in the order of their numbers", func() {
+		base := newNamespaceClass("order-base", mustRawConfigMap("shared", map[string]string{"from": "base"}))
+		first := newNamespaceClass("order-first", mustRawConfigMap("shared", map[string]string{"from": "first"}))
+		second := newNamespaceClass("order-second", mustRawConfigMap("shared", map[string]string{"from": "second"}))
+		ns := withAdditionalClasses(newNamespace("order-ns", base.Name), first.Name, second.Name)
+		r, _, ctx := setupTestReconciler(base, first, second, ns)
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		var shared corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "shared", Namespace: ns.Name}, &shared)).To(Succeed())
+		Expect(shared.Data).To(HaveKeyWithValue("from", "second"))
+	})
+
+	It("should keep a resource dropped from the class that an additional class still defines", func() {
+		base := newNamespaceClass("dropped-base", mustRawConfigMap("base-cfg", map[string]string{"from": "base"}))
+		base.Status.LastAppliedResources = []runtime.RawExtension{
+			mustRawConfigMap("base-cfg", map[string]string{"from": "base"}),
+			mustRawConfigMap("shared", map[string]string{"from": "base"}),
+		}
+		extra := newNamespaceClass("dropped-extra", mustRawConfigMap("shared", map[string]string{"from": "extra"}))
+		ns := withAdditionalClasses(newNamespace("dropped-ns", base.Name), extra.Name)
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		r, _, ctx := setupTestReconciler(base, extra, ns)
+
+		_, err := r.Reconcile(ctx, requestFor(base))
+		Expect(err).NotTo(HaveOccurred())
+
+		var shared corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "shared", Namespace: ns.Name}, &shared)).To(Succeed())
+		Expect(shared.Data).To(HaveKeyWithValue("from", "extra"))
+	})
+
+	It("should apply the resource limit to the composed class", func() {
+		base := newNamespaceClass("limit-base", mustRawConfigMap("base-cfg", map[string]string{"foo": "bar"}))
+		extra := newNamespaceClass("limit-extra",
+			mustRawConfigMap("extra-one", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("extra-two", map[string]string{"foo": "bar"}),
+		)
+		ns := withAdditionalClasses(newNamespace("limit-ns", base.Name), extra.Name)
+		r, _, ctx := setupTestReconciler(base, extra, ns)
+		r.MaxResourcesPerClass = 2
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("TooManyResources")))
+	})
+
+	It("should skip a missing additional class and apply the rest", func() {
+		base := newNamespaceClass("missing-extra-base", mustRawConfigMap("base-cfg", map[string]string{"foo": "bar"}))
+		ns := withAdditionalClasses(newNamespace("missing-extra-ns", base.Name), "no-such-class")
+		r, _, ctx := setupTestReconciler(base, ns)
+
+		_, err := r.Reconcile(ctx, requestFor(base))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(SatisfyAll(
+			ContainSubstring("MissingNamespaceClass"),
+			ContainSubstring("no-such-class"),
+		)))
+	})
+
+	It("should enqueue the class of every namespace that composes a changed class", func() {
+		base := newNamespaceClass("map-base")
+		other := newNamespaceClass("map-other")
+		monitoring := newNamespaceClass("map-monitoring")
+		nsA := withAdditionalClasses(newNamespace("map-a", base.Name), monitoring.Name)
+		nsB := withAdditionalClasses(newNamespace("map-b", base.Name), monitoring.Name)
+		nsC := withAdditionalClasses(newNamespace("map-c", other.Name), "unrelated")
+		r, _, ctx := setupTestReconciler(base, other, monitoring, nsA, nsB, nsC)
+
+		requests := controller.MapAdditionalClassToNamespaceClasses(r, ctx, monitoring)
+		Expect(requests).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Name: base.Name}}))
+	})
+})
@@ -0,0 +1,36 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Controller options", func() {
+	It("should apply the configured MaxConcurrentReconciles", func() {
+		r := &controller.NamespaceClassReconciler{MaxConcurrentReconciles: 8}
+		Expect(controller.ControllerOptions(r).MaxConcurrentReconciles).To(Equal(8))
+	})
+
+	It("should leave the controller-runtime default in place when unset", func() {
+		r := &controller.NamespaceClassReconciler{}
+		Expect(controller.ControllerOptions(r).MaxConcurrentReconciles).To(BeZero())
+	})
+})
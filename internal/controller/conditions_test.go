@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Conditions", func() {
+	It("should set Ready to True when all resources are applied", func() {
+		ns := newNamespace("ready-ns", "ready-class")
+		class := newNamespaceClass("ready-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		class.Generation = 3
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		cond := meta.FindStatusCondition(persisted.Status.Conditions, v1alpha1.ConditionReady)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(v1alpha1.ReasonApplied))
+		Expect(cond.ObservedGeneration).To(Equal(persisted.Generation))
+	})
+
+	It("should set Ready to False when a resource fails to apply", func() {
+		ns := newNamespace("degraded-ns", "degraded-class")
+		class := newNamespaceClass("degraded-class",
+			mustRawConfigMap("cfg", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("", map[string]string{"missing": "name"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		cond := meta.FindStatusCondition(persisted.Status.Conditions, v1alpha1.ConditionReady)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(v1alpha1.ReasonApplyFailed))
+		Expect(cond.Message).To(ContainSubstring("degraded-ns"))
+		Expect(cond.Message).To(ContainSubstring("ConfigMap"))
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+	})
+
+	It("should leave out the problems that do not fit into a condition message", func() {
+		var failures []string
+		for i := range 1000 {
+			failures = append(failures, fmt.Sprintf("ns-%d: %s", i, strings.Repeat("x", 100)))
+		}
+
+		msg := controller.ConditionMessage(failures)
+		Expect(len(msg)).To(BeNumerically("<=", 32768))
+		Expect(msg).To(HavePrefix(failures[0] + "; "))
+		Expect(msg).To(MatchRegexp(`; \.\.\. and \d+ more$`))
+
+		By("keeping short messages as they are")
+		Expect(controller.ConditionMessage(failures[:2])).To(Equal(failures[0] + "; " + failures[1]))
+
+		By("cutting a single problem that is too long")
+		msg = controller.ConditionMessage([]string{strings.Repeat("y", 40000), "other"})
+		Expect(len(msg)).To(BeNumerically("<=", 32768))
+		Expect(msg).To(HaveSuffix("...; ... and 1 more"))
+	})
+
+	It("should report a class without resources once", func() {
+		ns := newNamespace("empty-ns", "empty-class")
+		class := newNamespaceClass("empty-class")
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		cond := meta.FindStatusCondition(persisted.Status.Conditions, v1alpha1.ConditionReady)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(v1alpha1.ReasonNoResources))
+
+		events := r.Recorder.(*record.FakeRecorder).Events
+		var empty []string
+		for len(events) > 0 {
+			if event := <-events; strings.Contains(event, "EmptyNamespaceClass") {
+				empty = append(empty, event)
+			}
+		}
+		Expect(empty).To(ConsistOf(
+			ContainSubstring("NamespaceClass 'empty-class' defines no resources; nothing was applied"),
+			ContainSubstring("NamespaceClass defines no resources; nothing is applied to its 1 namespace(s)"),
+		))
+	})
+})
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// conflictManagerPattern matches the field manager in the message of a
+// conflict cause, e.g. `conflict with "kubectl-edit" using v1: .data.foo`.
+var conflictManagerPattern = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// fieldConflicts returns the fields that a server-side apply failed on with
+// err, together with the managers that own them. Other errors, including
+// conflicts of optimistic locking, have none.
+func fieldConflicts(err error) []v1alpha1.FieldConflict {
+	var status apierrors.APIStatus
+	if !apierrors.IsConflict(err) || !errors.As(err, &status) || status.Status().Details == nil {
+		return nil
+	}
+	var conflicts []v1alpha1.FieldConflict
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflict := v1alpha1.FieldConflict{Field: cause.Field}
+		if match := conflictManagerPattern.FindStringSubmatch(cause.Message); match != nil {
+			conflict.Manager = match[1]
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// describeConflicts formats conflicts for an event message.
+func describeConflicts(conflicts []v1alpha1.FieldConflict) string {
+	described := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		if c.Manager == "" {
+			described = append(described, c.Field)
+			continue
+		}
+		described = append(described, fmt.Sprintf("%s (owned by %q)", c.Field, c.Manager))
+	}
+	return strings.Join(described, ", ")
+}
+
+// namespaceConflicts returns the field conflicts of every resource that failed
+// to apply to ns with err.
+func namespaceConflicts(ns string, err error) []v1alpha1.FieldConflict {
+	if err == nil {
+		return nil
+	}
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	var conflicts []v1alpha1.FieldConflict
+	for _, err := range errs {
+		var re *resourceError
+		if !errors.As(err, &re) {
+			continue
+		}
+		for _, c := range fieldConflicts(re.err) {
+			c.Namespace = ns
+			c.Resource = re.resource
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts
+}
+
+// setFieldConflicts replaces the field conflicts of class with conflicts: all
+// of them if ns is empty, or else those of the namespace ns.
+func setFieldConflicts(class *v1alpha1.NamespaceClass, ns string, conflicts []v1alpha1.FieldConflict) {
+	kept := slices.DeleteFunc(class.Status.FieldConflicts, func(c v1alpha1.FieldConflict) bool {
+		return ns == "" || c.Namespace == ns
+	})
+	kept = append(kept, conflicts...)
+	slices.SortFunc(kept, func(a, b v1alpha1.FieldConflict) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Resource, b.Resource), cmp.Compare(a.Field, b.Field))
+	})
+	if len(kept) == 0 {
+		kept = nil
+	}
+	class.Status.FieldConflicts = kept
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+)
+
+// drainContext returns the context a single namespace is applied with. Once
+// ctx is cancelled, e.g. because the operator received SIGTERM, the returned
+// context lives on for up to DrainTimeout, so that an apply in flight is
+// finished instead of leaving the namespace with only part of its resources.
+// Without a DrainTimeout it is ctx itself.
+func (r *NamespaceClassReconciler) drainContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.DrainTimeout <= 0 {
+		return ctx, func() {}
+	}
+	drainCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(r.DrainTimeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-drainCtx.Done():
+		}
+	})
+	return drainCtx, func() {
+		stop()
+		cancel()
+	}
+}
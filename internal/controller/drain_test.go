@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("Shutdown drain", func() {
+	// shutdownOnCreate cancels the reconcile, like a SIGTERM, on the first
+	// create, and fails creates with a cancelled context like a real client.
+	shutdownOnCreate := func(cancel context.CancelFunc) interceptor.Funcs {
+		return interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				cancel()
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}
+	}
+
+	It("should finish the namespace in flight but not start further ones", func() {
+		class := newNamespaceClass("drain-class",
+			mustRawConfigMap("first", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("second", map[string]string{"foo": "bar"}),
+		)
+		nsA := newNamespace("drain-a", class.Name)
+		nsB := newNamespace("drain-b", class.Name)
+
+		var cancel context.CancelFunc
+		r, _, ctx := setupTestReconcilerWithInterceptor(shutdownOnCreate(func() { cancel() }), class, nsA, nsB)
+		r.DrainTimeout = time.Minute
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+
+		applied := [][]string{}
+		for _, ns := range []string{nsA.Name, nsB.Name} {
+			var names []string
+			for _, cm := range listConfigMaps(r.Client, context.Background(), ns) {
+				names = append(names, cm.Name)
+			}
+			applied = append(applied, names)
+		}
+		Expect(applied).To(ConsistOf(ConsistOf("first", "second"), BeEmpty()))
+	})
+
+	It("should stop the apply in flight without a drain timeout", func() {
+		class := newNamespaceClass("no-drain-class",
+			mustRawConfigMap("first", map[string]string{"foo": "bar"}),
+		)
+		ns := newNamespace("no-drain-ns", class.Name)
+
+		var cancel context.CancelFunc
+		r, _, ctx := setupTestReconcilerWithInterceptor(shutdownOnCreate(func() { cancel() }), class, ns)
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+
+		_, _ = r.Reconcile(ctx, requestFor(class))
+		Expect(listConfigMaps(r.Client, context.Background(), ns.Name)).To(BeEmpty())
+	})
+
+	It("should not start a reconcile once shutting down", func() {
+		class := newNamespaceClass("late-class", mustRawConfigMap("first", map[string]string{"foo": "bar"}))
+		ns := newNamespace("late-ns", class.Name)
+		r, _, ctx := setupTestReconciler(class, ns)
+		r.DrainTimeout = time.Minute
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, context.Background(), ns.Name)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// writer returns the client used to write injected resources. In dry-run mode
+// every write is sent with DryRunAll, so the API server validates it without
+// persisting anything. Writes of NamespaceClasses and Namespaces do not go
+// through it, since the operator's own bookkeeping is still needed. Each write
+// is bounded by ApplyTimeout, if set.
+func (r *NamespaceClassReconciler) writer() client.Writer {
+	var w client.Writer = r.Client
+	if r.DryRun {
+		w = client.NewDryRunClient(r.Client)
+	}
+	if r.ApplyTimeout > 0 {
+		w = timeoutWriter{Writer: w, timeout: r.ApplyTimeout}
+	}
+	return w
+}
+
+// reportDryRun logs the changes a dry run would have made to ns and reports
+// them with a DryRun event on the namespace.
+func (r *NamespaceClassReconciler) reportDryRun(log logr.Logger, ns *corev1.Namespace, className string, changes []string) {
+	if len(changes) == 0 {
+		return
+	}
+	log.Info("Dry run; changes not persisted", "changes", changes)
+	r.Recorder.Eventf(ns, corev1.EventTypeNormal, "DryRun",
+		"NamespaceClass '%s' would %s", className, strings.Join(changes, ", "))
+}
+
+// prefixAll returns refs with prefix prepended to each entry.
+func prefixAll(prefix string, refs []string) []string {
+	out := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, prefix+ref)
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("DryRun", func() {
+	It("should report planned creates without creating anything", func() {
+		ns := newNamespace("dry-ns", "dry-class")
+		class := newNamespaceClass("dry-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+		r.DryRun = true
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.PendingChanges).To(ConsistOf("dry-ns: apply ConfigMap/cfg"))
+		Expect(persisted.Status.LastAppliedResources).To(BeEmpty())
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(And(
+			ContainSubstring("DryRun"), ContainSubstring("apply ConfigMap/cfg"))))
+
+		var live corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &live)).To(Succeed())
+		Expect(live.Annotations).NotTo(HaveKey(controller.NamespaceClassAppliedHashKey))
+	})
+
+	It("should report planned updates and deletions without persisting them", func() {
+		ns := newNamespace("dry-update-ns", "dry-update-class")
+		class := newNamespaceClass("dry-update-class",
+			mustRawConfigMap("cfg", map[string]string{"foo": "new"}),
+			mustRawConfigMap("same", map[string]string{"foo": "bar"}),
+			mustRawDeletionMarker("stale"),
+		)
+		same := newInjectedConfigMap("same", ns.Name, map[string]string{"foo": "bar"})
+		same.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class.Name}
+		r, _, ctx := setupTestReconciler(ns, class,
+			newInjectedConfigMap("cfg", ns.Name, map[string]string{"foo": "old"}),
+			same,
+			newInjectedConfigMap("stale", ns.Name, map[string]string{"foo": "bar"}),
+		)
+		r.DryRun = true
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(3))
+		for _, cm := range cms {
+			if cm.Name == "cfg" {
+				Expect(cm.Data).To(HaveKeyWithValue("foo", "old"))
+			}
+		}
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.PendingChanges).To(ConsistOf(
+			"dry-update-ns: apply ConfigMap/cfg",
+			"dry-update-ns: delete ConfigMap/stale",
+		))
+	})
+
+	It("should report nothing when the namespace is in sync", func() {
+		ns := newNamespace("dry-sync-ns", "dry-sync-class")
+		class := newNamespaceClass("dry-sync-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		r.DryRun = true
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.PendingChanges).To(BeEmpty())
+	})
+})
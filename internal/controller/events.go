@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// eventCache remembers when a warning was last emitted for an object, so that
+// a condition that persists across reconciles, such as a namespace that
+// references a missing class, is reported once per cooldown rather than on
+// every reconcile.
+type eventCache struct {
+	now func() time.Time
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func newEventCache(now func() time.Time) *eventCache {
+	return &eventCache{now: now, last: map[string]time.Time{}}
+}
+
+func eventCacheKey(obj client.Object, reason string) string {
+	return obj.GetNamespace() + "/" + obj.GetName() + "/" + reason + "/"
+}
+
+// allow reports whether the event with reason and message may be emitted for
+// obj, that is whether it was not emitted within cooldown, and records it if
+// so. The message is part of the key, so that e.g. warnings about two
+// different missing classes do not suppress each other.
+func (c *eventCache) allow(obj client.Object, reason, message string, cooldown time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	key := eventCacheKey(obj, reason) + message
+	if last, ok := c.last[key]; ok && now.Sub(last) < cooldown {
+		return false
+	}
+	// Drop expired entries so that deleted namespaces do not pile up
+	for k, last := range c.last {
+		if now.Sub(last) >= cooldown {
+			delete(c.last, k)
+		}
+	}
+	c.last[key] = now
+	return true
+}
+
+// forget makes the next events with reason for obj be emitted right away, once
+// the condition they report has cleared.
+func (c *eventCache) forget(obj client.Object, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prefix := eventCacheKey(obj, reason)
+	for k := range c.last {
+		if strings.HasPrefix(k, prefix) {
+			delete(c.last, k)
+		}
+	}
+}
+
+func (r *NamespaceClassReconciler) eventCache() *eventCache {
+	r.eventsOnce.Do(func() {
+		if r.events == nil {
+			r.events = newEventCache(time.Now)
+		}
+	})
+	return r.events
+}
+
+// warnOnce emits a Warning event on obj unless the same warning was emitted
+// for it within EventCooldown.
+func (r *NamespaceClassReconciler) warnOnce(obj client.Object, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if r.EventCooldown > 0 && !r.eventCache().allow(obj, reason, message, r.EventCooldown) {
+		return
+	}
+	r.Recorder.Event(obj, corev1.EventTypeWarning, reason, message)
+}
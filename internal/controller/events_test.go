@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Event cooldown", func() {
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	setup := func(objs ...client.Object) (*controller.NamespaceClassReconciler, context.Context) {
+		r, _, ctx := setupTestReconciler(objs...)
+		r.EventCooldown = 10 * time.Minute
+		r.WithEventClock(func() time.Time { return now })
+		return r, ctx
+	}
+
+	It("should report a missing class once within the cooldown", func() {
+		ns := newNamespace("test-ns", "missing-class")
+		r, ctx := setup(ns)
+		events := r.Recorder.(*record.FakeRecorder).Events
+
+		for range 3 {
+			_, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+			now = now.Add(time.Minute)
+		}
+		Expect(events).To(Receive(ContainSubstring("MissingNamespaceClass")))
+		Expect(events).NotTo(Receive())
+
+		now = now.Add(10 * time.Minute)
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(Receive(ContainSubstring("MissingNamespaceClass")))
+	})
+
+	It("should report a missing class again once it existed in between", func() {
+		ns := newNamespace("test-ns", "flaky-class")
+		r, ctx := setup(ns)
+		events := r.Recorder.(*record.FakeRecorder).Events
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(Receive(ContainSubstring("MissingNamespaceClass")))
+
+		class := newNamespaceClass("flaky-class")
+		Expect(r.Create(ctx, class)).To(Succeed())
+		_, err = r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Delete(ctx, class)).To(Succeed())
+		_, err = r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(events).Should(Receive(ContainSubstring("MissingNamespaceClass")))
+	})
+})
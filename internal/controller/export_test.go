@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Exported for tests in the controller_test package.
+var (
+	MapNamespaceToNamespaceClass = (*NamespaceClassReconciler).mapNamespaceToNamespaceClass
+	ClassChanged                 = classChanged
+	NamespaceChanged             = namespaceChanged
+	NamespaceClassIndex          = classLabelIndex(NamespaceClassNameKey)
+
+	MapResourceRefToNamespaceClasses     = (*NamespaceClassReconciler).mapResourceRefToNamespaceClasses
+	MapParentToChildClasses              = (*NamespaceClassReconciler).mapParentToChildClasses
+	EffectiveClass                       = (*NamespaceClassReconciler).effectiveClass
+	ClassLabelIndex                      = classLabelIndex
+	NewReconcileHealthWithClock          = newReconcileHealth
+	ControllerOptions                    = (*NamespaceClassReconciler).controllerOptions
+	NamespaceRequest                     = namespaceRequest
+	NamespaceKey                         = namespaceKey
+	MapAdditionalClassToNamespaceClasses = (*NamespaceClassReconciler).mapAdditionalClassToNamespaceClasses
+	MapSecretRefToNamespaceClasses       = (*NamespaceClassReconciler).mapSecretRefToNamespaceClasses
+	ConditionMessage                     = conditionMessage
+)
+
+// WithRequestQueue makes the reconciler enqueue its own requests, such as
+// retries of single namespaces, to the returned channel instead of a manager.
+func (r *NamespaceClassReconciler) WithRequestQueue(size int) <-chan event.TypedGenericEvent[reconcile.Request] {
+	r.requests = make(chan event.TypedGenericEvent[reconcile.Request], size)
+	return r.requests
+}
+
+// WithEventClock makes the reconciler read the time from now when it decides
+// whether to repeat a warning.
+func (r *NamespaceClassReconciler) WithEventClock(now func() time.Time) {
+	r.events = newEventCache(now)
+}
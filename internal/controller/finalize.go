@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// giveUpFinalizing counts a failed cleanup of the deleted class and reports
+// whether its finalizer is to be removed anyway. Once MaxFinalizeAttempts is
+// reached a warning Event is emitted, and with ForceFinalize the finalizer is
+// removed, leaving behind whatever could not be cleaned up, rather than keeping
+// the class in Terminating for as long as e.g. an aggregated API is down.
+func (r *NamespaceClassReconciler) giveUpFinalizing(log logr.Logger, class *v1alpha1.NamespaceClass, err error) bool {
+	if r.MaxFinalizeAttempts <= 0 {
+		return false
+	}
+	attempts := r.countFinalizeAttempt(class.UID)
+	if attempts < r.MaxFinalizeAttempts {
+		return false
+	}
+	if attempts == r.MaxFinalizeAttempts {
+		r.Recorder.Eventf(class, corev1.EventTypeWarning, "CleanupFailed",
+			"Cleanup of NamespaceClass '%s' failed %d times: %v", class.Name, attempts, err)
+	}
+	if !r.ForceFinalize {
+		return false
+	}
+
+	log.Error(err, "Removing finalizer after failed cleanup", "attempts", attempts)
+	r.Recorder.Eventf(class, corev1.EventTypeWarning, "FinalizerRemoved",
+		"Removed the finalizer of NamespaceClass '%s' after %d failed cleanups; its resources may be left behind",
+		class.Name, attempts)
+	return true
+}
+
+// countFinalizeAttempt records another failed cleanup of the class with the
+// given UID and returns how many there were.
+func (r *NamespaceClassReconciler) countFinalizeAttempt(uid types.UID) int {
+	r.finalizeMu.Lock()
+	defer r.finalizeMu.Unlock()
+	if r.finalizeAttempts == nil {
+		r.finalizeAttempts = map[types.UID]int{}
+	}
+	r.finalizeAttempts[uid]++
+	return r.finalizeAttempts[uid]
+}
+
+// forgetFinalizeAttempts drops the count of a class that is gone.
+func (r *NamespaceClassReconciler) forgetFinalizeAttempts(uid types.UID) {
+	r.finalizeMu.Lock()
+	defer r.finalizeMu.Unlock()
+	delete(r.finalizeAttempts, uid)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Finalizing a NamespaceClass", func() {
+	// unavailable fails every namespace list, like an API server that cannot
+	// serve the cleanup
+	unavailable := interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if _, ok := list.(*corev1.NamespaceList); ok {
+				return errors.New("the server is currently unable to handle the request")
+			}
+			return c.List(ctx, list, opts...)
+		},
+	}
+
+	finalize := func(r *controller.NamespaceClassReconciler, ctx context.Context, class *v1alpha1.NamespaceClass) error {
+		_, err := r.Reconcile(ctx, requestFor(class))
+		return err
+	}
+
+	It("should keep the finalizer while the cleanup fails", func() {
+		ns := newNamespace("stuck-ns", "stuck-class")
+		setCleanupAnnotation(ns)
+		class := newDeletedNamespaceClass("stuck-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+
+		r, _, ctx := setupTestReconcilerWithInterceptor(unavailable, ns, class)
+		r.MaxFinalizeAttempts = 2
+
+		for range 4 {
+			Expect(finalize(r, ctx, class)).To(HaveOccurred())
+		}
+
+		var current v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &current)).To(Succeed())
+		Expect(current.Finalizers).To(ContainElement(controller.NamespaceClassFinalizerKey))
+
+		events := r.Recorder.(*record.FakeRecorder).Events
+		Expect(events).To(Receive(ContainSubstring("CleanupFailed")))
+		Expect(events).NotTo(Receive())
+	})
+
+	It("should not report failures without an attempt limit", func() {
+		class := newDeletedNamespaceClass("unlimited-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+
+		r, _, ctx := setupTestReconcilerWithInterceptor(unavailable, class)
+		r.ForceFinalize = true
+
+		for range 5 {
+			Expect(finalize(r, ctx, class)).To(HaveOccurred())
+		}
+		Expect(r.Recorder.(*record.FakeRecorder).Events).NotTo(Receive())
+	})
+
+	It("should remove the finalizer once the attempts run out when forced", func() {
+		ns := newNamespace("forced-ns", "forced-class")
+		setCleanupAnnotation(ns)
+		class := newDeletedNamespaceClass("forced-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+
+		r, _, ctx := setupTestReconcilerWithInterceptor(unavailable, ns, class)
+		r.MaxFinalizeAttempts = 3
+		r.ForceFinalize = true
+
+		Expect(finalize(r, ctx, class)).To(HaveOccurred())
+		Expect(finalize(r, ctx, class)).To(HaveOccurred())
+		Expect(finalize(r, ctx, class)).To(Succeed())
+
+		err := r.Get(ctx, types.NamespacedName{Name: class.Name}, &v1alpha1.NamespaceClass{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		events := r.Recorder.(*record.FakeRecorder).Events
+		Expect(events).To(Receive(ContainSubstring("CleanupFailed")))
+		Expect(events).To(Receive(ContainSubstring("FinalizerRemoved")))
+	})
+})
@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// forceRequested reports whether class carries a force-reconcile value that
+// has not been handled yet.
+func forceRequested(class *v1alpha1.NamespaceClass) bool {
+	value := class.Annotations[NamespaceClassForceReconcileKey]
+	return value != "" && value != class.Status.LastHandledForceReconcile
+}
+
+type forceApplyKey struct{}
+
+// withForceApply returns a context under which namespaces are applied even if
+// their applied hash matches, and objects are written even if they look
+// unchanged.
+func withForceApply(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceApplyKey{}, true)
+}
+
+// forceApply reports whether ctx was returned by withForceApply.
+func forceApply(ctx context.Context) bool {
+	forced, _ := ctx.Value(forceApplyKey{}).(bool)
+	return forced
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Force reconcile", func() {
+	It("should re-apply every resource once per new annotation value", func() {
+		class := newNamespaceClass("force-class",
+			mustRawConfigMap("cfg-a", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("cfg-b", map[string]string{"foo": "bar"}),
+		)
+		nsA := newNamespace("force-a", class.Name)
+		nsB := newNamespace("force-b", class.Name)
+		updates := 0
+		r, _, ctx := setupTestReconcilerWithInterceptor(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if obj.GetObjectKind().GroupVersionKind().Kind == "ConfigMap" {
+					updates++
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}, class, nsA, nsB)
+
+		force := func(value string) {
+			var persisted v1alpha1.NamespaceClass
+			Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+			persisted.Annotations = map[string]string{controller.NamespaceClassForceReconcileKey: value}
+			Expect(r.Update(ctx, &persisted)).To(Succeed())
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updates).To(BeZero())
+
+		By("forcing a re-apply")
+		force("2025-01-01T00:00:00Z")
+		Expect(updates).To(Equal(4))
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.LastHandledForceReconcile).To(Equal("2025-01-01T00:00:00Z"))
+
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		Expect(events).To(ContainElement(ContainSubstring("ForceReconcile")))
+
+		By("not forcing again for a handled value")
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updates).To(Equal(4))
+
+		By("forcing again for a new value")
+		force("2025-01-02T00:00:00Z")
+		Expect(updates).To(Equal(8))
+	})
+
+	It("should retry resources that ran out of retries", func() {
+		class := newNamespaceClass("force-retries-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		ns := newNamespace("force-retries-ns", class.Name)
+		class.Annotations = map[string]string{controller.NamespaceClassForceReconcileKey: "now"}
+		class.Status.ResourceFailures = []v1alpha1.ResourceFailure{{
+			Namespace: ns.Name, Resource: "ConfigMap/cfg", Attempts: 1, Failed: true,
+		}}
+		r, _, ctx := setupTestReconciler(class, ns)
+		r.MaxRetries = 1
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.ResourceFailures).To(BeEmpty())
+	})
+})
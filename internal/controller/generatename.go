@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// generatedNameLength is the number of random characters appended to the
+// generateName prefix, like the API server does.
+const generatedNameLength = 5
+
+// resolveGeneratedNames names the objects that only set metadata.generateName.
+// An object of the same kind in the same namespace that the class created from
+// the same prefix before keeps its name, so that every reconcile updates it
+// instead of creating yet another copy. Otherwise a new name is generated up
+// front, since the apply paths need a name to find the object again. Objects
+// whose previous name cannot be looked up are dropped, as creating them could
+// duplicate the existing one, and complete is false then.
+func (r *NamespaceClassReconciler) resolveGeneratedNames(
+	ctx context.Context,
+	log logr.Logger,
+	objs []*unstructured.Unstructured,
+) (resolved []*unstructured.Unstructured, complete bool) {
+	complete = true
+	resolved = objs[:0]
+	for _, obj := range objs {
+		prefix := obj.GetGenerateName()
+		if obj.GetName() != "" || prefix == "" || isPatch(obj) {
+			resolved = append(resolved, obj)
+			continue
+		}
+
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[NamespaceClassGenerateNameKey] = prefix
+		obj.SetAnnotations(annotations)
+
+		name, err := r.generatedName(ctx, obj, obj.GetLabels()[NamespaceClassOwnedByKey])
+		if err != nil {
+			withResource(log, obj.GroupVersionKind(), prefix).Error(err, "Failed to look up generated name")
+			complete = false
+			continue
+		}
+		if name == "" {
+			name = prefix + utilrand.String(generatedNameLength)
+		}
+		obj.SetName(name)
+		resolved = append(resolved, obj)
+	}
+	return resolved, complete
+}
+
+// generatedName returns the name of the object of obj's kind and namespace
+// that className created from obj's generateName prefix, or "" when there is
+// none. The API server is asked when possible, since an object created by the
+// previous reconcile may not have reached the cache yet.
+func (r *NamespaceClassReconciler) generatedName(
+	ctx context.Context,
+	obj *unstructured.Unstructured,
+	className string,
+) (string, error) {
+	var reader client.Reader = r.Client
+	if r.APIReader != nil {
+		reader = r.APIReader
+	}
+
+	gvk := obj.GroupVersionKind()
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := reader.List(ctx, list, client.InNamespace(obj.GetNamespace()),
+		client.MatchingLabels{NamespaceClassOwnedByKey: className}); err != nil {
+		return "", fmt.Errorf("listing %s: %w", gvk.Kind, err)
+	}
+
+	var names []string
+	for _, item := range list.Items {
+		if item.GetAnnotations()[NamespaceClassGenerateNameKey] == obj.GetGenerateName() {
+			names = append(names, item.GetName())
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	// Should several exist, e.g. after two reconciles raced, settle
+	// on the same one every time
+	sort.Strings(names)
+	return names[0], nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("generateName", func() {
+	It("should create a single object across reconciles", func() {
+		ns := newNamespace("genname-ns", "genname-class")
+		class := newNamespaceClass("genname-class", mustRawGeneratedConfigMap("settings-", map[string]string{"foo": "bar"}))
+
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		for range 3 {
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Name).To(HavePrefix("settings-"))
+		Expect(cms[0].Name).To(HaveLen(len("settings-") + 5))
+		Expect(cms[0].Annotations).To(HaveKeyWithValue(controller.NamespaceClassGenerateNameKey, "settings-"))
+	})
+
+	It("should update the generated object when the class changes", func() {
+		ns := newNamespace("genname-update-ns", "genname-update-class")
+		class := newNamespaceClass("genname-update-class",
+			mustRawGeneratedConfigMap("settings-", map[string]string{"foo": "bar"}))
+
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		name := listConfigMaps(r.Client, ctx, ns.Name)[0].Name
+
+		var current v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &current)).To(Succeed())
+		current.Spec.Resources = []runtime.RawExtension{
+			mustRawGeneratedConfigMap("settings-", map[string]string{"foo": "baz"}),
+		}
+		Expect(r.Update(ctx, &current)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Name).To(Equal(name))
+		Expect(cms[0].Data).To(HaveKeyWithValue("foo", "baz"))
+	})
+
+	It("should generate a name per namespace and prefix", func() {
+		first := newNamespace("genname-first", "genname-multi")
+		second := newNamespace("genname-second", "genname-multi")
+		class := newNamespaceClass("genname-multi",
+			mustRawGeneratedConfigMap("settings-", map[string]string{"foo": "bar"}),
+			mustRawGeneratedConfigMap("limits-", map[string]string{"cpu": "1"}),
+		)
+
+		r, _, ctx := setupTestReconciler(first, second, class)
+
+		for range 2 {
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		for _, ns := range []string{first.Name, second.Name} {
+			cms := listConfigMaps(r.Client, ctx, ns)
+			Expect(cms).To(HaveLen(2))
+			prefixes := []string{}
+			for _, cm := range cms {
+				prefixes = append(prefixes, cm.Annotations[controller.NamespaceClassGenerateNameKey])
+				Expect(strings.HasPrefix(cm.Name, cm.Annotations[controller.NamespaceClassGenerateNameKey])).To(BeTrue())
+			}
+			Expect(prefixes).To(ConsistOf("settings-", "limits-"))
+		}
+	})
+
+	It("should delete the generated object with the class", func() {
+		ns := newNamespace("genname-cleanup-ns", "genname-cleanup-class")
+		setCleanupAnnotation(ns)
+		class := newNamespaceClass("genname-cleanup-class",
+			mustRawGeneratedConfigMap("settings-", map[string]string{"foo": "bar"}))
+
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+
+		var current v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &current)).To(Succeed())
+		Expect(r.Delete(ctx, &current)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+	})
+})
+
+func mustRawGeneratedConfigMap(prefix string, data map[string]string) runtime.RawExtension {
+	cm := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{GenerateName: prefix},
+		Data:       data,
+	}
+	raw, err := json.Marshal(cm)
+	Expect(err).NotTo(HaveOccurred())
+	return runtime.RawExtension{Raw: raw}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Observed generation", func() {
+	It("should ignore status-only changes of a NamespaceClass", func() {
+		old := newNamespaceClass("gen-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		old.Generation = 1
+		old.ResourceVersion = "1"
+
+		updated := old.DeepCopy()
+		updated.ResourceVersion = "2"
+		updated.Status.LastAppliedResources = []runtime.RawExtension{}
+		Expect(controller.ClassChanged.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated})).To(BeFalse())
+
+		updated.Generation = 2
+		Expect(controller.ClassChanged.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated})).To(BeTrue())
+	})
+
+	It("should not write anything when the observed generation is current", func() {
+		ns := newNamespace("gen-ns", "gen-class")
+		class := newNamespaceClass("gen-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+
+		writes := 0
+		r, _, ctx := setupTestReconcilerWithInterceptor(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				writes++
+				return c.Create(ctx, obj, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				writes++
+				return c.Update(ctx, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				writes++
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				writes++
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}, ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writes).To(BeNumerically(">", 0))
+
+		writes = 0
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writes).To(BeZero())
+	})
+
+	It("should populate the reconcile summary in the status", func() {
+		ns := newNamespace("summary-ns", "summary-class")
+		class := newNamespaceClass("summary-class",
+			mustRawConfigMap("first", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("second", map[string]string{"foo": "bar"}),
+		)
+		class.Generation = 4
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.ObservedGeneration).To(Equal(int64(4)))
+		Expect(persisted.Status.AppliedResourceCount).To(Equal(2))
+		Expect(persisted.Status.LastReconcileTime.IsZero()).To(BeFalse())
+	})
+
+	It("should not advance the observed generation when a resource fails to apply", func() {
+		ns := newNamespace("stuck-ns", "stuck-class")
+		class := newNamespaceClass("stuck-class",
+			mustRawConfigMap("cfg", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("", map[string]string{"missing": "name"}),
+		)
+		class.Generation = 2
+		class.Status.ObservedGeneration = 1
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.ObservedGeneration).To(Equal(int64(1)))
+		Expect(persisted.Status.LastReconcileTime.IsZero()).To(BeFalse())
+	})
+
+	It("should stamp injected resources with the generation of their class", func() {
+		ns := newNamespace("stamp-ns", "stamp-class")
+		class := newNamespaceClass("stamp-class",
+			mustRawConfigMap("cfg", map[string]string{"foo": "bar"}),
+			mustRawServiceAccount("robot"),
+		)
+		class.Generation = 1
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "cfg", Namespace: ns.Name}, &cm)).To(Succeed())
+		Expect(cm.Annotations).To(HaveKeyWithValue(controller.NamespaceClassGenerationKey, "1"))
+		var robot corev1.ServiceAccount
+		Expect(r.Get(ctx, types.NamespacedName{Name: "robot", Namespace: ns.Name}, &robot)).To(Succeed())
+		saHash := robot.Annotations[controller.NamespaceClassContentHashKey]
+
+		By("editing the class")
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		persisted.Spec.Resources[0] = mustRawConfigMap("cfg", map[string]string{"foo": "baz"})
+		persisted.Generation = 2
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: "cfg", Namespace: ns.Name}, &cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("foo", "baz"))
+		Expect(cm.Annotations).To(HaveKeyWithValue(controller.NamespaceClassGenerationKey, "2"))
+
+		// Resources the edit did not touch only get the new generation
+		var sa corev1.ServiceAccount
+		Expect(r.Get(ctx, types.NamespacedName{Name: "robot", Namespace: ns.Name}, &sa)).To(Succeed())
+		Expect(sa.Annotations).To(HaveKeyWithValue(controller.NamespaceClassGenerationKey, "2"))
+		Expect(sa.Annotations).To(HaveKeyWithValue(controller.NamespaceClassContentHashKey, saHash))
+	})
+
+	It("should not rewrite resources when only the generation of their class changes", func() {
+		ns := newNamespace("restamp-ns", "restamp-class")
+		class := newNamespaceClass("restamp-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		class.Generation = 1
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "cfg", Namespace: ns.Name}, &cm)).To(Succeed())
+		resourceVersion := cm.ResourceVersion
+		var applied corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &applied)).To(Succeed())
+		appliedHash := applied.Annotations[controller.NamespaceClassAppliedHashKey]
+
+		By("bumping the generation of the class")
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		persisted.Generation = 2
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: "cfg", Namespace: ns.Name}, &cm)).To(Succeed())
+		Expect(cm.ResourceVersion).To(Equal(resourceVersion))
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &applied)).To(Succeed())
+		Expect(applied.Annotations[controller.NamespaceClassAppliedHashKey]).To(Equal(appliedHash))
+	})
+})
@@ -0,0 +1,186 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// hashResources returns a stable digest of the full set of objects applied to a
+// namespace. The generation of the class the objects are stamped with is left
+// out, so that editing one resource of a class does not change the digest of
+// the others.
+func hashResources(objs []*unstructured.Unstructured) string {
+	h := sha256.New()
+	for _, obj := range objs {
+		// Marshalling a map sorts its keys, so equal objects hash equally.
+		data, err := json.Marshal(withoutAnnotations(obj, NamespaceClassGenerationKey).Object)
+		if err != nil {
+			return ""
+		}
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// withoutAnnotations returns obj, or a copy of it without the given
+// annotations if it has any of them.
+func withoutAnnotations(obj *unstructured.Unstructured, keys ...string) *unstructured.Unstructured {
+	annotations := obj.GetAnnotations()
+	found := false
+	for _, key := range keys {
+		if _, ok := annotations[key]; ok {
+			found = true
+		}
+	}
+	if !found {
+		return obj
+	}
+	obj = obj.DeepCopy()
+	annotations = obj.GetAnnotations()
+	for _, key := range keys {
+		delete(annotations, key)
+	}
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+// inSync reports whether the namespace was last applied with the same resource
+// set and every object still matches what the class defines.
+func (r *NamespaceClassReconciler) inSync(ctx context.Context, ns *corev1.Namespace, hash string, objs []*unstructured.Unstructured) bool {
+	if hash == "" || ns.Annotations[NamespaceClassAppliedHashKey] != hash {
+		return false
+	}
+	return !r.driftDetected(ctx, objs)
+}
+
+// driftDetected reports whether any of the objects is missing from the cluster,
+// still present although marked for deletion, or has fields that differ from
+// the desired state. Fields that are only present
+// on the live object, such as server-side defaults, are not considered drift.
+func (r *NamespaceClassReconciler) driftDetected(ctx context.Context, objs []*unstructured.Unstructured) bool {
+	for _, obj := range objs {
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		key := types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+		err := r.Get(ctx, key, live)
+		if isDeletion(obj) {
+			if !apierrors.IsNotFound(err) {
+				return true
+			}
+			continue
+		}
+		if err != nil {
+			return true
+		}
+		if isCreateOnly(obj) || isImmutable(obj) {
+			// Changes to create-only and immutable resources are expected.
+			continue
+		}
+		if !matchesLive(obj, live) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesLive reports whether every field and label that obj sets has the same
+// value on live.
+func matchesLive(obj, live *unstructured.Unstructured) bool {
+	for field, desired := range obj.Object {
+		if field == "metadata" || field == "status" {
+			continue
+		}
+		if !isSubset(desired, live.Object[field]) {
+			return false
+		}
+	}
+	for key, value := range obj.GetLabels() {
+		if live.GetLabels()[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// setContentHash records a digest of obj as it is defined by its class in the
+// NamespaceClassContentHashKey annotation. An object whose recorded hash
+// matches was last applied from the same definition.
+func setContentHash(obj *unstructured.Unstructured) {
+	hash := contentHash(obj)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[NamespaceClassContentHashKey] = hash
+	obj.SetAnnotations(annotations)
+}
+
+// contentHash returns the digest setContentHash records for obj.
+func contentHash(obj *unstructured.Unstructured) string {
+	return hashResources([]*unstructured.Unstructured{withoutAnnotations(obj, NamespaceClassContentHashKey)})
+}
+
+// unchanged reports whether updating live with obj would be a no-op: live was
+// last applied from the same definition, which catches fields dropped from the
+// class, and still matches it, which catches edits by others. An object that
+// only differs in the generation of its class counts as unchanged; see
+// restampGeneration.
+func unchanged(obj, live *unstructured.Unstructured) bool {
+	hash := obj.GetAnnotations()[NamespaceClassContentHashKey]
+	return hash != "" && live.GetAnnotations()[NamespaceClassContentHashKey] == hash && matchesLive(obj, live)
+}
+
+// isSubset reports whether every field set in desired has the same value in live.
+func isSubset(desired, live interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range d {
+			if !isSubset(value, l[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !isSubset(d[i], l[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(desired, live)
+	}
+}
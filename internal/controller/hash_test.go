@@ -0,0 +1,202 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Applied hash", func() {
+	var updates int
+
+	countConfigMapUpdates := interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if obj.GetObjectKind().GroupVersionKind().Kind == "ConfigMap" {
+				updates++
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}
+
+	BeforeEach(func() {
+		updates = 0
+	})
+
+	It("should stamp the namespace with the hash of the applied resources", func() {
+		ns := newNamespace("hash-ns", "hash-class")
+		class := newNamespaceClass("hash-class", mustRawConfigMap("hashed", map[string]string{"foo": "bar"}))
+
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(namespaceAnnotations(r.Client, ctx, ns.Name)).To(HaveKeyWithValue(controller.NamespaceClassAppliedHashKey, Not(BeEmpty())))
+	})
+
+	It("should skip redundant applies when the hash matches", func() {
+		ns := newNamespace("hash-ns", "hash-class")
+		class := newNamespaceClass("hash-class", mustRawConfigMap("hashed", map[string]string{"foo": "bar"}))
+
+		r, _, ctx := setupTestReconcilerWithInterceptor(countConfigMapUpdates, ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updates).To(BeZero())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updates).To(BeZero())
+	})
+
+	It("should re-apply when an injected resource drifted", func() {
+		ns := newNamespace("hash-ns", "hash-class")
+		class := newNamespaceClass("hash-class", mustRawConfigMap("hashed", map[string]string{"foo": "bar"}))
+
+		r, _, ctx := setupTestReconcilerWithInterceptor(countConfigMapUpdates, ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "hashed", Namespace: ns.Name}, &cm)).To(Succeed())
+		cm.Data["foo"] = "edited"
+		Expect(r.Update(ctx, &cm)).To(Succeed())
+		updates = 0
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updates).To(Equal(1))
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: "hashed", Namespace: ns.Name}, &cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("foo", "bar"))
+	})
+
+	It("should change the hash when the class changes", func() {
+		ns := newNamespace("hash-ns", "hash-class")
+		class := newNamespaceClass("hash-class", mustRawConfigMap("hashed", map[string]string{"foo": "bar"}))
+
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		before := namespaceAnnotations(r.Client, ctx, ns.Name)[controller.NamespaceClassAppliedHashKey]
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		persisted.Spec.Resources = []runtime.RawExtension{mustRawConfigMap("hashed", map[string]string{"foo": "baz"})}
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		after := namespaceAnnotations(r.Client, ctx, ns.Name)[controller.NamespaceClassAppliedHashKey]
+		Expect(after).NotTo(Equal(before))
+	})
+})
+
+func namespaceAnnotations(c client.Client, ctx context.Context, name string) map[string]string {
+	var ns corev1.Namespace
+	Expect(c.Get(ctx, types.NamespacedName{Name: name}, &ns)).To(Succeed())
+	return ns.Annotations
+}
+
+var _ = Describe("Content hash", func() {
+	var updated []string
+
+	recordConfigMapUpdates := interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if obj.GetObjectKind().GroupVersionKind().Kind == "ConfigMap" {
+				updated = append(updated, obj.GetName())
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}
+
+	editClass := func(ctx context.Context, c client.Client, name string, resources ...runtime.RawExtension) {
+		var persisted v1alpha1.NamespaceClass
+		Expect(c.Get(ctx, types.NamespacedName{Name: name}, &persisted)).To(Succeed())
+		persisted.Spec.Resources = resources
+		Expect(c.Update(ctx, &persisted)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		updated = nil
+	})
+
+	It("should record the content hash on injected resources", func() {
+		ns := newNamespace("content-ns", "content-class")
+		class := newNamespaceClass("content-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Annotations).To(HaveKeyWithValue(controller.NamespaceClassContentHashKey, Not(BeEmpty())))
+	})
+
+	It("should not update resources whose definition did not change", func() {
+		ns := newNamespace("noop-ns", "noop-class")
+		class := newNamespaceClass("noop-class",
+			mustRawConfigMap("stable", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("changing", map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconcilerWithInterceptor(recordConfigMapUpdates, ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		editClass(ctx, r.Client, class.Name,
+			mustRawConfigMap("stable", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("changing", map[string]string{"foo": "baz"}),
+		)
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(updated).To(Equal([]string{"changing"}))
+	})
+
+	It("should update a resource when a field was dropped from the class", func() {
+		ns := newNamespace("dropped-ns", "dropped-class")
+		class := newNamespaceClass("dropped-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar", "old": "value"}))
+		r, _, ctx := setupTestReconcilerWithInterceptor(recordConfigMapUpdates, ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		editClass(ctx, r.Client, class.Name, mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(updated).To(Equal([]string{"cfg"}))
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms[0].Data).To(Equal(map[string]string{"foo": "bar"}))
+	})
+})
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReconcileHealth tracks the outcome of reconciles per class for the
+// readiness probe. It reports unhealthy once the reconciles of a class have
+// kept failing, without any success, for longer than its threshold. A success
+// of one class does not hide another that keeps failing, and an idle operator
+// stays healthy.
+//
+// The reconciles of a class in a single namespace are tracked apart from those
+// of the class as a whole, so that a namespace that keeps failing is not reset
+// by the class reconciles that succeed around it.
+type ReconcileHealth struct {
+	threshold time.Duration
+	now       func() time.Time
+
+	mu sync.Mutex
+	// failingSince holds, per class and namespace, the time of the first
+	// failure since its last success.
+	failingSince map[healthKey]time.Time
+}
+
+// healthKey identifies the reconciles of a class in a namespace, or of the
+// class as a whole when namespace is empty.
+type healthKey struct {
+	className string
+	namespace string
+}
+
+func (k healthKey) String() string {
+	if k.namespace == "" {
+		return k.className
+	}
+	return k.className + " in namespace " + k.namespace
+}
+
+// NewReconcileHealth returns a ReconcileHealth that becomes unhealthy after
+// threshold of failing reconciles of a class.
+func NewReconcileHealth(threshold time.Duration) *ReconcileHealth {
+	return newReconcileHealth(threshold, time.Now)
+}
+
+func newReconcileHealth(threshold time.Duration, now func() time.Time) *ReconcileHealth {
+	return &ReconcileHealth{threshold: threshold, now: now, failingSince: map[healthKey]time.Time{}}
+}
+
+// Record records the outcome of a reconcile of the class in the namespace, or
+// of the class as a whole when namespace is empty. A success only clears the
+// failures recorded for the same class and namespace.
+func (h *ReconcileHealth) Record(className, namespace string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := healthKey{className: className, namespace: namespace}
+	if err == nil {
+		delete(h.failingSince, key)
+		return
+	}
+	if _, ok := h.failingSince[key]; !ok {
+		h.failingSince[key] = h.now()
+	}
+}
+
+// Check implements healthz.Checker.
+func (h *ReconcileHealth) Check(_ *http.Request) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var stale []string
+	for key, since := range h.failingSince {
+		if failing := h.now().Sub(since); failing > h.threshold {
+			stale = append(stale, fmt.Sprintf("%s for %s", key, failing.Round(time.Second)))
+		}
+	}
+	slices.Sort(stale)
+	if len(stale) > 0 {
+		return fmt.Errorf("reconciles have been failing: %s", strings.Join(stale, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Reconcile health", func() {
+	var (
+		now    time.Time
+		health *controller.ReconcileHealth
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		health = controller.NewReconcileHealthWithClock(10*time.Minute, func() time.Time { return now })
+	})
+
+	It("should stay healthy while idle", func() {
+		now = now.Add(time.Hour)
+		Expect(health.Check(nil)).To(Succeed())
+	})
+
+	It("should report unhealthy once reconciles keep failing past the threshold", func() {
+		health.Record("failing", "", errors.New("boom"))
+		now = now.Add(5 * time.Minute)
+		Expect(health.Check(nil)).To(Succeed())
+
+		health.Record("failing", "", errors.New("boom"))
+		now = now.Add(6 * time.Minute)
+		Expect(health.Check(nil)).To(MatchError(ContainSubstring("failing for 11m0s")))
+
+		By("succeeding again")
+		health.Record("failing", "", nil)
+		Expect(health.Check(nil)).To(Succeed())
+	})
+
+	It("should not let the success of one class hide another that keeps failing", func() {
+		health.Record("failing", "", errors.New("boom"))
+		now = now.Add(11 * time.Minute)
+		health.Record("healthy", "", nil)
+
+		Expect(health.Check(nil)).To(MatchError(ContainSubstring("failing for 11m0s")))
+		Expect(health.Check(nil)).NotTo(MatchError(ContainSubstring("healthy")))
+	})
+
+	It("should not let the successes of a class hide a namespace that keeps failing", func() {
+		for range 3 {
+			health.Record("class", "failing-ns", errors.New("boom"))
+			health.Record("class", "", nil)
+			health.Record("class", "healthy-ns", nil)
+			now = now.Add(4 * time.Minute)
+		}
+
+		Expect(health.Check(nil)).To(MatchError(ContainSubstring("class in namespace failing-ns for 12m0s")))
+		Expect(health.Check(nil)).NotTo(MatchError(ContainSubstring("healthy-ns")))
+
+		By("succeeding in the namespace")
+		health.Record("class", "failing-ns", nil)
+		Expect(health.Check(nil)).To(Succeed())
+	})
+
+	It("should record failing reconciles of the controller", func() {
+		ns := newNamespace("health-ns", "health-class")
+		class := newNamespaceClass("health-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconcilerWithInterceptor(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				return errors.New("update failed")
+			},
+		}, ns, class)
+		r.Health = health
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).To(HaveOccurred())
+
+		now = now.Add(11 * time.Minute)
+		Expect(health.Check(nil)).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Immutable resources", func() {
+	immutable := map[string]string{controller.NamespaceClassImmutableKey: "true"}
+
+	editClass := func(ctx context.Context, c client.Client, name string, resources ...runtime.RawExtension) *v1alpha1.NamespaceClass {
+		var class v1alpha1.NamespaceClass
+		Expect(c.Get(ctx, types.NamespacedName{Name: name}, &class)).To(Succeed())
+		class.Spec.Resources = resources
+		Expect(c.Update(ctx, &class)).To(Succeed())
+		return &class
+	}
+
+	It("should leave an immutable resource untouched across class edits", func() {
+		ns := newNamespace("immutable-ns", "immutable-class")
+		class := newNamespaceClass("immutable-class",
+			mustRawAnnotatedConfigMap("token", immutable, map[string]string{"token": "first"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		edited := editClass(ctx, r.Client, class.Name,
+			mustRawAnnotatedConfigMap("token", immutable, map[string]string{"token": "second"}))
+		_, err = r.Reconcile(ctx, requestFor(edited))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Data).To(Equal(map[string]string{"token": "first"}))
+	})
+
+	It("should not delete an immutable resource as obsolete", func() {
+		ns := newNamespace("immutable-obsolete-ns", "immutable-obsolete-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		class := newNamespaceClass("immutable-obsolete-class",
+			mustRawAnnotatedConfigMap("token", immutable, map[string]string{"token": "first"}),
+			mustRawConfigMap("settings", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(2))
+
+		edited := editClass(ctx, r.Client, class.Name, mustRawConfigMap("other", map[string]string{"foo": "bar"}))
+		_, err = r.Reconcile(ctx, requestFor(edited))
+		Expect(err).NotTo(HaveOccurred())
+
+		var names []string
+		for _, cm := range listConfigMaps(r.Client, ctx, ns.Name) {
+			names = append(names, cm.Name)
+		}
+		Expect(names).To(ConsistOf("token", "other"))
+	})
+})
@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Namespace class index", func() {
+	It("should index namespaces by their class label", func() {
+		Expect(controller.NamespaceClassIndex(newNamespace("indexed", "gold"))).To(Equal([]string{"gold"}))
+		Expect(controller.NamespaceClassIndex(newNamespace("unlabeled", ""))).To(BeEmpty())
+	})
+
+	It("should return only the namespaces of the requested class", func() {
+		r, _, ctx := setupTestReconciler(
+			newNamespace("gold-a", "gold"),
+			newNamespace("gold-b", "gold"),
+			newNamespace("silver", "silver"),
+			newNamespace("plain", ""),
+		)
+
+		var nsList corev1.NamespaceList
+		Expect(r.List(ctx, &nsList, client.MatchingFields{controller.NamespaceClassIndexKey: "gold"})).To(Succeed())
+
+		var names []string
+		for _, ns := range nsList.Items {
+			names = append(names, ns.Name)
+		}
+		Expect(names).To(ConsistOf("gold-a", "gold-b"))
+	})
+})
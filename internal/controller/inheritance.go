@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// effectiveClass returns a copy of class whose Resources are the effective set
+// that is applied: the resources of its ancestors, root first, overlaid by its
+// own inline and referenced resources, with repeated resources expanded. Every
+// code path that works on spec.resources, including obsolete cleanup,
+// therefore sees inherited and repeated resources too. A missing parent or an
+// inheritance cycle is reported with a warning Event on class and ends the
+// chain. A parent that cannot be read for another reason, and referenced
+// resources that cannot be read, are left out too but returned as an error
+// along with the class, which callers must then not treat as the complete set.
+func (r *NamespaceClassReconciler) effectiveClass(
+	ctx context.Context,
+	class *v1alpha1.NamespaceClass,
+) (*v1alpha1.NamespaceClass, error) {
+	own, err := r.withReferencedResources(ctx, class)
+	errs := []error{err}
+	chain := []*v1alpha1.NamespaceClass{own}
+	visited := []string{class.Name}
+	for parentName := class.Spec.Extends; parentName != ""; {
+		if slices.Contains(visited, parentName) {
+			r.Recorder.Eventf(class, corev1.EventTypeWarning, "InheritanceCycle",
+				"NamespaceClass inheritance is cyclic: %s -> %s", strings.Join(visited, " -> "), parentName)
+			break
+		}
+
+		var parent v1alpha1.NamespaceClass
+		if err := r.Get(ctx, types.NamespacedName{Name: parentName}, &parent); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "Failed to get parent NamespaceClass", "parent", parentName)
+			r.Recorder.Eventf(class, corev1.EventTypeWarning, "MissingParentClass",
+				"Parent NamespaceClass '%s' cannot be read: %v", parentName, err)
+			if !apierrors.IsNotFound(err) {
+				// The parent's resources are still defined, they are only unknown
+				errs = append(errs, fmt.Errorf("parent NamespaceClass %q: %w", parentName, err))
+			}
+			break
+		}
+		expanded, err := r.withReferencedResources(ctx, &parent)
+		errs = append(errs, err)
+		chain = append(chain, expanded)
+		visited = append(visited, parentName)
+		parentName = parent.Spec.Extends
+	}
+	if len(chain) == 1 {
+		return r.expandRepeats(chain[0]), errors.Join(errs...)
+	}
+
+	effective := chain[0].DeepCopy()
+	effective.Spec.Resources = nil
+	index := map[string]int{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, res := range chain[i].Spec.Resources {
+			obj, ok := decodeResource(res)
+			if !ok {
+				effective.Spec.Resources = append(effective.Spec.Resources, res)
+				continue
+			}
+			key := objectKey(obj)
+			if at, exists := index[key]; exists {
+				if isPatch(obj) {
+					// A descendant tweaks the inherited resource instead of replacing it
+					patched, err := r.layerPatch(effective.Spec.Resources[at], obj)
+					if err != nil {
+						ctrl.LoggerFrom(ctx).Error(err, "Failed to patch inherited resource", "resource", key)
+						r.Recorder.Eventf(class, corev1.EventTypeWarning, "InvalidPatch",
+							"Patch of %s from NamespaceClass '%s' cannot be applied to the inherited resource: %v",
+							key, chain[i].Name, err)
+						continue
+					}
+					res = patched
+				}
+				effective.Spec.Resources[at] = res
+				continue
+			}
+			index[key] = len(effective.Spec.Resources)
+			effective.Spec.Resources = append(effective.Spec.Resources, res)
+		}
+	}
+	return r.expandRepeats(effective), errors.Join(errs...)
+}
+
+// resourceKey identifies an embedded resource by kind and name.
+func resourceKey(res runtime.RawExtension) (string, bool) {
+	obj, ok := decodeResource(res)
+	if !ok {
+		return "", false
+	}
+	return objectKey(obj), true
+}
+
+// decodeResource decodes an embedded resource, reporting whether it is valid.
+func decodeResource(res runtime.RawExtension) (*unstructured.Unstructured, bool) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(res.Raw); err != nil {
+		return nil, false
+	}
+	return obj, true
+}
+
+// mapParentToChildClasses enqueues every class that extends obj, directly or
+// through other classes, so that they pick up changes of their ancestors.
+func (r *NamespaceClassReconciler) mapParentToChildClasses(ctx context.Context, obj client.Object) []reconcile.Request {
+	var classes v1alpha1.NamespaceClassList
+	if err := r.List(ctx, &classes); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Failed to list NamespaceClasses for parent", "parent", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	parents := []string{obj.GetName()}
+	seen := map[string]bool{obj.GetName(): true}
+	for len(parents) > 0 {
+		parent := parents[0]
+		parents = parents[1:]
+		for _, class := range classes.Items {
+			if class.Spec.Extends != parent || seen[class.Name] {
+				continue
+			}
+			seen[class.Name] = true
+			parents = append(parents, class.Name)
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: class.Name}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Inheritance", func() {
+	It("should apply the parent's resources overlaid by the child's", func() {
+		ns := newNamespace("child-ns", "child-class")
+		base := newNamespaceClass("base-class",
+			mustRawConfigMap("base-cfg", map[string]string{"from": "base"}),
+			mustRawConfigMap("shared", map[string]string{"from": "base"}),
+		)
+		child := newNamespaceClass("child-class", mustRawConfigMap("shared", map[string]string{"from": "child"}))
+		child.Spec.Extends = base.Name
+		r, _, ctx := setupTestReconciler(ns, base, child)
+
+		_, err := r.Reconcile(ctx, requestFor(child))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(2))
+		var shared corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "shared", Namespace: ns.Name}, &shared)).To(Succeed())
+		Expect(shared.Data).To(HaveKeyWithValue("from", "child"))
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: child.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Spec.Resources).To(HaveLen(1))
+		Expect(persisted.Status.LastAppliedResources).To(HaveLen(2))
+	})
+
+	It("should apply a child's merge patch to a single field of a parent resource", func() {
+		ns := newNamespace("patch-child-ns", "patch-child")
+		base := newNamespaceClass("patch-base",
+			mustRawConfigMap("settings", map[string]string{"tier": "base", "owner": "platform"}))
+		child := newNamespaceClass("patch-child", mustRawAnnotatedConfigMap("settings",
+			map[string]string{controller.NamespaceClassPatchTypeKey: controller.PatchTypeMerge},
+			map[string]string{"tier": "gold"}))
+		child.Spec.Extends = base.Name
+		r, _, ctx := setupTestReconciler(ns, base, child)
+
+		_, err := r.Reconcile(ctx, requestFor(child))
+		Expect(err).NotTo(HaveOccurred())
+
+		var settings corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "settings", Namespace: ns.Name}, &settings)).To(Succeed())
+		Expect(settings.Data).To(Equal(map[string]string{"tier": "gold", "owner": "platform"}))
+		// The patched resource is still owned by the class, not a patch of a live object
+		Expect(settings.Labels).To(HaveKeyWithValue(controller.NamespaceClassOwnedByKey, child.Name))
+		Expect(settings.Annotations).NotTo(HaveKey(controller.NamespaceClassPatchTypeKey))
+	})
+
+	It("should merge lists by key for a child's strategic merge patch", func() {
+		ns := newNamespace("smp-child-ns", "smp-child")
+		service := func(annotations map[string]string, port int32) runtime.RawExtension {
+			raw, err := json.Marshal(corev1.Service{
+				TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: annotations},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: fmt.Sprintf("port-%d", port), Port: port}}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return runtime.RawExtension{Raw: raw}
+		}
+		base := newNamespaceClass("smp-base", service(nil, 80))
+		child := newNamespaceClass("smp-child", service(
+			map[string]string{controller.NamespaceClassPatchTypeKey: controller.PatchTypeStrategicMerge}, 443))
+		child.Spec.Extends = base.Name
+		r, _, ctx := setupTestReconciler(ns, base, child)
+
+		_, err := r.Reconcile(ctx, requestFor(child))
+		Expect(err).NotTo(HaveOccurred())
+
+		var svc corev1.Service
+		Expect(r.Get(ctx, types.NamespacedName{Name: "web", Namespace: ns.Name}, &svc)).To(Succeed())
+		Expect(svc.Spec.Ports).To(ConsistOf(
+			HaveField("Port", int32(80)),
+			HaveField("Port", int32(443)),
+		))
+	})
+
+	It("should keep the parent resource when a child's patch cannot be applied", func() {
+		ns := newNamespace("bad-patch-ns", "bad-patch-child")
+		base := newNamespaceClass("bad-patch-base", mustRawWidget("gizmo"))
+		patch, err := json.Marshal(map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata": map[string]interface{}{
+				"name": "gizmo",
+				"annotations": map[string]string{
+					controller.NamespaceClassPatchTypeKey: controller.PatchTypeStrategicMerge,
+				},
+			},
+			"spec": map[string]interface{}{"size": "large"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		child := newNamespaceClass("bad-patch-child", runtime.RawExtension{Raw: patch})
+		child.Spec.Extends = base.Name
+		r, _, ctx := setupTestReconciler(ns, base, child)
+
+		effective, err := controller.EffectiveClass(r, ctx, child)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(effective.Spec.Resources).To(Equal([]runtime.RawExtension{mustRawWidget("gizmo")}))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(And(
+			ContainSubstring("InvalidPatch"), ContainSubstring("strategic merge patches are not supported for Widget"))))
+	})
+
+	It("should keep the parent's resources when the parent cannot be read", func() {
+		ns := newNamespace("unreadable-parent-ns", "unreadable-parent-child")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		base := newNamespaceClass("unreadable-parent-base", mustRawConfigMap("base-cfg", map[string]string{"from": "base"}))
+		child := newNamespaceClass("unreadable-parent-child", mustRawConfigMap("child-cfg", map[string]string{"from": "child"}))
+		child.Spec.Extends = base.Name
+		child.Status.LastAppliedResources = []runtime.RawExtension{
+			mustRawConfigMap("base-cfg", map[string]string{"from": "base"}),
+			mustRawConfigMap("child-cfg", map[string]string{"from": "child"}),
+		}
+		baseCfg := newInjectedConfigMap("base-cfg", ns.Name, map[string]string{"from": "base"})
+		baseCfg.Labels = map[string]string{controller.NamespaceClassOwnedByKey: child.Name}
+		r, _, ctx := setupTestReconcilerWithInterceptor(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if key.Name == base.Name {
+					return errors.New("connection refused")
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}, ns, base, child, baseCfg)
+
+		_, err := r.Reconcile(ctx, requestFor(child))
+		Expect(err).To(MatchError(ContainSubstring("connection refused")))
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "base-cfg", Namespace: ns.Name}, &cm)).To(Succeed())
+		Expect(r.Get(ctx, types.NamespacedName{Name: "child-cfg", Namespace: ns.Name}, &cm)).To(Succeed())
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: child.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.LastAppliedResources).To(HaveLen(2))
+	})
+
+	It("should report a cycle instead of looping", func() {
+		ns := newNamespace("cycle-ns", "cycle-a")
+		a := newNamespaceClass("cycle-a", mustRawConfigMap("from-a", map[string]string{"foo": "bar"}))
+		a.Spec.Extends = "cycle-b"
+		b := newNamespaceClass("cycle-b", mustRawConfigMap("from-b", map[string]string{"foo": "bar"}))
+		b.Spec.Extends = "cycle-a"
+		r, _, ctx := setupTestReconciler(ns, a, b)
+
+		_, err := r.Reconcile(ctx, requestFor(a))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(2))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(And(
+			ContainSubstring("InheritanceCycle"), ContainSubstring("cycle-a -> cycle-b -> cycle-a"))))
+	})
+
+	It("should enqueue every class that extends a changed parent", func() {
+		base := newNamespaceClass("root-class")
+		middle := newNamespaceClass("middle-class")
+		middle.Spec.Extends = base.Name
+		leaf := newNamespaceClass("leaf-class")
+		leaf.Spec.Extends = middle.Name
+		unrelated := newNamespaceClass("unrelated-class")
+		r, _, ctx := setupTestReconciler(base, middle, leaf, unrelated)
+
+		Expect(controller.MapParentToChildClasses(r, ctx, base)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "middle-class"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "leaf-class"}},
+		))
+	})
+})
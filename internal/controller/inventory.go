@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxInventory is the size up to which the inventory of a namespace is recorded
+// as a list. All annotations of an object share 256KiB, so a larger inventory
+// is recorded as the hash of its list instead.
+const maxInventory = 64 * 1024
+
+// inventoryEntry is a resource listed in the NamespaceClassInventoryKey
+// annotation of a namespace.
+type inventoryEntry struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	// Namespace is only set for resources that are not in the namespace
+	// itself, i.e. redirected ones.
+	Namespace string `json:"namespace,omitempty"`
+	// Hash is the content hash of the resource as its class defines it, which
+	// the operator also records in its NamespaceClassContentHashKey annotation.
+	Hash string `json:"hash"`
+}
+
+// buildInventory returns the JSON list of the resources among objs that the
+// operator creates and keeps up to date in ns, sorted by kind and name.
+// Resources that are deleted or patched instead are not listed. A list larger
+// than maxInventory is returned as "sha256:" followed by the hex SHA-256 of it.
+func buildInventory(ns string, objs []*unstructured.Unstructured) (string, error) {
+	entries := []inventoryEntry{}
+	for _, obj := range objs {
+		if isDeletion(obj) || isPatch(obj) {
+			continue
+		}
+		entry := inventoryEntry{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Name:       obj.GetName(),
+			Hash:       contentHash(obj),
+		}
+		if obj.GetNamespace() != ns {
+			entry.Namespace = obj.GetNamespace()
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b inventoryEntry) int {
+		return cmp.Or(cmp.Compare(a.APIVersion, b.APIVersion), cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxInventory {
+		sum := sha256.Sum256(data)
+		return "sha256:" + hex.EncodeToString(sum[:]), nil
+	}
+	return string(data), nil
+}
+
+// inventory returns the inventory of objs in ns, or an empty string, which
+// keeps the recorded inventory, if it cannot be built.
+func (r *NamespaceClassReconciler) inventory(log logr.Logger, ns *corev1.Namespace, objs []*unstructured.Unstructured) string {
+	inv, err := buildInventory(ns.Name, objs)
+	if err != nil {
+		log.Error(err, "Failed to build the inventory of managed resources")
+		return ""
+	}
+	return inv
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Inventory", func() {
+	type entry struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Name       string `json:"name"`
+		Hash       string `json:"hash"`
+	}
+
+	inventoryOf := func(ns *corev1.Namespace) []entry {
+		var entries []entry
+		Expect(json.Unmarshal([]byte(ns.Annotations[controller.NamespaceClassInventoryKey]), &entries)).To(Succeed())
+		return entries
+	}
+
+	It("should list the applied resources with their content hashes on the namespace", func() {
+		ns := newNamespace("inventory-ns", "inventory-class")
+		class := newNamespaceClass("inventory-class",
+			mustRawConfigMap("settings", map[string]string{"foo": "bar"}),
+			mustRawServiceAccount("robot"),
+			mustRawDeletionMarker("legacy"),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "settings", Namespace: ns.Name}, &cm)).To(Succeed())
+		var sa corev1.ServiceAccount
+		Expect(r.Get(ctx, types.NamespacedName{Name: "robot", Namespace: ns.Name}, &sa)).To(Succeed())
+
+		var live corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &live)).To(Succeed())
+		Expect(inventoryOf(&live)).To(Equal([]entry{
+			{APIVersion: "v1", Kind: "ConfigMap", Name: "settings", Hash: cm.Annotations[controller.NamespaceClassContentHashKey]},
+			{APIVersion: "v1", Kind: "ServiceAccount", Name: "robot", Hash: sa.Annotations[controller.NamespaceClassContentHashKey]},
+		}))
+
+		By("removing a resource from the class")
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		persisted.Spec.Resources = []runtime.RawExtension{mustRawConfigMap("settings", map[string]string{"foo": "bar"})}
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &live)).To(Succeed())
+		Expect(inventoryOf(&live)).To(ConsistOf(HaveField("Name", "settings")))
+	})
+
+	It("should record the hash of an inventory too large for an annotation", func() {
+		ns := newNamespace("inventory-large-ns", "inventory-large-class")
+		var resources []runtime.RawExtension
+		for i := range 250 {
+			name := fmt.Sprintf("%03d-%s", i, strings.Repeat("x", 240))
+			resources = append(resources, mustRawConfigMap(name, map[string]string{"foo": "bar"}))
+		}
+		class := newNamespaceClass("inventory-large-class", resources...)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var live corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &live)).To(Succeed())
+		Expect(live.Annotations[controller.NamespaceClassInventoryKey]).To(MatchRegexp("^sha256:[0-9a-f]{64}$"))
+	})
+})
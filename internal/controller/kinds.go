@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// UnresolvableKindPolicy controls what happens when an embedded resource has a
+// kind that the RESTMapper cannot resolve, e.g. because its CRD is not installed.
+type UnresolvableKindPolicy string
+
+const (
+	// UnresolvableKindFailOpen skips the resource, emits an event, applies the
+	// rest and requeues until the kind can be resolved.
+	UnresolvableKindFailOpen UnresolvableKindPolicy = "fail-open"
+	// UnresolvableKindFailClosed applies nothing to the namespace and requeues
+	// until the kind can be resolved.
+	UnresolvableKindFailClosed UnresolvableKindPolicy = "fail-closed"
+)
+
+// unresolvableKindRequeueAfter is how long to wait for a missing kind to be
+// installed before retrying in fail-closed mode.
+const unresolvableKindRequeueAfter = 30 * time.Second
+
+var errUnresolvableKind = errors.New("unresolvable kind")
+
+// resolveKinds filters out the objects whose kind the RESTMapper cannot resolve
+// according to the configured UnresolvableKindPolicy. In fail-closed mode it
+// returns errUnresolvableKind as soon as one kind cannot be resolved.
+//
+// Objects of cluster-scoped kinds are only kept when they opt in with the
+// "namespaceclass.kardolus.dev/cluster-scoped" annotation, and are then created
+// once for the class instead of once per namespace.
+func (r *NamespaceClassReconciler) resolveKinds(
+	log logr.Logger,
+	ns *corev1.Namespace,
+	objs []*unstructured.Unstructured,
+) ([]*unstructured.Unstructured, error) {
+	resolved := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		mapping, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err == nil {
+			if mapping.Scope.Name() != meta.RESTScopeNameRoot {
+				resolved = append(resolved, obj)
+				continue
+			}
+			if obj.GetAnnotations()[NamespaceClassClusterScopedKey] != "true" {
+				withResource(log, gvk, obj.GetName()).Info("Skipping cluster-scoped resource without opt-in")
+				r.Recorder.Eventf(ns, corev1.EventTypeWarning, "ClusterScopedResource",
+					"Skipping cluster-scoped resource '%s' of kind %s: set annotation %s=true to create it",
+					obj.GetName(), gvk, NamespaceClassClusterScopedKey)
+				continue
+			}
+			// A single object is shared by every namespace of the class
+			obj.SetNamespace("")
+			resolved = append(resolved, obj)
+			continue
+		}
+		if !meta.IsNoMatchError(err) {
+			// Not a missing kind; let the apply surface the actual error.
+			resolved = append(resolved, obj)
+			continue
+		}
+
+		if r.UnresolvableKindPolicy == UnresolvableKindFailClosed {
+			r.Recorder.Eventf(ns, corev1.EventTypeWarning, "UnresolvableKind",
+				"Waiting for kind %s of resource '%s' to become available", gvk, obj.GetName())
+			return nil, fmt.Errorf("%w: %s", errUnresolvableKind, gvk)
+		}
+
+		withResource(log, gvk, obj.GetName()).Info("Skipping resource with unresolvable kind")
+		r.Recorder.Eventf(ns, corev1.EventTypeWarning, "UnresolvableKind",
+			"Skipping resource '%s': kind %s is not served by the cluster", obj.GetName(), gvk)
+	}
+	return resolved, nil
+}
+
+// hasUnresolvableKinds reports whether the RESTMapper cannot resolve the kind of
+// any embedded resource of class. Such a class is requeued until the kind, e.g.
+// of a CRD that is not installed yet, becomes available, also when failing
+// open, so that the skipped resources are applied then.
+func (r *NamespaceClassReconciler) hasUnresolvableKinds(class *v1alpha1.NamespaceClass) bool {
+	for _, res := range class.Spec.Resources {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(res.Raw); err != nil {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		if _, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); meta.IsNoMatchError(err) {
+			return true
+		}
+	}
+	return false
+}
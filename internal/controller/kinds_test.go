@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+var _ = Describe("Unresolvable kinds", func() {
+	var (
+		mapper *meta.DefaultRESTMapper
+		ns     *corev1.Namespace
+		class  *v1alpha1.NamespaceClass
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		mapper = newRESTMapper(scheme)
+
+		ns = newNamespace("crd-ns", "crd-class")
+		class = newNamespaceClass("crd-class",
+			mustRawConfigMap("plain-config", map[string]string{"foo": "bar"}),
+			mustRawWidget("my-widget"),
+		)
+	})
+
+	It("should skip the unresolvable resource with an event and requeue when failing open", func() {
+		r, ctx := setupReconcilerWithMapper(mapper, ns, class)
+
+		result, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("UnresolvableKind")))
+
+		By("installing the kind")
+		mapper.Add(widgetGVK, meta.RESTScopeNamespace)
+
+		result, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		widget := &unstructured.Unstructured{}
+		widget.SetGroupVersionKind(widgetGVK)
+		Expect(r.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: "my-widget"}, widget)).To(Succeed())
+	})
+
+	It("should apply nothing and requeue when failing closed", func() {
+		r, ctx := setupReconcilerWithMapper(mapper, ns, class)
+		r.UnresolvableKindPolicy = controller.UnresolvableKindFailClosed
+
+		result, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+		result, err = r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.LastAppliedResources).To(BeEmpty())
+
+		By("installing the kind")
+		mapper.Add(widgetGVK, meta.RESTScopeNamespace)
+
+		result, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.LastAppliedResources).To(HaveLen(2))
+	})
+})
+
+func mustRawWidget(name string) runtime.RawExtension {
+	raw, err := json.Marshal(map[string]interface{}{
+		"apiVersion": widgetGVK.GroupVersion().String(),
+		"kind":       widgetGVK.Kind,
+		"metadata":   map[string]interface{}{"name": name},
+		"spec":       map[string]interface{}{"size": "small"},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return runtime.RawExtension{Raw: raw}
+}
+
+func setupReconcilerWithMapper(mapper meta.RESTMapper, objs ...client.Object) (*controller.NamespaceClassReconciler, context.Context) {
+	scheme := runtime.NewScheme()
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(mapper).
+		WithObjects(objs...).
+		WithIndex(&corev1.Namespace{}, controller.NamespaceClassIndexKey, controller.NamespaceClassIndex).
+		WithStatusSubresource(&v1alpha1.NamespaceClass{}).
+		Build()
+
+	r := &controller.NamespaceClassReconciler{
+		Client:   c,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	log.SetLogger(zap.New(zap.WriteTo(GinkgoWriter)))
+	return r, log.IntoContext(context.Background(), log.Log)
+}
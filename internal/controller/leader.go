@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var leaderGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "namespaceclass_leader",
+		Help: "Whether this replica holds the leader lease and reconciles (1) or stands by (0).",
+	},
+	[]string{"pod"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(leaderGauge)
+}
+
+// LeaderGauge publishes the leadership of a replica in the namespaceclass_leader
+// metric. Added to a manager, it is started only once the replica is elected,
+// like the controller itself, and stopped when it loses the lease or shuts down.
+type LeaderGauge struct {
+	pod string
+}
+
+// NewLeaderGauge returns a LeaderGauge for the given pod, which starts out as a
+// standby.
+func NewLeaderGauge(pod string) *LeaderGauge {
+	leaderGauge.WithLabelValues(pod).Set(0)
+	return &LeaderGauge{pod: pod}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (g *LeaderGauge) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable. It reports the replica as the leader until
+// ctx is done.
+func (g *LeaderGauge) Start(ctx context.Context) error {
+	log := ctrl.Log.WithName("leader").WithValues("pod", g.pod)
+	log.Info("Acquired leadership; reconciling")
+	leaderGauge.WithLabelValues(g.pod).Set(1)
+
+	<-ctx.Done()
+	log.Info("Stopped leading")
+	leaderGauge.WithLabelValues(g.pod).Set(0)
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Leader gauge", func() {
+	It("should report the replica as leader only while it is started", func() {
+		pod := map[string]string{"pod": "operator-7d9f-x2k4p"}
+		gauge := controller.NewLeaderGauge("operator-7d9f-x2k4p")
+		Expect(gauge.NeedLeaderElection()).To(BeTrue())
+		Expect(metricValue("namespaceclass_leader", pod)).To(BeZero())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- gauge.Start(ctx) }()
+		Eventually(func() float64 { return metricValue("namespaceclass_leader", pod) }).Should(Equal(1.0))
+
+		By("losing the lease")
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(metricValue("namespaceclass_leader", pod)).To(BeZero())
+	})
+
+	It("should report standby replicas separately", func() {
+		controller.NewLeaderGauge("operator-standby")
+
+		leader := controller.NewLeaderGauge("operator-leader")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = leader.Start(ctx) }()
+
+		Eventually(func() float64 {
+			return metricValue("namespaceclass_leader", map[string]string{"pod": "operator-leader"})
+		}).Should(Equal(1.0))
+		Expect(metricValue("namespaceclass_leader", map[string]string{"pod": "operator-standby"})).To(BeZero())
+	})
+})
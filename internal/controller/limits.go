@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// tooManyResources reports whether the effective class defines more resources
+// than MaxResourcesPerClass allows.
+func (r *NamespaceClassReconciler) tooManyResources(class *v1alpha1.NamespaceClass) bool {
+	return r.MaxResourcesPerClass > 0 && len(class.Spec.Resources) > r.MaxResourcesPerClass
+}
+
+// refuseOversizedClass marks class as not ready without applying any of its
+// resources. The Event is emitted once when the class grows too large, and the
+// class is reconciled again when it is edited, so it is not requeued.
+func (r *NamespaceClassReconciler) refuseOversizedClass(ctx context.Context, log logr.Logger, class *v1alpha1.NamespaceClass) error {
+	msg := fmt.Sprintf("NamespaceClass defines %d resources, more than the limit of %d",
+		len(class.Spec.Resources), r.MaxResourcesPerClass)
+	log.Info("Refusing NamespaceClass", "reason", msg)
+
+	if ready := meta.FindStatusCondition(class.Status.Conditions, v1alpha1.ConditionReady); ready == nil ||
+		ready.Reason != v1alpha1.ReasonTooManyResources {
+		r.Recorder.Event(class, corev1.EventTypeWarning, "TooManyResources", msg)
+	}
+
+	before := class.Status.DeepCopy()
+	class.Status.Phase = v1alpha1.ClassPhaseError
+	meta.SetStatusCondition(&class.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             v1alpha1.ReasonTooManyResources,
+		Message:            msg,
+		ObservedGeneration: class.Generation,
+	})
+	if equality.Semantic.DeepEqual(before, &class.Status) {
+		return nil
+	}
+	class.Status.LastReconcileTime = metav1.Now()
+	return r.Status().Update(ctx, class)
+}
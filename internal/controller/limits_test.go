@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+var _ = Describe("Resource limit per class", func() {
+	twoConfigMaps := func(name string) *v1alpha1.NamespaceClass {
+		return newNamespaceClass(name,
+			mustRawConfigMap("cfg-a", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("cfg-b", map[string]string{"foo": "bar"}),
+		)
+	}
+
+	It("should apply a class with exactly the maximum number of resources", func() {
+		class := twoConfigMaps("limit-class")
+		ns := newNamespace("limit-ns", class.Name)
+		r, _, ctx := setupTestReconciler(class, ns)
+		r.MaxResourcesPerClass = 2
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(2))
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(persisted.Status.Conditions, v1alpha1.ConditionReady)).To(BeTrue())
+	})
+
+	It("should refuse a class with one resource too many", func() {
+		class := twoConfigMaps("oversized-class")
+		ns := newNamespace("oversized-ns", class.Name)
+		r, _, ctx := setupTestReconciler(class, ns)
+		r.MaxResourcesPerClass = 1
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		ready := meta.FindStatusCondition(persisted.Status.Conditions, v1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal(v1alpha1.ReasonTooManyResources))
+		Expect(ready.Message).To(ContainSubstring("2 resources, more than the limit of 1"))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("TooManyResources")))
+
+		By("reconciling the refused class again")
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Recorder.(*record.FakeRecorder).Events).NotTo(Receive())
+	})
+
+	It("should count inherited resources against the limit", func() {
+		parent := newNamespaceClass("limit-parent", mustRawConfigMap("inherited", map[string]string{"foo": "bar"}))
+		child := newNamespaceClass("limit-child", mustRawConfigMap("own", map[string]string{"foo": "bar"}))
+		child.Spec.Extends = parent.Name
+		ns := newNamespace("limit-child-ns", child.Name)
+		r, _, ctx := setupTestReconciler(parent, child, ns)
+		r.MaxResourcesPerClass = 1
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("TooManyResources")))
+	})
+})
@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Keys of the values that identify what a log line is about. Log lines about a
+// class, a namespace or an injected resource carry them under these names only,
+// so that logs can be queried by them. The logger of a reconcile already
+// carries the namespace and name of its request under "namespace" and "name",
+// so the namespace and name of what is worked on use keys of their own.
+const (
+	logKeyClass      = "class"
+	logKeyNamespace  = "resourceNamespace"
+	logKeyKind       = "kind"
+	logKeyAPIVersion = "apiVersion"
+	logKeyName       = "resourceName"
+)
+
+// classLogger returns the logger of ctx for work on the named class in the
+// named namespace. Either may be empty when it is not known, or does not
+// apply, and is then left out.
+func classLogger(ctx context.Context, className, namespace string) logr.Logger {
+	log := ctrl.LoggerFrom(ctx)
+	if className != "" {
+		log = log.WithValues(logKeyClass, className)
+	}
+	if namespace != "" {
+		log = log.WithValues(logKeyNamespace, namespace)
+	}
+	return log
+}
+
+// withResource adds the kind, API version and name of a resource to log.
+func withResource(log logr.Logger, gvk schema.GroupVersionKind, name string) logr.Logger {
+	return log.WithValues(logKeyKind, gvk.Kind, logKeyAPIVersion, gvk.GroupVersion().String(), logKeyName, name)
+}
+
+// withObject adds the kind, API version and name of obj to log.
+func withObject(log logr.Logger, obj *unstructured.Unstructured) logr.Logger {
+	return withResource(log, obj.GroupVersionKind(), obj.GetName())
+}
+
+// resourceLogger returns the logger of ctx for work on obj, identified by the
+// class that owns it, its namespace, if any, and its kind and name.
+func resourceLogger(ctx context.Context, obj *unstructured.Unstructured) logr.Logger {
+	return withObject(classLogger(ctx, obj.GetLabels()[NamespaceClassOwnedByKey], obj.GetNamespace()), obj)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Structured logging", func() {
+	// requestContext returns ctx with log as controller-runtime hands it to a
+	// reconcile of req, which carries the namespace and name of req.
+	requestContext := func(ctx context.Context, log logr.Logger, req reconcile.Request) context.Context {
+		return logr.NewContext(ctx, log.WithValues(
+			"controller", "namespaceclass", "controllerGroup", "namespace.kardolus.dev", "controllerKind", "NamespaceClass",
+			"namespace", req.Namespace, "name", req.Name, "reconcileID", "test",
+		))
+	}
+
+	// keys returns the keys of the JSON object line in order, repeated ones
+	// included.
+	keys := func(line []byte) []string {
+		dec := json.NewDecoder(bytes.NewReader(line))
+		_, err := dec.Token()
+		Expect(err).NotTo(HaveOccurred())
+		var keys []string
+		for dec.More() {
+			key, err := dec.Token()
+			Expect(err).NotTo(HaveOccurred())
+			keys = append(keys, key.(string))
+			var value json.RawMessage
+			Expect(dec.Decode(&value)).To(Succeed())
+		}
+		return keys
+	}
+
+	It("should identify the class, namespace and resource of every apply with the same keys", func() {
+		ns := newNamespace("logging-ns", "logging-class")
+		class := newNamespaceClass("logging-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		var out bytes.Buffer
+		log := zap.New(zap.WriteTo(&out))
+
+		By("creating the resource on the namespace path")
+		_, err := r.Reconcile(requestContext(ctx, log, requestFor(ns)), requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("updating the resource on the class path")
+		class.Spec.Resources = []runtime.RawExtension{mustRawConfigMap("cfg", map[string]string{"foo": "baz"})}
+		Expect(r.Update(ctx, class)).To(Succeed())
+		_, err = r.Reconcile(requestContext(ctx, log, requestFor(class)), requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var lines []map[string]any
+		for _, raw := range bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n")) {
+			lineKeys := keys(raw)
+			Expect(lineKeys).To(HaveLen(len(sets.New(lineKeys...))), "repeated keys in %s", raw)
+			var line map[string]any
+			Expect(json.Unmarshal(raw, &line)).To(Succeed())
+			lines = append(lines, line)
+		}
+
+		resourceLine := SatisfyAll(
+			HaveKeyWithValue("class", "logging-class"),
+			HaveKeyWithValue("resourceNamespace", "logging-ns"),
+			HaveKeyWithValue("kind", "ConfigMap"),
+			HaveKeyWithValue("apiVersion", "v1"),
+			HaveKeyWithValue("resourceName", "cfg"),
+		)
+		Expect(lines).To(ContainElement(SatisfyAll(HaveKeyWithValue("msg", "Created resource"), resourceLine)))
+		Expect(lines).To(ContainElement(SatisfyAll(HaveKeyWithValue("msg", "Updated existing resource"), resourceLine)))
+		Expect(lines).To(ContainElement(SatisfyAll(
+			HaveKeyWithValue("msg", "Applying NamespaceClass"),
+			HaveKeyWithValue("class", "logging-class"),
+			HaveKeyWithValue("resourceNamespace", "logging-ns"),
+		)))
+		for _, line := range lines {
+			Expect(line).NotTo(HaveKey("gvk"))
+			Expect(line).NotTo(HaveKey("className"))
+		}
+	})
+})
@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// setLastApplied records the object as the operator is about to write it in
+// the NamespaceClassLastAppliedKey annotation, like kubectl's
+// last-applied-configuration. The data of Secret copies is left out, so that
+// it is not readable in plain text from an annotation.
+func setLastApplied(obj *unstructured.Unstructured) error {
+	annotations := obj.GetAnnotations()
+	delete(annotations, NamespaceClassLastAppliedKey)
+	obj.SetAnnotations(annotations)
+
+	recorded := obj.Object
+	if isSecretCopy(obj) {
+		recorded = obj.DeepCopy().Object
+		delete(recorded, "data")
+	}
+	data, err := json.Marshal(recorded)
+	if err != nil {
+		return err
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[NamespaceClassLastAppliedKey] = string(data)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// lastApplied returns the configuration recorded on a live object, or nil if
+// there is none.
+func lastApplied(live *unstructured.Unstructured) map[string]interface{} {
+	data, ok := live.GetAnnotations()[NamespaceClassLastAppliedKey]
+	if !ok {
+		return nil
+	}
+	var original map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &original); err != nil {
+		return nil
+	}
+	return original
+}
+
+// mergeWithLive computes the object to write over live. Fields the class
+// defines are authoritative, fields the operator applied before but the class
+// dropped are removed, and fields only others added are kept. The data of a
+// Secret copy, which is not recorded, is replaced as a whole.
+func mergeWithLive(desired, live *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	original := lastApplied(live)
+	if err := setLastApplied(desired); err != nil {
+		return nil, err
+	}
+
+	merged := live.DeepCopy()
+	merged.Object = threeWayMerge(original, desired.Object, merged.Object)
+	if isSecretCopy(desired) {
+		if data, ok := desired.Object["data"]; ok {
+			merged.Object["data"] = runtime.DeepCopyJSONValue(data)
+		} else {
+			delete(merged.Object, "data")
+		}
+	}
+	return merged, nil
+}
+
+// threeWayMerge merges desired into live, deleting the keys of original that
+// desired no longer has. Nested maps are merged recursively; any other value,
+// including lists, is replaced as a whole.
+func threeWayMerge(original, desired, live map[string]interface{}) map[string]interface{} {
+	if live == nil {
+		live = map[string]interface{}{}
+	}
+
+	for key := range original {
+		if _, ok := desired[key]; !ok {
+			delete(live, key)
+		}
+	}
+
+	for key, value := range desired {
+		desiredMap, desiredIsMap := value.(map[string]interface{})
+		liveMap, liveIsMap := live[key].(map[string]interface{})
+		if desiredIsMap && liveIsMap {
+			originalMap, _ := original[key].(map[string]interface{})
+			live[key] = threeWayMerge(originalMap, desiredMap, liveMap)
+			continue
+		}
+		live[key] = value
+	}
+
+	return live
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Three-way merge", func() {
+	updateClass := func(ctx context.Context, r *controller.NamespaceClassReconciler, name string, resources ...runtime.RawExtension) {
+		var class v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: name}, &class)).To(Succeed())
+		class.Spec.Resources = resources
+		Expect(r.Update(ctx, &class)).To(Succeed())
+		_, err := r.Reconcile(ctx, requestFor(&class))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("should keep keys added by users when the class changes", func() {
+		ns := newNamespace("merge-ns", "merge-class")
+		class := newNamespaceClass("merge-class", mustRawConfigMap("app-config", map[string]string{"managed": "v1"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("letting the app append a runtime key")
+		cm := listConfigMaps(r.Client, ctx, ns.Name)[0]
+		Expect(cm.Annotations).To(HaveKey(controller.NamespaceClassLastAppliedKey))
+		cm.Data["runtime"] = "added-by-app"
+		Expect(r.Update(ctx, &cm)).To(Succeed())
+
+		updateClass(ctx, r, class.Name, mustRawConfigMap("app-config", map[string]string{"managed": "v2"}))
+
+		data := listConfigMaps(r.Client, ctx, ns.Name)[0].Data
+		Expect(data).To(HaveKeyWithValue("managed", "v2"))
+		Expect(data).To(HaveKeyWithValue("runtime", "added-by-app"))
+	})
+
+	It("should remove keys the class no longer defines", func() {
+		ns := newNamespace("merge-remove-ns", "merge-remove-class")
+		class := newNamespaceClass("merge-remove-class",
+			mustRawConfigMap("app-config", map[string]string{"managed": "v1", "retired": "v1"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		cm := listConfigMaps(r.Client, ctx, ns.Name)[0]
+		cm.Data["runtime"] = "added-by-app"
+		Expect(r.Update(ctx, &cm)).To(Succeed())
+
+		updateClass(ctx, r, class.Name, mustRawConfigMap("app-config", map[string]string{"managed": "v1"}))
+
+		data := listConfigMaps(r.Client, ctx, ns.Name)[0].Data
+		Expect(data).To(HaveKeyWithValue("managed", "v1"))
+		Expect(data).To(HaveKeyWithValue("runtime", "added-by-app"))
+		Expect(data).NotTo(HaveKey("retired"))
+	})
+
+	It("should overwrite operator-managed keys changed by users", func() {
+		ns := newNamespace("merge-authoritative-ns", "merge-authoritative-class")
+		existing := newInjectedConfigMap("app-config", ns.Name, map[string]string{"managed": "edited", "runtime": "kept"})
+		class := newNamespaceClass("merge-authoritative-class", mustRawConfigMap("app-config", map[string]string{"managed": "v1"}))
+		r, _, ctx := setupTestReconciler(ns, class, existing)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		data := listConfigMaps(r.Client, ctx, ns.Name)[0].Data
+		Expect(data).To(HaveKeyWithValue("managed", "v1"))
+		Expect(data).To(HaveKeyWithValue("runtime", "kept"))
+	})
+})
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	resourcesAppliedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "namespaceclass_resources_applied_total",
+			Help: "Number of resources created or updated in namespaces by a NamespaceClass.",
+		},
+		[]string{"class", "namespace", "kind"},
+	)
+
+	reconcileErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "namespaceclass_reconcile_errors_total",
+			Help: "Number of reconciles of a NamespaceClass that returned an error.",
+		},
+		[]string{"class"},
+	)
+
+	namespacesManaged = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "namespaceclass_namespaces_managed",
+			Help: "Number of namespaces that currently belong to a NamespaceClass.",
+		},
+		[]string{"class"},
+	)
+
+	reconcileDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "namespaceclass_reconcile_duration_seconds",
+			Help:    "Time spent in each phase of a reconcile, in seconds.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"phase"},
+	)
+)
+
+// Phases of a reconcile, as reported by namespaceclass_reconcile_duration_seconds.
+const (
+	phaseNamespaceCreate = "namespace_create"
+	phaseClassUpdate     = "class_update"
+	phaseClassDelete     = "class_delete"
+	phaseUpsert          = "upsert"
+)
+
+func init() {
+	metrics.Registry.MustRegister(resourcesAppliedTotal, reconcileErrorsTotal, namespacesManaged, reconcileDurationSeconds)
+}
+
+// observeDuration records the time since start against phase. It is meant to
+// be deferred at the top of the function that implements the phase.
+func observeDuration(phase string, start time.Time) {
+	reconcileDurationSeconds.WithLabelValues(phase).Observe(time.Since(start).Seconds())
+}
+
+// countApplied records an applied resource against the class it is labeled
+// with. Dry-run applies are not counted.
+func (r *NamespaceClassReconciler) countApplied(obj *unstructured.Unstructured) {
+	if r.DryRun {
+		return
+	}
+	resourcesAppliedTotal.WithLabelValues(obj.GetLabels()[NamespaceClassOwnedByKey], obj.GetNamespace(), obj.GetKind()).Inc()
+}
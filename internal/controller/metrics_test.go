@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("Metrics", func() {
+	It("should count applied resources and managed namespaces", func() {
+		ns := newNamespace("metrics-ns", "metrics-class")
+		other := newNamespace("metrics-other-ns", "metrics-class")
+		class := newNamespaceClass("metrics-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, other, class)
+
+		applied := map[string]string{"class": "metrics-class", "namespace": "metrics-ns", "kind": "ConfigMap"}
+		before := metricValue("namespaceclass_resources_applied_total", applied)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(metricValue("namespaceclass_resources_applied_total", applied)).To(Equal(before + 1))
+		Expect(metricValue("namespaceclass_namespaces_managed", map[string]string{"class": "metrics-class"})).To(Equal(2.0))
+	})
+
+	It("should count reconcile errors per class", func() {
+		class := newNamespaceClass("metrics-error-class")
+		r, _, ctx := setupTestReconcilerWithInterceptor(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				return errors.New("boom")
+			},
+		}, class)
+
+		labels := map[string]string{"class": "metrics-error-class"}
+		before := metricValue("namespaceclass_reconcile_errors_total", labels)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).To(HaveOccurred())
+
+		Expect(metricValue("namespaceclass_reconcile_errors_total", labels)).To(Equal(before + 1))
+	})
+
+	It("should observe the duration of reconcile phases", func() {
+		ns := newNamespace("metrics-duration-ns", "metrics-duration-class")
+		class := newNamespaceClass("metrics-duration-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		classUpdate := map[string]string{"phase": "class_update"}
+		namespaceCreate := map[string]string{"phase": "namespace_create"}
+		beforeClassUpdate := metricValue("namespaceclass_reconcile_duration_seconds", classUpdate)
+		beforeNamespaceCreate := metricValue("namespaceclass_reconcile_duration_seconds", namespaceCreate)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(metricValue("namespaceclass_reconcile_duration_seconds", classUpdate)).To(Equal(beforeClassUpdate + 1))
+		Expect(metricValue("namespaceclass_reconcile_duration_seconds", namespaceCreate)).To(Equal(beforeNamespaceCreate + 1))
+	})
+})
+
+// metricValue scrapes the controller-runtime registry and returns the value of
+// the series with the given labels, or 0 if it does not exist yet. The value of
+// a histogram is its number of observations.
+func metricValue(name string, labels map[string]string) float64 {
+	families, err := metrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			matched := 0
+			for _, pair := range m.GetLabel() {
+				if labels[pair.GetName()] == pair.GetValue() {
+					matched++
+				}
+			}
+			if matched != len(labels) {
+				continue
+			}
+			if m.GetHistogram() != nil {
+				return float64(m.GetHistogram().GetSampleCount())
+			}
+			if m.GetCounter() != nil {
+				return m.GetCounter().GetValue()
+			}
+			return m.GetGauge().GetValue()
+		}
+	}
+	return 0
+}
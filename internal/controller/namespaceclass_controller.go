@@ -18,51 +18,306 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/go-logr/logr"
 	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/managedfields"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
+	// NamespaceClassNameKey is the default class label of Namespaces, see
+	// NamespaceClassReconciler.ClassLabelKey.
 	NamespaceClassNameKey            = "namespaceclass.akuity.io/name"
 	NamespaceClassCleanupKey         = "namespaceclass.akuity.io/cleanup"
 	NamespaceClassCleanupObsoleteKey = "namespaceclass.akuity.io/cleanup-obsolete"
 	NamespaceClassFinalizerKey       = "namespaceclass.kardolus.dev/finalizer"
+	NamespaceClassOwnedByKey         = "namespaceclass.kardolus.dev/owned-by"
+	NamespaceClassAppliedClassKey    = "namespaceclass.kardolus.dev/applied-class"
+	NamespaceClassAppliedHashKey     = "namespaceclass.kardolus.dev/applied-hash"
+	NamespaceClassActionKey          = "namespaceclass.kardolus.dev/action"
+	NamespaceClassApplyPolicyKey     = "namespaceclass.kardolus.dev/apply-policy"
+	NamespaceClassClusterScopedKey   = "namespaceclass.kardolus.dev/cluster-scoped"
+	NamespaceClassLastAppliedKey     = "namespaceclass.kardolus.dev/last-applied-configuration"
+	NamespaceClassApplyOrderKey      = "namespaceclass.kardolus.dev/apply-order"
+	NamespaceClassPatchTypeKey       = "namespaceclass.kardolus.dev/patch-type"
+	NamespaceClassTargetNamespaceKey = "namespaceclass.kardolus.dev/target-namespace"
+	// NamespaceClassImmutableKey set to "true" marks an embedded resource that
+	// is never updated once created, like a create-only one, and that is not
+	// deleted as obsolete when it is removed from the class either.
+	NamespaceClassImmutableKey = "namespaceclass.kardolus.dev/immutable"
+	// NamespaceClassProtectKey set to "true" on an injected resource, in the
+	// class or on the object in the namespace, keeps it from being deleted as
+	// obsolete, e.g. a PersistentVolumeClaim that holds data.
+	NamespaceClassProtectKey = "namespaceclass.kardolus.dev/protect"
+	// NamespaceClassWhenKey on an embedded resource holds a label selector,
+	// e.g. "tier=frontend", that the labels of a namespace must match for the
+	// resource to be applied to it.
+	NamespaceClassWhenKey = "namespaceclass.kardolus.dev/when"
+	// NamespaceClassContentHashKey is set by the operator to a hash of an
+	// injected resource as its class defines it, so that applying an
+	// unchanged resource again does not write it.
+	NamespaceClassContentHashKey = "namespaceclass.kardolus.dev/content-hash"
+	// NamespaceClassRepeatKey lists comma-separated values on an embedded
+	// resource, which is expanded into one copy per value with {{ .Value }}
+	// replaced by it.
+	NamespaceClassRepeatKey = "namespaceclass.kardolus.dev/repeat"
+	// NamespaceClassPausedKey set to "true" on a NamespaceClass stops the
+	// operator from creating, updating or deleting any of its resources until
+	// the annotation is removed.
+	NamespaceClassPausedKey = "namespaceclass.kardolus.dev/paused"
+	// NamespaceClassForceReconcileKey set on a NamespaceClass to a new value,
+	// e.g. a timestamp, re-applies every resource to every namespace of the
+	// class once, even those that look in sync.
+	NamespaceClassForceReconcileKey = "namespaceclass.kardolus.dev/force-reconcile"
+	// NamespaceClassSourceSecretKey is set by the operator on the copies of
+	// spec.secretRefs to the namespace/name of the Secret they were copied from.
+	NamespaceClassSourceSecretKey = "namespaceclass.kardolus.dev/source-secret"
+	// NamespaceClassAppliedCleanupObsoleteKey is set by the operator to "true"
+	// once obsolete resources were cleaned up in a namespace with
+	// NamespaceClassCleanupObsoleteKey, so that enabling the cleanup later also
+	// finds the resources that were removed from the class in the meantime.
+	NamespaceClassAppliedCleanupObsoleteKey = "namespaceclass.kardolus.dev/applied-cleanup-obsolete"
+	// NamespaceClassInventoryKey is set by the operator to a JSON list of the
+	// resources it manages in a namespace with their content hashes, as of the
+	// last apply that fully succeeded, so that external tools can compare it
+	// with their desired state. A list too large for an annotation is replaced
+	// with its hash.
+	NamespaceClassInventoryKey = "namespaceclass.kardolus.dev/inventory"
+	// NamespaceClassAllowOrphanKey set to "true" on a NamespaceClass lets it be
+	// deleted while namespaces without cleanup still use it, when the class
+	// webhook is configured to prevent that.
+	NamespaceClassAllowOrphanKey = "namespaceclass.kardolus.dev/allow-orphan"
+	// NamespaceClassGenerationKey is set by the operator on every injected
+	// resource to the metadata.generation of the class it was last applied
+	// from, so that resources lagging behind their class can be found.
+	NamespaceClassGenerationKey = "namespaceclass.kardolus.dev/class-generation"
+	// NamespaceClassUIDKey is a label set by the operator on every injected
+	// resource to the UID of the class it was last applied from. A resource
+	// whose class was deleted and recreated under the same name keeps the UID of
+	// the old class until the new one applies it.
+	NamespaceClassUIDKey = "namespaceclass.kardolus.dev/class-uid"
+	// NamespaceClassGenerateNameKey is set by the operator on every resource it
+	// created from a metadata.generateName prefix to that prefix, so that the
+	// generated name is reused by later reconciles.
+	NamespaceClassGenerateNameKey = "namespaceclass.kardolus.dev/generate-name"
+	// NamespaceClassPropagationPolicyKey on a NamespaceClass selects the
+	// propagation policy its resources are deleted with: Foreground, Background
+	// or Orphan. The API server default of each kind applies when it is unset.
+	NamespaceClassPropagationPolicyKey = "namespaceclass.kardolus.dev/propagation-policy"
+
+	// ActionDelete marks an embedded resource that should be deleted from
+	// target namespaces instead of being created.
+	ActionDelete = "delete"
+
+	// ApplyPolicyCreateOnly marks an embedded resource that is created once and
+	// never updated afterwards, so that applications may change it freely.
+	ApplyPolicyCreateOnly = "create-only"
 )
 
+// NamespaceClassIndexKey indexes Namespaces by the value of their class label.
+const NamespaceClassIndexKey = ".metadata.labels.namespaceclass"
+
 // NamespaceClassReconciler reconciles a NamespaceClass object
 type NamespaceClassReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// DefaultClass is treated as the class of every Namespace without a class
+	// label. Defaulting is disabled when empty.
+	DefaultClass string
+	// ExcludedNamespaces never receive the DefaultClass, nor a class whose
+	// namespace selector matches them.
+	ExcludedNamespaces []string
+	// UnresolvableKindPolicy decides whether resources of unknown kinds are
+	// skipped (the default) or block the namespace until they can be resolved.
+	UnresolvableKindPolicy UnresolvableKindPolicy
+	// WatchedKinds are the injected kinds whose deletion triggers a reconcile of
+	// the owning class, so that they are re-injected. Defaults to DefaultWatchedKinds.
+	WatchedKinds []schema.GroupVersionKind
+	// ApplyStrategy decides how existing resources are updated. Defaults to
+	// ApplyStrategyUpdate.
+	ApplyStrategy ApplyStrategy
+	// ResyncPeriod is how often a class is reconciled without any change, so
+	// that out-of-band edits of injected resources are corrected. Zero disables
+	// periodic resyncs.
+	ResyncPeriod time.Duration
+	// DryRun sends every write of an injected resource with DryRunAll and
+	// reports the planned changes instead of persisting them.
+	DryRun bool
+	// NamespaceFinalizer adds a finalizer to every Namespace a class was applied
+	// to, which is removed once the namespace leaves its class.
+	NamespaceFinalizer bool
+	// AllowedKinds restricts the kinds a class may inject, so that a class
+	// cannot be used to create e.g. RBAC objects. Every kind is allowed when nil.
+	AllowedKinds []AllowedKind
+	// AdoptExisting takes over resources that already exist when a namespace
+	// is first provisioned, unless they are owned by another class, instead of
+	// leaving them alone.
+	AdoptExisting bool
+	// OperatorNamespace is the namespace the operator runs in. The ConfigMaps
+	// of spec.resourceRefs are read from it; references cannot be resolved
+	// when it is empty.
+	OperatorNamespace string
+	// ClassLabelKey is the Namespace label that names the class of a
+	// namespace. Defaults to NamespaceClassNameKey.
+	ClassLabelKey string
+	// TypeConverter holds the OpenAPI schemas served by the API server. When
+	// set, embedded resources are validated against them and problems are
+	// reported in the ResourcesValid condition of the class.
+	TypeConverter managedfields.TypeConverter
+	// CheckReferences checks that the ServiceAccounts the RoleBindings of a
+	// class bind are defined by the class too, and reports dangling ones in the
+	// ReferencesValid condition of the class.
+	CheckReferences bool
+	// TargetNamespaces are the namespaces that embedded resources may be
+	// redirected to with NamespaceClassTargetNamespaceKey, e.g. a shared
+	// monitoring namespace. No redirection is allowed when empty.
+	TargetNamespaces []string
+	// Health records the outcome of every reconcile for the readiness probe.
+	// Nothing is recorded when nil.
+	Health *ReconcileHealth
+	// MaxConcurrentReconciles is how many requests, of classes or of single
+	// namespaces of a class, are reconciled in parallel, so that a class bound
+	// to many namespaces does not hold up the others. Defaults to 1.
+	MaxConcurrentReconciles int
+	// ListPageSize makes classes list their namespaces from the API server in
+	// pages of this size, so that a class bound to a huge number of namespaces
+	// does not hold a copy of all of them at once. Pages are read through
+	// APIReader, since the cache cannot serve them. Zero lists namespaces from
+	// the cache at once.
+	ListPageSize int64
+	// APIReader reads from the API server instead of the cache.
+	APIReader client.Reader
+	// NamespaceAllowlist restricts the operator to the namespaces that match
+	// one of these glob patterns, e.g. "team-*". Every namespace is allowed
+	// when empty.
+	NamespaceAllowlist []string
+	// NamespaceDenylist are glob patterns of namespaces the operator never
+	// touches, even if they are allowed, e.g. DefaultNamespaceDenylist.
+	NamespaceDenylist []string
+	// MaxResourcesPerClass is the most resources, including inherited,
+	// referenced and repeated ones, a class may define. Larger classes are
+	// refused as a whole rather than applied in part. Zero means no limit.
+	MaxResourcesPerClass int
+	// MaxRetries is how many times in a row a resource may fail to apply to a
+	// namespace before it is marked failed in the status and skipped until the
+	// class changes. Zero retries forever.
+	MaxRetries int
+	// ObsoleteCleanup decides how resources that a class no longer defines are
+	// found. Defaults to ObsoleteCleanupStatus.
+	ObsoleteCleanup ObsoleteCleanup
+	// EventCooldown is how long a warning about a persisting condition, such
+	// as a namespace referencing a missing class, is not repeated for the
+	// same namespace. Zero emits it on every reconcile.
+	EventCooldown time.Duration
+	// ApplyTimeout bounds every write of an injected resource, so that a slow
+	// API server fails the write, which is retried, rather than holding up the
+	// worker. Zero waits as long as the reconcile does.
+	ApplyTimeout time.Duration
+	// DrainTimeout is how long an apply to a namespace that is in flight when
+	// the operator shuts down may take to finish. No further namespaces are
+	// started meanwhile. Zero stops applies right away.
+	DrainTimeout time.Duration
+	// ApplyQPS limits how many namespaces per second a class is applied to,
+	// so that a class bound to thousands of namespaces does not flood the API
+	// server. Namespaces that are in sync are not counted. Zero does not limit
+	// them.
+	ApplyQPS float64
+	// ApplyBurst is how many namespaces a class may be applied to at once
+	// before ApplyQPS paces them. It is at least 1.
+	ApplyBurst int
+	// MaxFinalizeAttempts is how often the cleanup of a deleted class may fail
+	// before a CleanupFailed Event is emitted. Zero keeps retrying silently.
+	MaxFinalizeAttempts int
+	// ForceFinalize removes the finalizer of a deleted class once
+	// MaxFinalizeAttempts is reached, so that the class does not stay in
+	// Terminating, at the cost of the resources that could not be cleaned up.
+	ForceFinalize bool
+
+	// limiters hold the token bucket of each class, see applyLimiter.
+	limiters   map[string]*rate.Limiter
+	limitersMu sync.Mutex
+
+	// finalizeAttempts count the failed cleanups of deleted classes by UID,
+	// see giveUpFinalizing.
+	finalizeAttempts map[types.UID]int
+	finalizeMu       sync.Mutex
+
+	// events remembers recently emitted warnings, see warnOnce.
+	events     *eventCache
+	eventsOnce sync.Once
+
+	// requests feeds requests of the reconciler itself into the work queue,
+	// such as those of the namespaces of a class.
+	requests chan event.TypedGenericEvent[reconcile.Request]
+
+	// rollouts hold the classes that are being applied to their namespaces,
+	// see rollout.
+	rollouts   map[string]*rollout
+	rolloutsMu sync.Mutex
 }
 
 // +kubebuilder:rbac:groups=namespace.kardolus.dev,resources=namespaceclasses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=namespace.kardolus.dev,resources=namespaceclasses/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=namespace.kardolus.dev,resources=namespaceclasses/finalizers,verbs=update
-// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups="",resources=configmaps;secrets;services;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
-// Reconcile handles both Namespace and NamespaceClass events.
+// Reconcile handles both Namespace and NamespaceClass events. Requests for
+// Namespaces are told apart from those for classes by their name, see
+// namespaceKey, so that a Namespace and a class of the same name never get
+// their requests mixed up.
 //
 // For Namespace events:
 //   - If the "namespaceclass.akuity.io/name" label is present on the Namespace,
 //     the controller looks up the referenced NamespaceClass and injects its
-//     defined resources into the Namespace.
+//     defined resources into the Namespace. Unlabeled Namespaces use the first
+//     class whose namespace selector matches them or else the DefaultClass, if
+//     configured, unless they are excluded.
 //   - Resources are created if missing, or updated in-place if they already exist.
+//     Resources annotated with "namespaceclass.kardolus.dev/action: delete" are
+//     deleted from the Namespace instead, and resources annotated with
+//     "namespaceclass.kardolus.dev/apply-policy: create-only" are never updated.
+//   - Embedded resources are rendered with text/template first and may refer to
+//     {{ .Namespace }}, {{ .Namespace.Labels.<key> }} and {{ .ClassName }}.
+//   - If the Namespace switched from another class (as recorded in the
+//     "namespaceclass.kardolus.dev/applied-class" annotation) and has the annotation
+//     "namespaceclass.akuity.io/cleanup: true", resources of the previous class that
+//     the new class does not define are deleted.
+//   - If the class label was removed, the Namespace is released: resources of the
+//     previous class are deleted when cleanup is enabled, and the applied-class
+//     annotation and finalizer are removed.
 //
 // For NamespaceClass updates:
 //   - The controller reconciles all Namespaces that reference the class.
@@ -70,6 +325,11 @@ type NamespaceClassReconciler struct {
 //   - If the Namespace has the annotation "namespaceclass.akuity.io/cleanup-obsolete: true",
 //     resources that were previously injected but are no longer defined in the NamespaceClass
 //     will be deleted.
+//   - Each Namespace is applied on its own, with a request naming the class and,
+//     in its namespace field, the Namespace: a Namespace that fails, or panics,
+//     does not keep the class from being applied to the others, and is retried
+//     on its own. Once every Namespace was applied, the class is reconciled
+//     again and reports the outcome in its status.
 //
 // For NamespaceClass deletion events:
 //   - The controller identifies all Namespaces that reference the deleted class.
@@ -77,44 +337,343 @@ type NamespaceClassReconciler struct {
 //     "namespaceclass.akuity.io/cleanup: true", injected resources are cleaned up.
 //   - Otherwise, a warning Event is emitted to indicate that the Namespace is now orphaned.
 func (r *NamespaceClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	// Try to fetch as a Namespace
-	ns := &corev1.Namespace{}
-	if err := r.Get(ctx, req.NamespacedName, ns); err == nil {
-		return r.reconcileNamespaceCreate(ctx, ns)
+	if ctx.Err() != nil {
+		// Shutting down; requests left in the queue are picked up again on start
+		ctrl.LoggerFrom(ctx).Info("Shutting down; skipping reconcile")
+		return ctrl.Result{}, nil
+	}
+	if req.Namespace != "" {
+		result, err := r.reconcileNamespaceRequest(ctx, req)
+		if err != nil {
+			reconcileErrorsTotal.WithLabelValues(req.Name).Inc()
+		}
+		r.recordHealth(req.Name, req.Namespace, err)
+		return result, err
 	}
 
+	if name, ok := namespaceFromKey(req); ok {
+		ns := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		drainCtx, cancel := r.drainContext(ctx)
+		defer cancel()
+		result, err := r.reconcileNamespaceCreate(drainCtx, ns)
+		className, _ := r.classNameFor(ctx, ns)
+		if err != nil {
+			reconcileErrorsTotal.WithLabelValues(className).Inc()
+		}
+		r.recordHealth(className, name, err)
+		return result, err
+	}
+
+	result, err := r.reconcileClass(ctx, req)
+	if err != nil {
+		reconcileErrorsTotal.WithLabelValues(req.Name).Inc()
+	}
+	r.recordHealth(req.Name, "", err)
+	return result, err
+}
+
+func (r *NamespaceClassReconciler) recordHealth(className, namespace string, err error) {
+	if r.Health != nil {
+		r.Health.Record(className, namespace, err)
+	}
+}
+
+func (r *NamespaceClassReconciler) reconcileClass(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	// Try to fetch as a NamespaceClass
 	class := &v1alpha1.NamespaceClass{}
 	if err := r.Get(ctx, req.NamespacedName, class); err != nil {
 		return r.handleMissingNamespaceClass(ctx, req.Name, err)
 	}
 
-	log := ctrl.LoggerFrom(ctx).WithValues("reconcile", req.NamespacedName)
-	log.Info("Reconciling NamespaceClass", "name", class.Name)
+	log := classLogger(ctx, class.Name, "")
+	log.Info("Reconciling NamespaceClass")
 
 	if class.DeletionTimestamp != nil {
 		return r.finalizeClass(ctx, log, class)
 	}
 
+	if isPaused(class) {
+		r.forgetRollout(class.Name)
+		log.Info("NamespaceClass is paused; skipping reconcile")
+		r.Recorder.Eventf(class, corev1.EventTypeNormal, "ReconcilePaused",
+			"NamespaceClass is paused; remove annotation %s to resume", NamespaceClassPausedKey)
+		return ctrl.Result{}, nil
+	}
+
+	if class.Status.Phase == "" {
+		if err := r.setPhase(ctx, class, v1alpha1.ClassPhasePending); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 	if err := r.ensureFinalizer(ctx, class); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return r.reconcileClassUpdates(ctx, log, class)
+	class, expandErr := r.effectiveClass(ctx, class)
+	if expandErr != nil {
+		// Apply what can be read, but keep the resources that cannot be
+		log.Error(expandErr, "Not every resource of the class could be read")
+		ctx = withIncompleteSpec(ctx)
+	}
+	if r.tooManyResources(class) {
+		r.forgetRollout(class.Name)
+		return ctrl.Result{}, r.refuseOversizedClass(ctx, log, class)
+	}
+	result, err := r.reconcileClassUpdates(ctx, log, class)
+	if err == nil && expandErr != nil {
+		// Retried with backoff until every resource can be read
+		return ctrl.Result{}, expandErr
+	}
+	return result, err
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *NamespaceClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.Recorder = mgr.GetEventRecorderFor("namespaceclass-controller")
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.NamespaceClass{}). // Primary resource
-		Watches(                         // Watch namespaces to trigger reconcile on the referenced NamespaceClass
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(), &corev1.Namespace{}, NamespaceClassIndexKey, classLabelIndex(r.classLabelKey()),
+	); err != nil {
+		return err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(r.controllerOptions()).
+		For(&v1alpha1.NamespaceClass{}, builder.WithPredicates(classChanged)). // Primary resource
+		// Watch namespaces to trigger reconcile on the referenced NamespaceClass
+		Watches(
 			&corev1.Namespace{},
 			handler.EnqueueRequestsFromMapFunc(r.mapNamespaceToNamespaceClass),
-			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+			builder.WithPredicates(namespaceChanged),
 		).
-		Complete(r)
+		// Requests of single namespaces of a class
+		WatchesRawSource(r.requestSource())
+
+	// Watch classes again so that changes of a parent reach the classes extending it
+	b = b.Watches(
+		&v1alpha1.NamespaceClass{},
+		handler.EnqueueRequestsFromMapFunc(r.mapParentToChildClasses),
+		builder.WithPredicates(classChanged),
+	)
+
+	// Watch classes once more for the namespaces that compose them into their own class
+	b = b.Watches(
+		&v1alpha1.NamespaceClass{},
+		handler.EnqueueRequestsFromMapFunc(r.mapAdditionalClassToNamespaceClasses),
+		builder.WithPredicates(classChanged),
+	)
+
+	// Watch the ConfigMaps of spec.resourceRefs and spec.valuesRef so that classes pick up their changes
+	if r.OperatorNamespace != "" {
+		b = b.Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.mapResourceRefToNamespaceClasses),
+		)
+	}
+
+	// Watch the Secrets of spec.secretRefs so that their copies are kept up to
+	// date. Only their metadata is cached; sources are read when they are copied.
+	b = b.Watches(
+		watchedObject(corev1.SchemeGroupVersion.WithKind("Secret")),
+		handler.EnqueueRequestsFromMapFunc(r.mapSecretRefToNamespaceClasses),
+	)
+
+	// Watch injected resources so that deleted ones are re-injected by their class
+	for _, gvk := range r.watchedKinds() {
+		b = b.Watches(
+			watchedObject(gvk),
+			handler.EnqueueRequestsFromMapFunc(r.mapOwnedResourceToNamespaceClass),
+			builder.WithPredicates(ownedResourceDeleted),
+		)
+	}
+
+	return b.Complete(r)
+}
+
+// controllerOptions returns the options the controller is built with.
+func (r *NamespaceClassReconciler) controllerOptions() controller.Options {
+	return controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}
+}
+
+// classChanged ignores NamespaceClass updates that neither advance the spec
+// generation nor touch annotations, such as status writes, which would otherwise
+// cause a reconcile after every reconcile.
+var classChanged = predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})
+
+// namespaceChanged ignores Namespace updates that cannot change what is applied
+// to the namespace, such as the operator recording the applied class or other
+// annotation updates. Labels decide the class and feed selectors and
+// templates, so every label change counts, as do changes of the cleanup
+// annotations, finalizers, deletion and phase.
+var namespaceChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldObj, newObj := e.ObjectOld, e.ObjectNew
+		if !maps.Equal(oldObj.GetLabels(), newObj.GetLabels()) {
+			return true
+		}
+		for _, key := range []string{NamespaceClassCleanupKey, NamespaceClassCleanupObsoleteKey} {
+			if oldObj.GetAnnotations()[key] != newObj.GetAnnotations()[key] {
+				return true
+			}
+		}
+		if !slices.Equal(oldObj.GetFinalizers(), newObj.GetFinalizers()) ||
+			!oldObj.GetDeletionTimestamp().Equal(newObj.GetDeletionTimestamp()) {
+			return true
+		}
+		oldNs, ok := oldObj.(*corev1.Namespace)
+		newNs, newOk := newObj.(*corev1.Namespace)
+		return ok && newOk && oldNs.Status.Phase != newNs.Status.Phase
+	},
+}
+
+// boundNamespaces returns the sorted, unique names of the namespaces that are
+// not being deleted.
+func boundNamespaces(namespaces []corev1.Namespace) []string {
+	var names []string
+	for _, ns := range namespaces {
+		if isTerminating(&ns) {
+			continue
+		}
+		names = append(names, ns.Name)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// namespaceStatus returns the status entry of a namespace after the class was
+// applied to it with the given changes and error. LastApplied is carried over
+// from the previous entry unless resources were changed.
+func (r *NamespaceClassReconciler) namespaceStatus(
+	class *v1alpha1.NamespaceClass,
+	name string,
+	changes []string,
+	err error,
+) v1alpha1.NamespaceStatus {
+	status := v1alpha1.NamespaceStatus{Name: name, Ready: err == nil}
+	if err != nil {
+		status.Message = err.Error()
+	}
+	for _, previous := range class.Status.Namespaces {
+		if previous.Name == name {
+			status.LastApplied = previous.LastApplied
+		}
+	}
+	if len(changes) > 0 && !r.DryRun {
+		now := metav1.Now()
+		status.LastApplied = &now
+	}
+	return status
+}
+
+// recordSkipped records in the status entry of ns the objects, as "Kind/name",
+// that provisioning ns found in place and left alone. The entry is added, as
+// ready when the rest was applied, if the class has not reported on ns yet.
+func (r *NamespaceClassReconciler) recordSkipped(
+	ctx context.Context,
+	class *v1alpha1.NamespaceClass,
+	ns string,
+	applied bool,
+	skipped []string,
+) error {
+	before := class.Status.DeepCopy()
+	byName := func(s v1alpha1.NamespaceStatus) bool { return s.Name == ns }
+	if !slices.ContainsFunc(class.Status.Namespaces, byName) {
+		if len(skipped) == 0 {
+			return nil
+		}
+		class.Status.Namespaces = append(class.Status.Namespaces, v1alpha1.NamespaceStatus{Name: ns, Ready: applied})
+		slices.SortFunc(class.Status.Namespaces, func(a, b v1alpha1.NamespaceStatus) int { return strings.Compare(a.Name, b.Name) })
+	}
+	class.Status.Namespaces[slices.IndexFunc(class.Status.Namespaces, byName)].SkippedResources = skipped
+	if equality.Semantic.DeepEqual(before, &class.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, class)
+}
+
+// ownedByOther reports whether the live object of obj does not belong to the
+// class named className, e.g. because it was created by hand.
+func (r *NamespaceClassReconciler) ownedByOther(ctx context.Context, obj *unstructured.Unstructured, className string) bool {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		return false
+	}
+	return live.GetLabels()[NamespaceClassOwnedByKey] != className
+}
+
+// reportEmptyClass emits an Event on a class that defines no resources, once
+// when it becomes empty rather than with every reconcile.
+func (r *NamespaceClassReconciler) reportEmptyClass(class *v1alpha1.NamespaceClass, namespaces int) {
+	if len(class.Spec.Resources) > 0 {
+		return
+	}
+	if ready := meta.FindStatusCondition(class.Status.Conditions, v1alpha1.ConditionReady); ready != nil &&
+		ready.Reason == v1alpha1.ReasonNoResources {
+		return
+	}
+	r.Recorder.Eventf(class, corev1.EventTypeNormal, "EmptyNamespaceClass",
+		"NamespaceClass defines no resources; nothing is applied to its %d namespace(s)", namespaces)
+}
+
+// setReadyCondition sets the Ready condition from the per-namespace failures of
+// the current reconcile.
+func setReadyCondition(class *v1alpha1.NamespaceClass, failures []string) {
+	cond := metav1.Condition{
+		Type:               v1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             v1alpha1.ReasonApplied,
+		Message:            "All resources were applied",
+		ObservedGeneration: class.Generation,
+	}
+	if len(class.Spec.Resources) == 0 {
+		cond.Reason = v1alpha1.ReasonNoResources
+		cond.Message = "NamespaceClass defines no resources"
+	}
+	if len(failures) > 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = v1alpha1.ReasonApplyFailed
+		cond.Message = conditionMessage(failures)
+	}
+	meta.SetStatusCondition(&class.Status.Conditions, cond)
+}
+
+// maxConditionMessage is the longest message, in bytes, the API server accepts
+// in a condition.
+const maxConditionMessage = 32768
+
+// conditionMessage joins the problems reported in a condition with "; ". The
+// problems that do not fit into a condition message are left out and counted
+// at the end instead, so that a class failing in thousands of namespaces can
+// still write its status.
+func conditionMessage(problems []string) string {
+	const sep = "; "
+	if msg := strings.Join(problems, sep); len(msg) <= maxConditionMessage {
+		return msg
+	}
+	var b strings.Builder
+	for i, problem := range problems {
+		more := fmt.Sprintf("... and %d more", len(problems)-i)
+		if b.Len()+len(problem)+len(sep)+len(more) <= maxConditionMessage {
+			b.WriteString(problem + sep)
+			continue
+		}
+		if i == 0 {
+			// A first problem that is too long is cut rather than left out
+			more = ""
+			if len(problems) > 1 {
+				more = fmt.Sprintf("%s... and %d more", sep, len(problems)-1)
+			}
+			cut := problem[:maxConditionMessage-len("...")-len(more)]
+			return strings.ToValidUTF8(cut, "") + "..." + more
+		}
+		b.WriteString(more)
+		return b.String()
+	}
+	return b.String()
 }
 
 func (r *NamespaceClassReconciler) ensureFinalizer(ctx context.Context, class *v1alpha1.NamespaceClass) error {
@@ -128,9 +687,16 @@ func (r *NamespaceClassReconciler) ensureFinalizer(ctx context.Context, class *v
 func (r *NamespaceClassReconciler) finalizeClass(ctx context.Context, log logr.Logger, class *v1alpha1.NamespaceClass) (ctrl.Result, error) {
 	if controllerutil.ContainsFinalizer(class, NamespaceClassFinalizerKey) {
 		log.Info("Finalizing NamespaceClass deletion")
-		if res, err := r.reconcileNamespaceClassDelete(ctx, class.Name); err != nil {
+		if isPaused(class) {
+			// Deleting the class must not be blocked, but its resources stay
+			log.Info("NamespaceClass is paused; leaving its resources in place")
+		} else if res, err := r.reconcileNamespaceClassDelete(ctx, class.Name); err != nil && !r.giveUpFinalizing(log, class, err) {
 			return res, err
 		}
+		namespacesManaged.DeleteLabelValues(class.Name)
+		r.forgetApplyLimiter(class.Name)
+		r.forgetRollout(class.Name)
+		r.forgetFinalizeAttempts(class.UID)
 		controllerutil.RemoveFinalizer(class, NamespaceClassFinalizerKey)
 		if err := r.Update(ctx, class); err != nil {
 			return ctrl.Result{}, err
@@ -144,60 +710,167 @@ func (r *NamespaceClassReconciler) handleMissingNamespaceClass(ctx context.Conte
 		return ctrl.Result{}, err
 	}
 
-	var nsList corev1.NamespaceList
-	if listErr := r.List(ctx, &nsList, client.MatchingLabels{NamespaceClassNameKey: className}); listErr != nil {
+	namespaces, listErr := r.namespacesForClass(ctx, className, nil)
+	if listErr != nil {
 		return ctrl.Result{}, listErr
 	}
-	for _, ns := range nsList.Items {
-		r.Recorder.Eventf(&ns, corev1.EventTypeWarning, "OrphanedNamespaceClass",
+	for _, ns := range namespaces {
+		r.warnOnce(&ns, "OrphanedNamespaceClass",
 			"Namespace references missing NamespaceClass '%s'", className)
 	}
 	return ctrl.Result{}, nil
 }
 
 func (r *NamespaceClassReconciler) mapNamespaceToNamespaceClass(ctx context.Context, obj client.Object) []reconcile.Request {
-	className := obj.GetLabels()[NamespaceClassNameKey]
-	if className == "" {
-		return nil
+	if r.needsRelease(ctx, obj) {
+		// Released namespaces are handled by the namespace path of Reconcile
+		return []reconcile.Request{namespaceKey(obj.GetName())}
+	}
+
+	var requests []reconcile.Request
+	if className, ok := r.classNameFor(ctx, obj); ok {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: className}})
 	}
-	return []reconcile.Request{{
-		NamespacedName: types.NamespacedName{Name: className},
-	}}
+	// Classes that select the namespace but lose to another class report the conflict
+	for _, className := range r.selectingClasses(ctx, obj) {
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Name: className}}
+		if !slices.Contains(requests, request) {
+			requests = append(requests, request)
+		}
+	}
+	return requests
 }
 
 func (r *NamespaceClassReconciler) reconcileClassUpdates(ctx context.Context, log logr.Logger, class *v1alpha1.NamespaceClass) (ctrl.Result, error) {
-	currentMap := toNameGVKMap(class.Spec.Resources)
-	lastAppliedMap := toNameGVKMap(class.Status.LastAppliedResources)
-	removed := diffRemoved(lastAppliedMap, currentMap)
+	defer observeDuration(phaseClassUpdate, time.Now())
 
-	var nsList corev1.NamespaceList
-	if err := r.List(ctx, &nsList, client.MatchingLabels{NamespaceClassNameKey: class.Name}); err != nil {
+	ro := r.currentRollout(class.Name)
+	if ro == nil {
+		var err error
+		if ro, err = r.startRollout(ctx, log, class); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if ro.markStale() {
+		log.Info("NamespaceClass is still being applied; applying it again afterwards")
+	}
+	if !ro.done() {
+		// The last namespace request enqueues the class again
+		return ctrl.Result{}, nil
+	}
+	result, err := r.reportRollout(ctx, log, class, ro)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	r.forgetRollout(class.Name)
+	if ro.stale {
+		r.enqueue(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: class.Name}})
+	}
+	return result, nil
+}
+
+// reportRollout writes the outcome of ro, which is done, to the status of
+// class.
+func (r *NamespaceClassReconciler) reportRollout(
+	ctx context.Context,
+	log logr.Logger,
+	class *v1alpha1.NamespaceClass,
+	ro *rollout,
+) (ctrl.Result, error) {
+	// The namespace requests wrote the status meanwhile
+	latest := &v1alpha1.NamespaceClass{}
+	if err := r.Get(ctx, types.NamespacedName{Name: class.Name}, latest); err != nil {
+		return ctrl.Result{}, err
+	}
+	class.Status = latest.Status
+	class.ResourceVersion = latest.ResourceVersion
+
+	var result ctrl.Result
+	var failures, pending []string
+	var statuses []v1alpha1.NamespaceStatus
+	var conflicts []v1alpha1.FieldConflict
+	for _, ns := range slices.Sorted(maps.Keys(ro.results)) {
+		res := ro.results[ns]
+		if r.DryRun {
+			for _, change := range res.changes {
+				pending = append(pending, ns+": "+change)
+			}
+		}
+		statuses = append(statuses, r.namespaceStatus(class, ns, res.changes, res.err))
+		conflicts = append(conflicts, namespaceConflicts(ns, res.err)...)
+		if res.err == nil {
+			continue
+		}
+		if errors.Is(res.err, errUnresolvableKind) {
+			result.RequeueAfter = unresolvableKindRequeueAfter
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", ns, res.err))
+	}
+	namespacesManaged.WithLabelValues(class.Name).Set(float64(ro.managed))
+	if r.hasUnresolvableKinds(class) {
+		result.RequeueAfter = unresolvableKindRequeueAfter
+	}
+
+	before := class.Status.DeepCopy()
+	if !r.DryRun && !incompleteSpec(ctx) {
+		// Nothing was applied, so the obsolete resources are still to be removed
+		if len(failures) == 0 {
+			// Namespaces that failed still hold what the class no longer defines
+			class.Status.LastAppliedResources = withoutCopiedSecretData(class.Spec.Resources)
+			class.Status.AppliedResourceCount = len(class.Spec.Resources)
+		}
+		class.Status.ClusterScopedResources = slices.DeleteFunc(clusterScopedResources(class, ro.clusterApplied),
+			func(key string) bool { return slices.Contains(ro.released, key) })
+	}
+	class.Status.PendingChanges = pending
+	setFieldConflicts(class, "", conflicts)
+	if ro.forced {
+		class.Status.LastHandledForceReconcile = ro.forceValue
+	}
+	bound := slices.Sorted(slices.Values(ro.bound))
+	r.pruneResourceFailures(class, bound)
+	if len(failures) == 0 {
+		// A generation that is not observed signals a stuck reconcile
+		class.Status.ObservedGeneration = class.Generation
+	}
+	class.Status.BoundNamespaces = slices.Compact(bound)
+	slices.SortFunc(statuses, func(a, b v1alpha1.NamespaceStatus) int { return strings.Compare(a.Name, b.Name) })
+	class.Status.Namespaces = statuses
+	r.reportEmptyClass(class, len(class.Status.BoundNamespaces))
+	setReadyCondition(class, failures)
+	class.Status.Phase = phaseFor(failures)
+	r.setResourcesValidCondition(class)
+	r.setReferencesValidCondition(class)
 
-	for _, ns := range nsList.Items {
-		r.reconcileNamespaceForClass(ctx, log.WithValues("namespace", ns.Name), &ns, class, removed)
+	// Writing an unchanged status would only cause churn
+	if equality.Semantic.DeepEqual(before, &class.Status) {
+		return r.withResync(result), nil
 	}
+	class.Status.LastReconcileTime = metav1.Now()
 
-	class.Status.LastAppliedResources = class.Spec.Resources
 	if err := r.Status().Update(ctx, class); err != nil {
 		log.Error(err, "Failed to update NamespaceClass status")
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return r.withResync(result), nil
+}
+
+// withResync schedules the next periodic resync unless an earlier requeue is
+// already requested.
+func (r *NamespaceClassReconciler) withResync(result ctrl.Result) ctrl.Result {
+	if r.ResyncPeriod <= 0 {
+		return result
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > r.ResyncPeriod {
+		result.RequeueAfter = r.ResyncPeriod
+	}
+	return result
 }
 
 func (r *NamespaceClassReconciler) reconcileNamespaceClassDelete(ctx context.Context, className string) (ctrl.Result, error) {
-	log := ctrl.LoggerFrom(ctx).WithValues("deletedNamespaceClass", className)
+	defer observeDuration(phaseClassDelete, time.Now())
 
-	var nsList corev1.NamespaceList
-	if err := r.List(ctx, &nsList, client.MatchingLabels{
-		NamespaceClassNameKey: className,
-	}); err != nil {
-		log.Error(err, "Failed to list namespaces for cleanup")
-		return ctrl.Result{}, err
-	}
+	log := classLogger(ctx, className, "")
 
 	var class v1alpha1.NamespaceClass
 	if err := r.Get(ctx, types.NamespacedName{Name: className}, &class); err != nil {
@@ -205,146 +878,903 @@ func (r *NamespaceClassReconciler) reconcileNamespaceClassDelete(ctx context.Con
 		return ctrl.Result{}, nil // Don't fail reconciliation; just skip
 	}
 
-	for _, ns := range nsList.Items {
-		log := log.WithValues("namespace", ns.Name)
+	err := r.forEachNamespaceOfClass(ctx, className, class.Spec.NamespaceSelector, func(namespaces []corev1.Namespace) error {
+		for _, ns := range namespaces {
+			log := log.WithValues(logKeyNamespace, ns.Name)
 
-		cleanup := ns.Annotations[NamespaceClassCleanupKey] == "true"
-		if cleanup {
-			for _, res := range class.Spec.Resources {
-				obj := &unstructured.Unstructured{}
-				if err := obj.UnmarshalJSON(res.Raw); err != nil {
-					continue
+			cleanup := ns.Annotations[NamespaceClassCleanupKey] == "true"
+			if cleanup {
+				resolved, err := r.resolvedClass(ctx, &ns, &class)
+				if err != nil {
+					// Deleting what can be read leaves less behind than deleting nothing
+					log.Error(err, "Not every resource of the class could be read")
 				}
+				r.deleteInjected(ctx, log, &ns, resolved)
+			} else {
+				log.Info("Skipping cleanup; annotation not set")
 
-				gvk := obj.GroupVersionKind()
-				name := obj.GetName()
-
-				obj.SetNamespace(ns.Name)
-
-				if err := r.Delete(ctx, obj); err != nil {
-					log.Error(err, "Failed to delete resource", "kind", gvk.Kind, "name", name)
-				} else {
-					log.Info("Deleted resource", "kind", gvk.Kind, "name", name)
-				}
+				r.warnOnce(&ns, "OrphanedNamespaceClass",
+					"Namespace references deleted NamespaceClass '%s' but does not have cleanup enabled", className)
 			}
-		} else {
-			log.Info("Skipping cleanup; annotation not set")
-
-			r.Recorder.Eventf(&ns, corev1.EventTypeWarning, "OrphanedNamespaceClass",
-				"Namespace references deleted NamespaceClass '%s' but does not have cleanup enabled", className)
 		}
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to list namespaces for cleanup")
+		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// inactiveNamespaceRequeueAfter is how long to wait for a namespace that has
+// not become Active yet before applying its class.
+const inactiveNamespaceRequeueAfter = 2 * time.Second
+
+// isActive reports whether ns has reached the Active phase, after which it is
+// safe to create objects in it.
+func isActive(ns *corev1.Namespace) bool {
+	return ns.Status.Phase == corev1.NamespaceActive
+}
+
 func (r *NamespaceClassReconciler) reconcileNamespaceCreate(ctx context.Context, ns *corev1.Namespace) (ctrl.Result, error) {
-	log := ctrl.LoggerFrom(ctx).WithValues("namespace", ns.Name)
+	defer observeDuration(phaseNamespaceCreate, time.Now())
+
+	log := classLogger(ctx, "", ns.Name)
 
 	log.Info("Reconciling namespace")
 
-	className, ok := ns.Labels[NamespaceClassNameKey]
+	if !r.managesNamespace(ns.Name) {
+		log.Info("Skipping namespace not managed by the operator")
+		return ctrl.Result{}, nil
+	}
+
+	if r.needsRelease(ctx, ns) {
+		log.Info("Releasing namespace from its NamespaceClass", logKeyClass, ns.Annotations[NamespaceClassAppliedClassKey])
+		return ctrl.Result{}, r.releaseNamespace(ctx, log, ns)
+	}
+
+	if isTerminating(ns) {
+		// Creates in a namespace that is going away are refused
+		log.V(1).Info("Skipping terminating namespace")
+		return ctrl.Result{}, nil
+	}
+
+	className, ok := r.classNameFor(ctx, ns)
 	if !ok {
 		log.Info("Skipping namespace without NamespaceClass label")
 		return ctrl.Result{}, nil
 	}
+	log = log.WithValues(logKeyClass, className)
+
+	if !isActive(ns) {
+		// Creating too early races with the controllers that set up the namespace
+		log.V(1).Info("Waiting for namespace to become Active", "phase", ns.Status.Phase)
+		return ctrl.Result{RequeueAfter: inactiveNamespaceRequeueAfter}, nil
+	}
 
 	var class v1alpha1.NamespaceClass
 	if err := r.Get(ctx, types.NamespacedName{Name: className}, &class); err != nil {
-		log.Error(err, "Failed to get NamespaceClass", "className", className)
-		r.Recorder.Eventf(ns, corev1.EventTypeWarning, "MissingNamespaceClass",
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to get NamespaceClass")
+			return ctrl.Result{}, err
+		}
+		// The class watch applies it once the class is created, so retrying
+		// with backoff until then would only add noise.
+		log.Info("Waiting for missing NamespaceClass")
+		r.warnOnce(ns, "MissingNamespaceClass",
 			"Namespace references missing NamespaceClass '%s'", className)
+		return ctrl.Result{}, nil
+	}
+	r.eventCache().forget(ns, "MissingNamespaceClass")
+	if isPaused(&class) {
+		// Resuming the class reconciles it, which applies it to the namespace
+		log.Info("NamespaceClass is paused; skipping namespace")
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("Applying NamespaceClass")
+
+	expanded, expandErr := r.effectiveClass(ctx, &class)
+	if expandErr != nil {
+		log.Error(expandErr, "Not every resource of the class could be read")
+		ctx = withIncompleteSpec(ctx)
+	}
+	expanded, err := r.composedClass(ctx, ns, expanded)
+	if err != nil {
+		log.Error(err, "Not every resource of the additional classes could be read")
+		ctx = withIncompleteSpec(ctx)
+		expandErr = errors.Join(expandErr, err)
+	}
+	if r.tooManyResources(expanded) {
+		// Additional classes count against the limit too
+		log.Info("Skipping NamespaceClass with too many resources",
+			"resources", len(expanded.Spec.Resources), "limit", r.MaxResourcesPerClass)
+		r.Recorder.Eventf(ns, corev1.EventTypeWarning, "TooManyResources",
+			"NamespaceClass '%s' defines %d resources, more than the limit of %d; nothing was applied",
+			className, len(expanded.Spec.Resources), r.MaxResourcesPerClass)
+		return ctrl.Result{}, nil
+	}
+	if len(expanded.Spec.Resources) == 0 && ns.Annotations[NamespaceClassAppliedClassKey] != className {
+		r.Recorder.Eventf(ns, corev1.EventTypeNormal, "EmptyNamespaceClass",
+			"NamespaceClass '%s' defines no resources; nothing was applied", className)
+	}
+	r.cleanupPreviousClass(ctx, log, ns, expanded)
+
+	built, complete, err := r.buildResources(ctx, log, ns, expanded)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	objs, err := r.resolveKinds(log, ns, built)
+	if err != nil {
+		log.Info("Waiting for unresolvable kind", "reason", err.Error())
+		return ctrl.Result{RequeueAfter: unresolvableKindRequeueAfter}, nil
+	}
+	objs, resolved := r.resolveGeneratedNames(ctx, log, objs)
+	hash := hashResources(objs)
+	inv := r.inventory(log, ns, objs)
+	if r.inSync(ctx, ns, hash, objs) {
+		log.Info("Namespace is in sync with NamespaceClass; skipping apply")
+		return ctrl.Result{}, nil
+	}
 
-	log.Info("Applying NamespaceClass", "class", className)
+	start := time.Now()
+	applied := true
+	var created, adopted, skipped []string
+	for _, obj := range objs {
+		if r.retriesExhausted(expanded, ns.Name, obj) {
+			withObject(log, obj).Info("Skipping resource that ran out of retries")
+			applied = false
+			continue
+		}
 
-	for _, res := range class.Spec.Resources {
-		obj := &unstructured.Unstructured{}
-		if err := obj.UnmarshalJSON(res.Raw); err != nil {
-			log.Error(err, "Failed to unmarshal embedded resource")
+		if isDeletion(obj) {
+			if err := r.deleteMarked(ctx, log, obj, r.deleteOptions(expanded)...); err != nil {
+				applied = false
+			}
 			continue
 		}
 
-		// Force the resource into the namespace
-		obj.SetNamespace(ns.Name)
+		if isPatch(obj) {
+			if err := r.patchExisting(ctx, obj); err != nil {
+				applied = false
+			}
+			continue
+		}
+		if isClusterScoped(obj) && clusterScopedApplied(expanded, obj) {
+			withObject(log, obj).Info("Skipping cluster-scoped resource already created by the class")
+			continue
+		}
+		if isClusterScoped(obj) {
+			if err := r.clusterScopedConflict(ctx, obj, expanded); err != nil {
+				withObject(log, obj).Error(err, "Skipping cluster-scoped resource")
+				applied = false
+				continue
+			}
+		}
 
-		if err := r.Create(ctx, obj); err != nil {
-			log.Error(err, "Failed to create resource in namespace", "gvk", obj.GroupVersionKind())
+		if err := setLastApplied(obj); err != nil {
+			withObject(log, obj).Error(err, "Failed to record last applied configuration")
+			applied = false
+			continue
+		}
+		err := r.writer().Create(ctx, obj, client.FieldOwner(FieldManager))
+		if apierrors.IsAlreadyExists(err) && r.AdoptExisting {
+			ok, err := r.adopt(ctx, log, ns, obj)
+			if err != nil {
+				withObject(log, obj).Error(err, "Failed to adopt resource")
+				applied = false
+			} else if ok {
+				adopted = append(adopted, resourceRef(obj))
+			}
+			continue
+		}
+		if apierrors.IsAlreadyExists(err) {
+			if r.ownedByOther(ctx, obj, className) {
+				withObject(log, obj).Info("Skipping resource that already exists")
+				r.Recorder.Eventf(ns, corev1.EventTypeWarning, "ResourceSkipped",
+					"%s '%s' already exists and does not belong to NamespaceClass '%s'; it was left as is",
+					obj.GetKind(), obj.GetName(), className)
+				skipped = append(skipped, resourceRef(obj))
+			}
+			continue
+		}
+		if err != nil {
+			withObject(log, obj).Error(err, "Failed to create resource in namespace")
+			applied = false
 			continue
 		}
+		r.countApplied(obj)
+		created = append(created, resourceRef(obj))
 
-		log.Info("Created resource", "kind", obj.GetKind(), "name", obj.GetName())
+		withObject(log, obj).Info("Created resource")
 	}
 
-	return ctrl.Result{}, nil
+	switch {
+	case len(created) == 0 && len(adopted) == 0:
+	case r.DryRun:
+		r.reportDryRun(log, ns, className, append(prefixAll("create ", created), prefixAll("adopt ", adopted)...))
+	default:
+		if len(created) > 0 {
+			log.Info("Injected resources", "count", len(created), "duration", time.Since(start))
+			r.Recorder.Eventf(ns, corev1.EventTypeNormal, "ResourcesInjected",
+				"Injected %d resource(s) from NamespaceClass '%s': %s", len(created), className, strings.Join(created, ", "))
+		}
+		if len(adopted) > 0 {
+			r.Recorder.Eventf(ns, corev1.EventTypeNormal, "ResourcesAdopted",
+				"Adopted %d existing resource(s) into NamespaceClass '%s': %s", len(adopted), className, strings.Join(adopted, ", "))
+		}
+	}
+
+	if err := r.recordSkipped(ctx, &class, ns.Name, applied, skipped); err != nil {
+		log.Error(err, "Failed to update NamespaceClass status")
+		return ctrl.Result{}, err
+	}
+	if !applied || !complete || !resolved || incompleteSpec(ctx) {
+		hash = ""
+	}
+	if err := r.recordApplied(ctx, ns, className, hash, inv,
+		ns.Annotations[NamespaceClassAppliedCleanupObsoleteKey] == "true"); err != nil {
+		log.Error(err, "Failed to record applied NamespaceClass")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, expandErr
 }
 
+// reconcileNamespaceForClass applies class to a single namespace. It returns the
+// changes it made, or in dry-run mode would make, and the errors of the
+// resources that could not be applied, which are reported in the Ready
+// condition of the class. The resources of lastApplied that class no longer
+// defines are obsolete. Cluster-scoped objects are recorded in clusterApplied
+// and not applied again for the other namespaces of the class.
 func (r *NamespaceClassReconciler) reconcileNamespaceForClass(
 	ctx context.Context,
 	log logr.Logger,
 	ns *corev1.Namespace,
 	class *v1alpha1.NamespaceClass,
-	removed map[string]schema.GroupVersionKind,
-) {
+	lastApplied []runtime.RawExtension,
+	clusterApplied map[string]bool,
+) ([]string, error) {
 	cleanup := ns.Annotations[NamespaceClassCleanupObsoleteKey] == "true"
+	// Resources that were removed from the class while the cleanup was off are
+	// gone from the status by now, so they are looked up by label once it is on
+	catchUp := cleanup && ns.Annotations[NamespaceClassAppliedClassKey] == class.Name &&
+		ns.Annotations[NamespaceClassAppliedCleanupObsoleteKey] != "true"
+	cleaned := cleanup
 
-	for _, res := range class.Spec.Resources {
-		obj := &unstructured.Unstructured{}
-		if err := obj.UnmarshalJSON(res.Raw); err != nil {
-			log.Error(err, "Failed to unmarshal resource")
-			continue
+	changes := prefixAll("delete ", r.cleanupPreviousClass(ctx, log, ns, class))
+	class, err := r.composedClass(ctx, ns, class)
+	if err != nil {
+		log.Error(err, "Not every resource of the additional classes could be read")
+		ctx = withIncompleteSpec(ctx)
+	}
+	if r.tooManyResources(class) {
+		// The class itself is within the limit, but not with the additional classes of ns
+		return changes, fmt.Errorf("NamespaceClass %q and the additional classes of the namespace define %d resources, "+
+			"more than the limit of %d", class.Name, len(class.Spec.Resources), r.MaxResourcesPerClass)
+	}
+
+	built, complete, err := r.buildResources(ctx, log, ns, class)
+	if err != nil {
+		return changes, err
+	}
+	objs, err := r.resolveKinds(log, ns, built)
+	if err != nil {
+		log.Info("Waiting for unresolvable kind", "reason", err.Error())
+		return changes, err
+	}
+	objs, resolved := r.resolveGeneratedNames(ctx, log, objs)
+	// Owned objects missing from an incomplete set may still be defined by the
+	// class, so they are not looked up by label for deletion
+	complete = complete && resolved && !incompleteSpec(ctx)
+	var removed map[string]schema.GroupVersionKind
+	if complete {
+		removed = r.removedResources(ctx, ns, class, lastApplied)
+	}
+	hash := hashResources(objs)
+	inv := r.inventory(log, ns, objs)
+	var errs []error
+	if !forceApply(ctx) && r.inSync(ctx, ns, hash, objs) {
+		log.Info("Namespace is in sync with NamespaceClass; skipping apply")
+	} else {
+		// Only namespaces that are written to count against ApplyQPS
+		if err := r.waitToApply(ctx, class.Name); err != nil {
+			log.Info("Stopped waiting to apply to namespace", "reason", err.Error())
+			return changes, fmt.Errorf("waiting to apply: %w", err)
 		}
-		obj.SetNamespace(ns.Name)
-		if err := r.upsert(ctx, obj); err != nil {
-			log.Error(err, "Failed to upsert resource")
+		start := time.Now()
+		var updated []string
+		for _, obj := range objs {
+			if r.DryRun && !r.driftDetected(ctx, []*unstructured.Unstructured{obj}) {
+				// Only report what would actually change
+				continue
+			}
+			if r.retriesExhausted(class, ns.Name, obj) {
+				errs = append(errs, &resourceError{resourceRef(obj),
+					fmt.Errorf("gave up applying %s %q: %w", obj.GetKind(), obj.GetName(), errRetriesExhausted)})
+				continue
+			}
+
+			if isDeletion(obj) {
+				if err := r.deleteMarked(ctx, log, obj, r.deleteOptions(class)...); err != nil {
+					errs = append(errs, &resourceError{resourceRef(obj),
+						fmt.Errorf("failed to delete %s %q: %w", obj.GetKind(), obj.GetName(), err)})
+					continue
+				}
+				changes = append(changes, "delete "+resourceRef(obj))
+				continue
+			}
+
+			if isClusterScoped(obj) {
+				if clusterApplied[objectKey(obj)] || isTerminating(ns) {
+					// A namespace that is going away needs no shared objects
+					continue
+				}
+				if err := r.clusterScopedConflict(ctx, obj, class); err != nil {
+					errs = append(errs, &resourceError{resourceRef(obj), err})
+					continue
+				}
+			}
+			if err := r.upsert(ctx, obj); err != nil {
+				withObject(log, obj).Error(err, "Failed to upsert resource")
+				errs = append(errs, &resourceError{resourceRef(obj),
+					fmt.Errorf("failed to apply %s %q: %w", obj.GetKind(), obj.GetName(), err)})
+				continue
+			}
+			if isClusterScoped(obj) {
+				clusterApplied[objectKey(obj)] = true
+			}
+			updated = append(updated, resourceRef(obj))
+			changes = append(changes, "apply "+resourceRef(obj))
+		}
+
+		if len(updated) > 0 && !r.DryRun {
+			log.Info("Updated resources", "count", len(updated), "duration", time.Since(start))
+			r.Recorder.Eventf(ns, corev1.EventTypeNormal, "ResourcesUpdated",
+				"Applied %d resource(s) from NamespaceClass '%s': %s", len(updated), class.Name, strings.Join(updated, ", "))
 		}
 	}
+	if len(errs) > 0 || !complete {
+		// Skipped resources are retried with every reconcile
+		hash = ""
+	}
 
 	if cleanup {
-		for name, gvk := range removed {
-			obj := &unstructured.Unstructured{}
-			obj.SetGroupVersionKind(gvk)
-			obj.SetName(name)
-			obj.SetNamespace(ns.Name)
-			if err := r.Delete(ctx, obj); err != nil {
-				log.Error(err, "Failed to delete obsolete resource", "kind", gvk.Kind, "name", name)
-			} else {
-				log.Info("Deleted obsolete resource", "kind", gvk.Kind, "name", name)
+		obsolete := r.obsoleteResources(ctx, log, ns, class, removed, objs, complete)
+		if catchUp && !complete {
+			cleaned = false
+		} else if catchUp && r.ObsoleteCleanup != ObsoleteCleanupLabels {
+			log.Info("Obsolete cleanup was enabled; looking up obsolete resources by label")
+			obsolete = append(obsolete, r.obsoleteByLabel(ctx, log, ns, class, objs)...)
+		}
+		var deleted []string
+		for _, obj := range obsolete {
+			if r.isProtected(ctx, obj) {
+				withObject(log, obj).Info("Keeping protected obsolete resource")
+				r.warnOnce(ns, "ObsoleteResourceProtected",
+					"%s '%s' was removed from NamespaceClass '%s' but is not deleted because it has %s=true",
+					obj.GetKind(), obj.GetName(), class.Name, NamespaceClassProtectKey)
+				continue
 			}
+			err := r.writer().Delete(ctx, obj, r.deleteOptions(class)...)
+			switch {
+			case apierrors.IsNotFound(err):
+			case err != nil:
+				withObject(log, obj).Error(err, "Failed to delete obsolete resource")
+				cleaned = false
+			default:
+				withObject(log, obj).Info("Deleted obsolete resource")
+				deleted = append(deleted, resourceRef(obj))
+				if !r.DryRun {
+					r.Recorder.Eventf(ns, corev1.EventTypeNormal, "ObsoleteResourceDeleted",
+						"Deleted %s '%s' because it was removed from NamespaceClass '%s' and the namespace has %s=true",
+						obj.GetKind(), obj.GetName(), class.Name, NamespaceClassCleanupObsoleteKey)
+				}
+			}
+		}
+		if len(deleted) > 0 && !r.DryRun {
+			slices.Sort(deleted)
+			r.Recorder.Eventf(ns, corev1.EventTypeNormal, "ObsoleteResourcesDeleted",
+				"Deleted %d resource(s) no longer defined by NamespaceClass '%s': %s",
+				len(deleted), class.Name, strings.Join(deleted, ", "))
+		}
+		changes = append(changes, prefixAll("delete ", deleted)...)
+	} else if !r.DryRun {
+		r.reportRetained(ctx, log, ns, class, removed)
+	}
+
+	if r.DryRun {
+		r.reportDryRun(log, ns, class.Name, changes)
+	}
+
+	if err := r.recordApplied(ctx, ns, class.Name, hash, inv, cleaned); err != nil {
+		log.Error(err, "Failed to record applied NamespaceClass")
+	}
+	return changes, errors.Join(errs...)
+}
+
+// cleanupPreviousClass deletes the resources injected by the class a namespace
+// was bound to before it switched to class and returns them. Resources that the
+// new class also defines are left in place, and so are those that do not carry
+// the ownership label of the previous class. Nothing is deleted unless the
+// namespace opted into cleanup.
+func (r *NamespaceClassReconciler) cleanupPreviousClass(
+	ctx context.Context,
+	log logr.Logger,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+) []string {
+	previous := ns.Annotations[NamespaceClassAppliedClassKey]
+	if previous == "" || previous == class.Name {
+		return nil
+	}
+
+	log = log.WithValues("previousClass", previous)
+
+	if ns.Annotations[NamespaceClassCleanupKey] != "true" {
+		log.Info("Skipping cleanup of previous class resources; annotation not set")
+		return nil
+	}
+	if incompleteSpec(ctx) {
+		// Resources of the new class that cannot be read would be deleted
+		log.Info("Skipping cleanup of previous class resources; not every resource of the class could be read")
+		return nil
+	}
+
+	var old v1alpha1.NamespaceClass
+	if err := r.Get(ctx, types.NamespacedName{Name: previous}, &old); err != nil {
+		log.Error(err, "Previous class not found — skipping resource cleanup")
+		return nil
+	}
+	if isPaused(&old) {
+		log.Info("Previous class is paused — skipping resource cleanup")
+		return nil
+	}
+
+	var deleted []string
+	expanded, err := r.effectiveClass(ctx, &old)
+	if err != nil {
+		// Deleting what can be read leaves less behind than deleting nothing
+		log.Error(err, "Not every resource of the previous class could be read")
+	}
+	previousNames, _ := r.renderedNames(ctx, ns, expanded, expanded.Spec.Resources)
+	currentNames, ok := r.renderedNames(ctx, ns, class, class.Spec.Resources)
+	if !ok {
+		// Resources of the new class that fail to render would be deleted
+		log.Info("Skipping cleanup of previous class resources; not every resource of the class could be rendered")
+		return nil
+	}
+	removed := diffRemoved(previousNames, currentNames)
+	for _, name := range slices.Sorted(maps.Keys(removed)) {
+		gvk := removed[name]
+		if !r.kindAllowed(gvk) {
+			continue
+		}
+		obj := r.ownedObject(ctx, log, ns, gvk, types.NamespacedName{Name: name, Namespace: ns.Name}, previous)
+		if obj == nil {
+			continue
+		}
+		err := r.writer().Delete(ctx, obj, r.deleteOptions(&old)...)
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			withResource(log, gvk, name).Error(err, "Failed to delete resource of previous class")
+		default:
+			withResource(log, gvk, name).Info("Deleted resource of previous class")
+			deleted = append(deleted, resourceRef(obj))
 		}
 	}
+	return deleted
+}
+
+// recordApplied stores the name of the class that was last applied to the
+// namespace, so that a later switch to another class can be detected, together
+// with the hash and inventory of the applied resources and whether obsolete
+// resources were cleaned up. An empty hash means the last apply did not fully
+// succeed and removes the hash annotation, while the inventory of the last
+// successful apply is kept.
+func (r *NamespaceClassReconciler) recordApplied(
+	ctx context.Context,
+	ns *corev1.Namespace,
+	className, hash, inventory string,
+	cleaned bool,
+) error {
+	if r.DryRun {
+		return nil
+	}
+	if err := r.ensureNamespaceFinalizer(ctx, ns); err != nil {
+		return err
+	}
+	if hash == "" {
+		inventory = ns.Annotations[NamespaceClassInventoryKey]
+	}
+	if ns.Annotations[NamespaceClassAppliedClassKey] == className && ns.Annotations[NamespaceClassAppliedHashKey] == hash &&
+		ns.Annotations[NamespaceClassInventoryKey] == inventory &&
+		(ns.Annotations[NamespaceClassAppliedCleanupObsoleteKey] == "true") == cleaned {
+		return nil
+	}
+
+	patch := client.MergeFrom(ns.DeepCopy())
+	if ns.Annotations == nil {
+		ns.Annotations = map[string]string{}
+	}
+	ns.Annotations[NamespaceClassAppliedClassKey] = className
+	if hash == "" {
+		delete(ns.Annotations, NamespaceClassAppliedHashKey)
+	} else {
+		ns.Annotations[NamespaceClassAppliedHashKey] = hash
+	}
+	if inventory != "" {
+		ns.Annotations[NamespaceClassInventoryKey] = inventory
+	}
+	if cleaned {
+		ns.Annotations[NamespaceClassAppliedCleanupObsoleteKey] = "true"
+	} else {
+		delete(ns.Annotations, NamespaceClassAppliedCleanupObsoleteKey)
+	}
+	return r.Patch(ctx, ns, patch)
 }
 
 func (r *NamespaceClassReconciler) upsert(ctx context.Context, obj *unstructured.Unstructured) error {
-	log := ctrl.LoggerFrom(ctx).WithValues("namespace", obj.GetNamespace())
+	defer observeDuration(phaseUpsert, time.Now())
+
+	if isPatch(obj) {
+		return r.patchExisting(ctx, obj)
+	}
+	if isCreateOnly(obj) || isImmutable(obj) {
+		return r.createOnly(ctx, obj)
+	}
+
+	if r.ApplyStrategy == ApplyStrategyServerSide {
+		if err := r.serverSideApply(ctx, obj); err != nil {
+			return err
+		}
+		r.countApplied(obj)
+		return nil
+	}
+
+	log := resourceLogger(ctx, obj)
 
 	key := types.NamespacedName{
 		Name:      obj.GetName(),
 		Namespace: obj.GetNamespace(),
 	}
-	existing := &unstructured.Unstructured{}
-	existing.SetGroupVersionKind(obj.GroupVersionKind())
-
-	if err := r.Get(ctx, key, existing); err == nil {
-		obj.SetResourceVersion(existing.GetResourceVersion())
-		if err := r.Update(ctx, obj); err != nil {
-			log.Error(err, "Failed to update existing resource", "gvk", obj.GroupVersionKind(), "name", obj.GetName())
+	setContentHash(obj)
+	skipped := false
+	var existing *unstructured.Unstructured
+	// The live object may change between Get and Update; merge with the
+	// latest version again instead of failing the reconcile
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing = &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := r.Get(ctx, key, existing); err != nil {
+			return err
+		}
+		if skipped = !forceApply(ctx) && unchanged(obj, existing); skipped {
+			return nil
+		}
+		merged, err := mergeWithLive(obj, existing)
+		if err != nil {
 			return err
 		}
-		log.Info("Updated existing resource", "kind", obj.GetKind(), "name", obj.GetName())
+		return r.writer().Update(ctx, merged, client.FieldOwner(FieldManager))
+	})
+	if err == nil && skipped {
+		log.V(1).Info("Skipping update of unchanged resource")
+		return r.restampGeneration(ctx, obj, existing)
+	}
+	if err == nil {
+		log.Info("Updated existing resource")
+		r.countApplied(obj)
 		return nil
 	}
+	if !apierrors.IsNotFound(err) {
+		log.Error(err, "Failed to update existing resource")
+		return err
+	}
 
-	if err := r.Create(ctx, obj); err != nil {
-		log.Error(err, "Failed to create resource", "gvk", obj.GroupVersionKind(), "name", obj.GetName())
+	if err := setLastApplied(obj); err != nil {
+		return err
+	}
+	if err := r.writer().Create(ctx, obj, client.FieldOwner(FieldManager)); err != nil {
+		log.Error(err, "Failed to create resource")
 		return err
 	}
+	r.countApplied(obj)
+
+	log.Info("Created resource")
+	return nil
+}
+
+// resourceRef formats an object as Kind/name for event messages.
+func resourceRef(obj *unstructured.Unstructured) string {
+	return obj.GetKind() + "/" + obj.GetName()
+}
+
+// isDeletion reports whether an embedded resource is marked for deletion rather
+// than injection.
+func isDeletion(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[NamespaceClassActionKey] == ActionDelete
+}
+
+// deleteMarked deletes a resource marked for deletion with opts. A resource
+// that is already gone counts as deleted.
+func (r *NamespaceClassReconciler) deleteMarked(
+	ctx context.Context,
+	log logr.Logger,
+	obj *unstructured.Unstructured,
+	opts ...client.DeleteOption,
+) error {
+	if err := r.writer().Delete(ctx, obj, opts...); client.IgnoreNotFound(err) != nil {
+		withObject(log, obj).Error(err, "Failed to delete resource marked for deletion")
+		return err
+	}
+	withObject(log, obj).Info("Deleted resource marked for deletion")
+	return nil
+}
+
+// isCreateOnly reports whether an embedded resource must not be updated once it
+// exists.
+func isCreateOnly(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[NamespaceClassApplyPolicyKey] == ApplyPolicyCreateOnly
+}
+
+// isImmutable reports whether an embedded resource must neither be updated nor
+// deleted as obsolete once it was created.
+func isImmutable(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[NamespaceClassImmutableKey] == "true"
+}
+
+// isPaused reports whether class is paused with NamespaceClassPausedKey.
+func isPaused(class *v1alpha1.NamespaceClass) bool {
+	return class.Annotations[NamespaceClassPausedKey] == "true"
+}
+
+// createOnly creates obj unless it already exists, in which case it is left
+// untouched.
+func (r *NamespaceClassReconciler) createOnly(ctx context.Context, obj *unstructured.Unstructured) error {
+	log := resourceLogger(ctx, obj)
+
+	if err := r.writer().Create(ctx, obj, client.FieldOwner(FieldManager)); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			log.Info("Skipping update of existing resource")
+			return nil
+		}
+		log.Error(err, "Failed to create resource")
+		return err
+	}
+
+	log.Info("Created resource")
+	r.countApplied(obj)
+	return nil
+}
+
+// classNameFor returns the class a namespace belongs to: the value of its class
+// label, or, for unlabeled namespaces that are not excluded, the first class by
+// name whose namespace selector matches it or the default class.
+func (r *NamespaceClassReconciler) classNameFor(ctx context.Context, ns client.Object) (string, bool) {
+	if className, ok := ns.GetLabels()[r.classLabelKey()]; ok {
+		return className, true
+	}
+	if selecting := r.selectingClasses(ctx, ns); len(selecting) > 0 {
+		return selecting[0], true
+	}
+	if r.DefaultClass == "" || r.isExcluded(ns.GetName()) {
+		return "", false
+	}
+	return r.DefaultClass, true
+}
+
+func (r *NamespaceClassReconciler) classLabelKey() string {
+	if r.ClassLabelKey != "" {
+		return r.ClassLabelKey
+	}
+	return NamespaceClassNameKey
+}
+
+// classLabelIndex returns the index function for NamespaceClassIndexKey, which
+// indexes Namespaces by the value of the class label key.
+func classLabelIndex(key string) client.IndexerFunc {
+	return func(obj client.Object) []string {
+		if className, ok := obj.GetLabels()[key]; ok {
+			return []string{className}
+		}
+		return nil
+	}
+}
+
+// forEachNamespaceOfClass calls fn with the namespaces that belong to the
+// given class, including unlabeled namespaces when it is the default class or
+// they match selector, one page at a time, see listNamespaces. Labeled
+// namespaces are looked up by class; the default class has to consider every
+// namespace.
+func (r *NamespaceClassReconciler) forEachNamespaceOfClass(
+	ctx context.Context,
+	className string,
+	selector *metav1.LabelSelector,
+	fn func([]corev1.Namespace) error,
+) error {
+	if className != r.DefaultClass {
+		if err := r.listNamespaces(ctx, fn, r.classMembers(className)); err != nil {
+			return err
+		}
+		if selector == nil {
+			return nil
+		}
+		return r.forEachNamespaceSelectedBy(ctx, className, selector, fn)
+	}
+
+	return r.listNamespaces(ctx, func(page []corev1.Namespace) error {
+		var namespaces []corev1.Namespace
+		for _, ns := range page {
+			if name, ok := r.classNameFor(ctx, &ns); ok && name == className {
+				namespaces = append(namespaces, ns)
+			}
+		}
+		return fn(namespaces)
+	})
+}
+
+// namespacesForClass lists all namespaces that belong to the given class at
+// once, see forEachNamespaceOfClass.
+func (r *NamespaceClassReconciler) namespacesForClass(
+	ctx context.Context,
+	className string,
+	selector *metav1.LabelSelector,
+) ([]corev1.Namespace, error) {
+	var namespaces []corev1.Namespace
+	err := r.forEachNamespaceOfClass(ctx, className, selector, func(page []corev1.Namespace) error {
+		namespaces = append(namespaces, page...)
+		return nil
+	})
+	return namespaces, err
+}
 
-	log.Info("Created resource", "kind", obj.GetKind(), "name", obj.GetName())
+// buildResources renders and decodes every embedded resource of class for the
+// namespace, in apply order. Resources whose when selector does not match the
+// namespace are left out. Resources whose template fails, whose kind is not
+// allowed or whose target namespace is not allowed are skipped with a warning
+// Event on the namespace; those that cannot be decoded are skipped with a
+// warning Event on the namespace and the class. complete is false when a
+// resource was skipped because it failed to render or decode, since the
+// objects then do not stand for everything the class defines. Values of the
+// class that cannot be read fail the whole namespace instead, so that the
+// resources that use them are neither applied nor cleaned up.
+func (r *NamespaceClassReconciler) buildResources(
+	ctx context.Context,
+	log logr.Logger,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+) (objs []*unstructured.Unstructured, complete bool, err error) {
+	values, err := r.templateValues(ctx, class)
+	if err != nil {
+		log.Error(err, "Failed to read template values", "configMap", class.Spec.ValuesRef.Name)
+		r.warnOnce(class, "ValuesRefError",
+			"Failed to read template values from ConfigMap '%s': %v", class.Spec.ValuesRef.Name, err)
+		return nil, false, fmt.Errorf("failed to read template values: %w", err)
+	}
+
+	complete = true
+	objs = make([]*unstructured.Unstructured, 0, len(class.Spec.Resources))
+	for i, res := range class.Spec.Resources {
+		raw, err := renderResource(res.Raw, ns, class.Name, values)
+		if err != nil {
+			log.Error(err, "Failed to render embedded resource template")
+			r.Recorder.Eventf(ns, corev1.EventTypeWarning, "TemplateError",
+				"Failed to render resource of NamespaceClass '%s': %v", class.Name, err)
+			complete = false
+			continue
+		}
+
+		obj, err := buildResource(runtime.RawExtension{Raw: raw}, ns.Name, class.Name)
+		if err != nil {
+			log.Error(err, "Failed to unmarshal embedded resource", "index", i)
+			// Every namespace of the class runs into it, but the class needs telling once
+			r.warnOnce(class, "InvalidResource",
+				"Resource %d of NamespaceClass '%s' is invalid: %v", i, class.Name, err)
+			r.Recorder.Eventf(ns, corev1.EventTypeWarning, "InvalidResource",
+				"Resource %d of NamespaceClass '%s' is invalid: %v", i, class.Name, err)
+			complete = false
+			continue
+		}
+		setClassGeneration(obj, class.Generation)
+		setClassUID(obj, class.UID)
+		objs = append(objs, obj)
+	}
+	sortByApplyOrder(log, objs)
+	objs = r.filterByWhen(log, ns, class.Name, objs)
+	objs = r.filterAllowedKinds(log, ns, class.Name, objs)
+	return r.applyTargetNamespaces(log, ns, class.Name, objs), complete, nil
+}
+
+// buildResource decodes an embedded resource and prepares it for injection into
+// the given namespace. Every injected object is forced into the target namespace
+// and labeled with the owning NamespaceClass, so that the create and upsert paths
+// produce identical objects and managed resources can be found with a selector.
+// Resources without an apiVersion are rejected. The stringData of Secrets is
+// folded into their data. Patches are not labeled, since the class does not own
+// the objects they target.
+func buildResource(raw runtime.RawExtension, namespace, className string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+		return nil, err
+	}
+	if obj.GetAPIVersion() == "" {
+		// Decodes without one, but cannot be created
+		return nil, fmt.Errorf("%s %q has no apiVersion", obj.GetKind(), obj.GetName())
+	}
+	if err := foldStringData(obj); err != nil {
+		return nil, err
+	}
+
+	obj.SetNamespace(namespace)
+	if isPatch(obj) {
+		return obj, nil
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[NamespaceClassOwnedByKey] = className
+	obj.SetLabels(labels)
+
+	return obj, nil
+}
+
+// setClassGeneration records the generation of the class an injected object is
+// built from in the NamespaceClassGenerationKey annotation. Patches are left
+// alone, like in buildResource.
+func setClassGeneration(obj *unstructured.Unstructured, generation int64) {
+	if isPatch(obj) {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[NamespaceClassGenerationKey] = strconv.FormatInt(generation, 10)
+	obj.SetAnnotations(annotations)
+}
+
+// restampGeneration patches the NamespaceClassGenerationKey annotation of live,
+// the object of obj that is otherwise unchanged, to the generation obj is built
+// from, so that it shows that it reflects the latest spec of its class without
+// rewriting the object.
+func (r *NamespaceClassReconciler) restampGeneration(ctx context.Context, obj, live *unstructured.Unstructured) error {
+	generation, ok := obj.GetAnnotations()[NamespaceClassGenerationKey]
+	if !ok || live.GetAnnotations()[NamespaceClassGenerationKey] == generation {
+		return nil
+	}
+	patch := client.RawPatch(types.MergePatchType, fmt.Appendf(nil,
+		`{"metadata":{"annotations":{%q:%q}}}`, NamespaceClassGenerationKey, generation))
+	if err := r.writer().Patch(ctx, live, patch, client.FieldOwner(FieldManager)); err != nil {
+		resourceLogger(ctx, obj).Error(err, "Failed to update the class generation of resource")
+		return err
+	}
 	return nil
 }
 
+// setClassUID labels an injected object with the UID of the class it is built
+// from, so that objects left behind by an earlier class of the same name can be
+// told apart. Patches are left alone, and so are objects built from a class
+// that has no UID yet, e.g. one rendered offline.
+func setClassUID(obj *unstructured.Unstructured, uid types.UID) {
+	if isPatch(obj) || uid == "" {
+		return
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[NamespaceClassUIDKey] = string(uid)
+	obj.SetLabels(labels)
+}
+
+// diffRemoved returns the resources of old, by name, that current no longer
+// defines. Callers iterate the result by sorted name, so that resources are
+// deleted in the same order every time.
 func diffRemoved(old, current map[string]schema.GroupVersionKind) map[string]schema.GroupVersionKind {
 	removed := make(map[string]schema.GroupVersionKind)
 	for name, gvk := range old {
@@ -355,11 +1785,15 @@ func diffRemoved(old, current map[string]schema.GroupVersionKind) map[string]sch
 	return removed
 }
 
+// toNameGVKMap maps the names of the resources owned by a class to their kinds.
+// Patches and immutable resources are left out, so that neither their targets
+// nor immutable resources are ever deleted as obsolete. So are resources named
+// by generateName, whose names differ between namespaces.
 func toNameGVKMap(resources []runtime.RawExtension) map[string]schema.GroupVersionKind {
 	result := make(map[string]schema.GroupVersionKind)
 	for _, raw := range resources {
 		obj := &unstructured.Unstructured{}
-		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+		if err := obj.UnmarshalJSON(raw.Raw); err != nil || isPatch(obj) || isImmutable(obj) || obj.GetName() == "" {
 			continue
 		}
 		result[obj.GetName()] = obj.GroupVersionKind()
@@ -24,15 +24,21 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strings"
+	"time"
 )
 
 var _ = Describe("Reconcile", func() {
@@ -55,13 +61,15 @@ var _ = Describe("Reconcile", func() {
 			Expect(cMaps).To(BeEmpty())
 		})
 
-		It("should return an error if the referenced NamespaceClass does not exist", func() {
+		It("should report a missing NamespaceClass without returning an error", func() {
 			ns := newNamespace("test-ns", "public-network")
 
 			r, _, ctx := setupTestReconciler(ns)
 
-			_, err := r.Reconcile(ctx, requestFor(ns))
-			Expect(err).To(HaveOccurred())
+			result, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("MissingNamespaceClass")))
 		})
 
 		It("should skip embedded resources that fail to unmarshal", func() {
@@ -89,6 +97,76 @@ var _ = Describe("Reconcile", func() {
 			Expect(cMaps).To(BeEmpty())
 		})
 
+		It("should emit InvalidResource events for embedded resources that fail to unmarshal", func() {
+			ns := newNamespace("invalid-ns", "invalid-class")
+			class := newNamespaceClass("invalid-class",
+				mustRawConfigMap("valid", map[string]string{"foo": "bar"}),
+				runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "metadata": {"name": "typo"}}`)},
+			)
+			r, _, ctx := setupTestReconciler(ns, class)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+
+			var invalid []string
+			for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+				if event := <-r.Recorder.(*record.FakeRecorder).Events; strings.Contains(event, "InvalidResource") {
+					invalid = append(invalid, event)
+				}
+			}
+			// Once for the class and once for the namespace
+			Expect(invalid).To(HaveLen(2))
+			Expect(invalid).To(HaveEach(And(
+				HavePrefix("Warning InvalidResource Resource 1 of NamespaceClass 'invalid-class' is invalid: "),
+				ContainSubstring("'Kind' is missing"),
+			)))
+		})
+
+		It("should emit the InvalidResource event of the class once for all of its namespaces", func() {
+			first := newNamespace("invalid-first-ns", "invalid-many-class")
+			second := newNamespace("invalid-second-ns", "invalid-many-class")
+			class := newNamespaceClass("invalid-many-class",
+				runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "metadata": {"name": "typo"}}`)},
+			)
+			r, _, ctx := setupTestReconciler(first, second, class)
+			r.EventCooldown = time.Hour
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			var invalid []string
+			for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+				if event := <-r.Recorder.(*record.FakeRecorder).Events; strings.Contains(event, "InvalidResource") {
+					invalid = append(invalid, event)
+				}
+			}
+			// Once for the class and once for each namespace
+			Expect(invalid).To(HaveLen(3))
+		})
+
+		It("should report embedded resources without an apiVersion instead of creating them", func() {
+			ns := newNamespace("no-version-ns", "no-version-class")
+			class := newNamespaceClass("no-version-class",
+				mustRawConfigMap("valid", map[string]string{"foo": "bar"}),
+				runtime.RawExtension{Raw: []byte(`{"kind": "ConfigMap", "metadata": {"name": "typo"}}`)},
+			)
+			r, _, ctx := setupTestReconciler(ns, class)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+
+			var events []string
+			for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+				events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+			}
+			Expect(events).To(ContainElement(And(
+				HavePrefix("Warning InvalidResource Resource 1 of NamespaceClass 'no-version-class' is invalid: "),
+				ContainSubstring(`ConfigMap "typo" has no apiVersion`),
+			)))
+		})
+
 		It("should log and skip resources that already exist", func() {
 			ns := newNamespace("test-ns", "dup-class")
 
@@ -118,6 +196,45 @@ var _ = Describe("Reconcile", func() {
 			Expect(cMaps[0].Name).To(Equal("injected-config"))
 		})
 
+		It("should report resources skipped because they already exist", func() {
+			ns := newNamespace("test-ns", "dup-class")
+			cm := newInjectedConfigMap("injected-config", "test-ns", map[string]string{"foo": "bar"})
+			owned := newInjectedConfigMap("owned-config", "test-ns", map[string]string{"foo": "bar"})
+			owned.Labels = map[string]string{controller.NamespaceClassOwnedByKey: "dup-class"}
+			class := newNamespaceClass("dup-class",
+				mustRawConfigMap("injected-config", map[string]string{"foo": "bar"}),
+				mustRawConfigMap("owned-config", map[string]string{"foo": "bar"}),
+				mustRawConfigMap("new-config", map[string]string{"foo": "bar"}),
+			)
+			r, _, ctx := setupTestReconciler(ns, class, cm, owned)
+
+			_, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listConfigMaps(r.Client, ctx, "test-ns")).To(HaveLen(3))
+
+			var persisted v1alpha1.NamespaceClass
+			Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+			Expect(persisted.Status.Namespaces).To(HaveLen(1))
+			Expect(persisted.Status.Namespaces[0].Name).To(Equal("test-ns"))
+			Expect(persisted.Status.Namespaces[0].SkippedResources).To(Equal([]string{"ConfigMap/injected-config"}))
+
+			var skipped []string
+			for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+				if event := <-r.Recorder.(*record.FakeRecorder).Events; strings.Contains(event, "ResourceSkipped") {
+					skipped = append(skipped, event)
+				}
+			}
+			Expect(skipped).To(ConsistOf(
+				"Warning ResourceSkipped ConfigMap 'injected-config' already exists and does not belong to NamespaceClass 'dup-class'; it was left as is",
+			))
+
+			By("taking the resource over when the class is applied")
+			_, err = r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+			Expect(persisted.Status.Namespaces[0].SkippedResources).To(BeEmpty())
+		})
+
 		It("should apply resources from NamespaceClass into the namespace", func() {
 			ns := newNamespace("test-ns", "public-network")
 			class := newNamespaceClass("public-network", mustRawConfigMap("injected-config", map[string]string{"foo": "bar"}))
@@ -135,6 +252,68 @@ var _ = Describe("Reconcile", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(cm.Data).To(HaveKeyWithValue("foo", "bar"))
 		})
+
+		It("should label created resources with the owning NamespaceClass", func() {
+			ns := newNamespace("test-ns", "public-network")
+			class := newNamespaceClass("public-network", mustRawConfigMap("injected-config", map[string]string{"foo": "bar"}))
+
+			r, _, ctx := setupTestReconciler(ns, class)
+
+			_, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("v1")
+			obj.SetKind("ConfigMap")
+			Expect(r.Get(ctx, types.NamespacedName{Name: "injected-config", Namespace: "test-ns"}, obj)).To(Succeed())
+			Expect(obj.GetLabels()).To(HaveKeyWithValue(controller.NamespaceClassOwnedByKey, "public-network"))
+		})
+
+		It("should wait for a namespace to become Active before creating resources", func() {
+			ns := newNamespace("pending-ns", "pending-class")
+			ns.Status.Phase = ""
+			class := newNamespaceClass("pending-class",
+				mustRawConfigMap("settings", map[string]string{"foo": "bar"}))
+			r, _, ctx := setupTestReconciler(ns, class)
+
+			result, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+			ns.Status.Phase = corev1.NamespaceActive
+			Expect(r.Status().Update(ctx, ns)).To(Succeed())
+			_, err = r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+		})
+
+		It("should not create resources in a terminating namespace", func() {
+			ns := newNamespace("terminating-ns", "terminating-class")
+			ns.Status.Phase = corev1.NamespaceTerminating
+			class := newNamespaceClass("terminating-class",
+				mustRawConfigMap("settings", map[string]string{"foo": "bar"}))
+
+			creates := 0
+			r, _, ctx := setupTestReconcilerWithInterceptor(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					creates++
+					return c.Create(ctx, obj, opts...)
+				},
+			}, ns, class)
+
+			_, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(creates).To(BeZero())
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+			var persisted v1alpha1.NamespaceClass
+			Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(persisted.Status.Conditions, v1alpha1.ConditionReady)).To(BeTrue())
+		})
 	})
 
 	Describe("Delete", func() {
@@ -144,6 +323,7 @@ var _ = Describe("Reconcile", func() {
 
 			class := newDeletedNamespaceClass("clean-class", mustRawConfigMap("to-delete", map[string]string{"foo": "bar"}))
 			injected := newInjectedConfigMap("to-delete", ns.Name, map[string]string{"foo": "bar"})
+			injected.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class.Name}
 
 			r, _, ctx := setupTestReconciler(ns, class, injected)
 
@@ -154,6 +334,40 @@ var _ = Describe("Reconcile", func() {
 			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
 		})
 
+		It("should not delete a resource created by hand under the same name", func() {
+			ns := newNamespace("handmade-ns", "handmade-class")
+			setCleanupAnnotation(ns)
+
+			class := newDeletedNamespaceClass("handmade-class", mustRawConfigMap("user-data", map[string]string{"foo": "bar"}))
+			handmade := newInjectedConfigMap("user-data", ns.Name, map[string]string{"precious": "data"})
+
+			r, _, ctx := setupTestReconciler(ns, class, handmade)
+
+			_, err := r.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: class.Name},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+			Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("DeletionSkipped")))
+		})
+
+		It("should not delete a resource owned by another class", func() {
+			ns := newNamespace("foreign-ns", "foreign-class")
+			setCleanupAnnotation(ns)
+
+			class := newDeletedNamespaceClass("foreign-class", mustRawConfigMap("shared", map[string]string{"foo": "bar"}))
+			foreign := newInjectedConfigMap("shared", ns.Name, map[string]string{"foo": "bar"})
+			foreign.Labels = map[string]string{controller.NamespaceClassOwnedByKey: "other-class"}
+
+			r, _, ctx := setupTestReconciler(ns, class, foreign)
+
+			_, err := r.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: class.Name},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+		})
+
 		It("should emit an event if cleanup annotation is not set", func() {
 			ns := newNamespace("orphan-ns", "orphan-class")
 
@@ -202,6 +416,7 @@ var _ = Describe("Reconcile", func() {
 			}, &cm)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(cm.Data).To(HaveKeyWithValue("foo", "updated"))
+			Expect(cm.Labels).To(HaveKeyWithValue(controller.NamespaceClassOwnedByKey, "class"))
 		})
 
 		It("should be idempotent and not fail when applied twice", func() {
@@ -280,6 +495,399 @@ var _ = Describe("Reconcile", func() {
 		})
 	})
 
+	Describe("Switch", func() {
+		It("should record the applied class on a namespace that never had one", func() {
+			ns := newNamespace("fresh-ns", "class-a")
+			class := newNamespaceClass("class-a", mustRawConfigMap("cm-a", map[string]string{"foo": "a"}))
+
+			r, _, ctx := setupTestReconciler(ns, class)
+
+			_, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+
+			var updated corev1.Namespace
+			Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &updated)).To(Succeed())
+			Expect(updated.Annotations).To(HaveKeyWithValue(controller.NamespaceClassAppliedClassKey, "class-a"))
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+		})
+
+		It("should delete resources of the previous class when switching with cleanup enabled", func() {
+			ns := newNamespace("switch-ns", "class-b")
+			setCleanupAnnotation(ns)
+			ns.Annotations[controller.NamespaceClassAppliedClassKey] = "class-a"
+
+			classA := newNamespaceClass("class-a", mustRawConfigMap("cm-a", map[string]string{"foo": "a"}))
+			classB := newNamespaceClass("class-b", mustRawConfigMap("cm-b", map[string]string{"foo": "b"}))
+			injected := newInjectedConfigMap("cm-a", ns.Name, map[string]string{"foo": "a"})
+			injected.Labels = map[string]string{controller.NamespaceClassOwnedByKey: classA.Name}
+
+			r, _, ctx := setupTestReconciler(ns, classA, classB, injected)
+
+			_, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+
+			cms := listConfigMaps(r.Client, ctx, ns.Name)
+			Expect(cms).To(HaveLen(1))
+			Expect(cms[0].Name).To(Equal("cm-b"))
+
+			var updated corev1.Namespace
+			Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &updated)).To(Succeed())
+			Expect(updated.Annotations).To(HaveKeyWithValue(controller.NamespaceClassAppliedClassKey, "class-b"))
+		})
+
+		It("should keep resources of the previous class when cleanup annotation is missing", func() {
+			ns := newNamespace("keep-ns", "class-b")
+			ns.Annotations = map[string]string{controller.NamespaceClassAppliedClassKey: "class-a"}
+
+			classA := newNamespaceClass("class-a", mustRawConfigMap("cm-a", map[string]string{"foo": "a"}))
+			classB := newNamespaceClass("class-b", mustRawConfigMap("cm-b", map[string]string{"foo": "b"}))
+			injected := newInjectedConfigMap("cm-a", ns.Name, map[string]string{"foo": "a"})
+
+			r, _, ctx := setupTestReconciler(ns, classA, classB, injected)
+
+			_, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+
+			cms := listConfigMaps(r.Client, ctx, ns.Name)
+			Expect(cms).To(HaveLen(2))
+			Expect([]string{cms[0].Name, cms[1].Name}).To(ContainElements("cm-a", "cm-b"))
+		})
+
+		It("should keep resources that both classes define", func() {
+			ns := newNamespace("overlap-ns", "class-b")
+			setCleanupAnnotation(ns)
+			ns.Annotations[controller.NamespaceClassAppliedClassKey] = "class-a"
+
+			shared := mustRawConfigMap("shared", map[string]string{"foo": "shared"})
+			classA := newNamespaceClass("class-a", shared, mustRawConfigMap("cm-a", map[string]string{"foo": "a"}))
+			classB := newNamespaceClass("class-b", shared)
+			injectedShared := newInjectedConfigMap("shared", ns.Name, map[string]string{"foo": "shared"})
+			injectedA := newInjectedConfigMap("cm-a", ns.Name, map[string]string{"foo": "a"})
+			injectedA.Labels = map[string]string{controller.NamespaceClassOwnedByKey: classA.Name}
+
+			r, _, ctx := setupTestReconciler(ns, classA, classB, injectedShared, injectedA)
+
+			_, err := r.Reconcile(ctx, requestFor(classB))
+			Expect(err).NotTo(HaveOccurred())
+
+			cms := listConfigMaps(r.Client, ctx, ns.Name)
+			Expect(cms).To(HaveLen(1))
+			Expect(cms[0].Name).To(Equal("shared"))
+			Expect(cms[0].Labels).To(HaveKeyWithValue(controller.NamespaceClassOwnedByKey, "class-b"))
+		})
+	})
+
+	Describe("DefaultClass", func() {
+		It("should apply the default class to namespaces without a class label", func() {
+			ns := newNamespace("unlabeled-ns", "")
+			class := newNamespaceClass("baseline", mustRawConfigMap("baseline-config", map[string]string{"foo": "bar"}))
+
+			r, _, ctx := setupTestReconciler(ns, class)
+			r.DefaultClass = "baseline"
+
+			_, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+
+			cms := listConfigMaps(r.Client, ctx, ns.Name)
+			Expect(cms).To(HaveLen(1))
+			Expect(cms[0].Name).To(Equal("baseline-config"))
+
+			var unchanged corev1.Namespace
+			Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &unchanged)).To(Succeed())
+			Expect(unchanged.Labels).NotTo(HaveKey(controller.NamespaceClassNameKey))
+		})
+
+		It("should apply the default class to unlabeled namespaces when the class is reconciled", func() {
+			unlabeled := newNamespace("unlabeled-ns", "")
+			labeled := newNamespace("labeled-ns", "other")
+			class := newNamespaceClass("baseline", mustRawConfigMap("baseline-config", map[string]string{"foo": "bar"}))
+
+			r, _, ctx := setupTestReconciler(unlabeled, labeled, class)
+			r.DefaultClass = "baseline"
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(listConfigMaps(r.Client, ctx, unlabeled.Name)).To(HaveLen(1))
+			Expect(listConfigMaps(r.Client, ctx, labeled.Name)).To(BeEmpty())
+		})
+
+		It("should not apply the default class to excluded namespaces", func() {
+			excluded := newNamespace("kube-system", "")
+			class := newNamespaceClass("baseline", mustRawConfigMap("baseline-config", map[string]string{"foo": "bar"}))
+
+			r, _, ctx := setupTestReconciler(excluded, class)
+			r.DefaultClass = "baseline"
+			r.ExcludedNamespaces = []string{"kube-system"}
+
+			_, err := r.Reconcile(ctx, requestFor(excluded))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(listConfigMaps(r.Client, ctx, excluded.Name)).To(BeEmpty())
+		})
+
+		It("should enqueue the default class for unlabeled namespaces", func() {
+			r, _, ctx := setupTestReconciler()
+			r.DefaultClass = "baseline"
+			r.ExcludedNamespaces = []string{"kube-system"}
+
+			Expect(controller.MapNamespaceToNamespaceClass(r, ctx, newNamespace("unlabeled-ns", ""))).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "baseline"}},
+			))
+			Expect(controller.MapNamespaceToNamespaceClass(r, ctx, newNamespace("labeled-ns", "other"))).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "other"}},
+			))
+			Expect(controller.MapNamespaceToNamespaceClass(r, ctx, newNamespace("kube-system", ""))).To(BeEmpty())
+		})
+	})
+
+	Describe("Resync", func() {
+		It("should requeue the class after the resync period", func() {
+			ns := newNamespace("resync-ns", "resync-class")
+			class := newNamespaceClass("resync-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+			r, _, ctx := setupTestReconciler(ns, class)
+			r.ResyncPeriod = 5 * time.Minute
+
+			result, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+
+			By("requeueing again once the class is in sync")
+			result, err = r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+		})
+
+		It("should not requeue when the resync period is zero", func() {
+			ns := newNamespace("no-resync-ns", "no-resync-class")
+			class := newNamespaceClass("no-resync-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+			r, _, ctx := setupTestReconciler(ns, class)
+
+			result, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+		})
+
+		It("should correct an out-of-band edit on resync", func() {
+			ns := newNamespace("resync-drift-ns", "resync-drift-class")
+			class := newNamespaceClass("resync-drift-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+			r, _, ctx := setupTestReconciler(ns, class)
+			r.ResyncPeriod = time.Minute
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			cms := listConfigMaps(r.Client, ctx, ns.Name)
+			Expect(cms).To(HaveLen(1))
+			cms[0].Data["foo"] = "edited"
+			Expect(r.Update(ctx, &cms[0])).To(Succeed())
+
+			_, err = r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)[0].Data).To(HaveKeyWithValue("foo", "bar"))
+		})
+	})
+
+	Describe("Events", func() {
+		It("should emit a Normal event listing the injected resources", func() {
+			ns := newNamespace("events-ns", "events-class")
+			class := newNamespaceClass("events-class",
+				mustRawConfigMap("first", map[string]string{"foo": "bar"}),
+				mustRawConfigMap("second", map[string]string{"foo": "baz"}),
+			)
+			r, _, ctx := setupTestReconciler(ns, class)
+
+			_, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal(
+				"Normal ResourcesInjected Injected 2 resource(s) from NamespaceClass 'events-class': ConfigMap/first, ConfigMap/second",
+			)))
+		})
+
+		It("should emit a Normal event listing the updated resources", func() {
+			ns := newNamespace("events-update-ns", "events-update-class")
+			existing := newInjectedConfigMap("first", ns.Name, map[string]string{"foo": "old"})
+			class := newNamespaceClass("events-update-class", mustRawConfigMap("first", map[string]string{"foo": "new"}))
+			r, _, ctx := setupTestReconciler(ns, class, existing)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal(
+				"Normal ResourcesUpdated Applied 1 resource(s) from NamespaceClass 'events-update-class': ConfigMap/first",
+			)))
+		})
+
+		It("should not emit an event when nothing was applied", func() {
+			ns := newNamespace("events-noop-ns", "events-noop-class")
+			class := newNamespaceClass("events-noop-class", mustRawConfigMap("first", map[string]string{"foo": "bar"}))
+			r, _, ctx := setupTestReconciler(ns, class)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive())
+
+			_, err = r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Recorder.(*record.FakeRecorder).Events).NotTo(Receive())
+		})
+
+		It("should emit Normal events for deleted obsolete resources", func() {
+			ns := newNamespace("events-obsolete-ns", "events-obsolete-class")
+			ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+			kept := mustRawConfigMap("kept", map[string]string{"foo": "bar"})
+			class := newNamespaceClass("events-obsolete-class", kept)
+			class.Status.LastAppliedResources = []runtime.RawExtension{
+				kept,
+				mustRawConfigMap("first-gone", map[string]string{"foo": "bar"}),
+				mustRawConfigMap("second-gone", map[string]string{"foo": "bar"}),
+			}
+			firstGone := newInjectedConfigMap("first-gone", ns.Name, map[string]string{"foo": "bar"})
+			firstGone.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class.Name}
+			secondGone := newInjectedConfigMap("second-gone", ns.Name, map[string]string{"foo": "bar"})
+			secondGone.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class.Name}
+			r, _, ctx := setupTestReconciler(ns, class, firstGone, secondGone)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+
+			var events []string
+			for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+				events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+			}
+			Expect(events).To(ContainElements(
+				HavePrefix("Normal ObsoleteResourceDeleted Deleted ConfigMap 'first-gone'"),
+				HavePrefix("Normal ObsoleteResourceDeleted Deleted ConfigMap 'second-gone'"),
+				"Normal ObsoleteResourcesDeleted Deleted 2 resource(s) no longer defined by NamespaceClass "+
+					"'events-obsolete-class': ConfigMap/first-gone, ConfigMap/second-gone",
+			))
+		})
+	})
+
+	Describe("ApplyPolicy", func() {
+		createOnly := map[string]string{controller.NamespaceClassApplyPolicyKey: controller.ApplyPolicyCreateOnly}
+
+		It("should not update an existing create-only resource", func() {
+			ns := newNamespace("seed-ns", "seed-class")
+			existing := newInjectedConfigMap("seed", ns.Name, map[string]string{"foo": "changed-by-app"})
+			class := newNamespaceClass("seed-class", mustRawAnnotatedConfigMap("seed", createOnly, map[string]string{"foo": "seed"}))
+			r, _, ctx := setupTestReconciler(ns, class, existing)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			cms := listConfigMaps(r.Client, ctx, ns.Name)
+			Expect(cms).To(HaveLen(1))
+			Expect(cms[0].Data).To(HaveKeyWithValue("foo", "changed-by-app"))
+		})
+
+		It("should create a missing create-only resource", func() {
+			ns := newNamespace("seed-missing-ns", "seed-missing-class")
+			class := newNamespaceClass("seed-missing-class", mustRawAnnotatedConfigMap("seed", createOnly, map[string]string{"foo": "seed"}))
+			r, _, ctx := setupTestReconciler(ns, class)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			cms := listConfigMaps(r.Client, ctx, ns.Name)
+			Expect(cms).To(HaveLen(1))
+			Expect(cms[0].Data).To(HaveKeyWithValue("foo", "seed"))
+		})
+
+		It("should update an existing resource by default", func() {
+			ns := newNamespace("sync-ns", "sync-class")
+			existing := newInjectedConfigMap("seed", ns.Name, map[string]string{"foo": "changed-by-app"})
+			class := newNamespaceClass("sync-class", mustRawAnnotatedConfigMap("seed", nil, map[string]string{"foo": "seed"}))
+			r, _, ctx := setupTestReconciler(ns, class, existing)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			cms := listConfigMaps(r.Client, ctx, ns.Name)
+			Expect(cms).To(HaveLen(1))
+			Expect(cms[0].Data).To(HaveKeyWithValue("foo", "seed"))
+		})
+	})
+
+	Describe("Status", func() {
+		It("should list the namespaces bound to the class", func() {
+			nsB := newNamespace("bound-b", "bound-class")
+			nsA := newNamespace("bound-a", "bound-class")
+			unrelated := newNamespace("bound-other", "other-class")
+			class := newNamespaceClass("bound-class")
+			r, _, ctx := setupTestReconciler(nsB, nsA, unrelated, class)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			var persisted v1alpha1.NamespaceClass
+			Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+			Expect(persisted.Status.BoundNamespaces).To(Equal([]string{"bound-a", "bound-b"}))
+		})
+
+		It("should not list namespaces that are terminating", func() {
+			ns := newNamespace("bound-live", "bound-term-class")
+			terminating := newNamespace("bound-terminating", "bound-term-class")
+			terminating.Status.Phase = corev1.NamespaceTerminating
+			class := newNamespaceClass("bound-term-class")
+			r, _, ctx := setupTestReconciler(ns, terminating, class)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			var persisted v1alpha1.NamespaceClass
+			Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+			Expect(persisted.Status.BoundNamespaces).To(Equal([]string{"bound-live"}))
+		})
+	})
+
+	Describe("DeleteMarker", func() {
+		It("should delete a marked resource from a new namespace", func() {
+			ns := newNamespace("marker-ns", "marker-class")
+			stale := newInjectedConfigMap("stale", ns.Name, map[string]string{"foo": "old"})
+			class := newNamespaceClass("marker-class",
+				mustRawConfigMap("keep", map[string]string{"foo": "bar"}),
+				mustRawDeletionMarker("stale"),
+			)
+			r, _, ctx := setupTestReconciler(ns, class, stale)
+
+			_, err := r.Reconcile(ctx, requestFor(ns))
+			Expect(err).NotTo(HaveOccurred())
+
+			cms := listConfigMaps(r.Client, ctx, ns.Name)
+			Expect(cms).To(HaveLen(1))
+			Expect(cms[0].Name).To(Equal("keep"))
+		})
+
+		It("should delete a marked resource when the class is reconciled", func() {
+			ns := newNamespace("marker-update-ns", "marker-update-class")
+			stale := newInjectedConfigMap("stale", ns.Name, map[string]string{"foo": "old"})
+			class := newNamespaceClass("marker-update-class", mustRawDeletionMarker("stale"))
+			r, _, ctx := setupTestReconciler(ns, class, stale)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+		})
+
+		It("should not fail when the marked resource is already gone", func() {
+			ns := newNamespace("marker-absent-ns", "marker-absent-class")
+			class := newNamespaceClass("marker-absent-class", mustRawDeletionMarker("stale"))
+			r, _, ctx := setupTestReconciler(ns, class)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(namespaceAnnotations(r.Client, ctx, ns.Name)).To(HaveKey(controller.NamespaceClassAppliedHashKey))
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+		})
+	})
+
 	Describe("Finalizers", func() {
 		It("should add a finalizer to NamespaceClass if missing", func() {
 			class := newNamespaceClass("needs-finalizer", mustRawConfigMap("some", map[string]string{"x": "y"}))
@@ -323,6 +931,28 @@ func mustRawConfigMap(name string, data map[string]string) runtime.RawExtension
 	return runtime.RawExtension{Raw: raw}
 }
 
+func mustRawDeletionMarker(name string) runtime.RawExtension {
+	return mustRawAnnotatedConfigMap(name, map[string]string{controller.NamespaceClassActionKey: controller.ActionDelete}, nil)
+}
+
+func mustRawAnnotatedConfigMap(name string, annotations, data map[string]string) runtime.RawExtension {
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: annotations,
+		},
+		Data: data,
+	}
+
+	raw, err := json.Marshal(cm)
+	Expect(err).NotTo(HaveOccurred())
+	return runtime.RawExtension{Raw: raw}
+}
+
 func newNamespace(name, classLabel string) *corev1.Namespace {
 	labels := map[string]string{}
 	if classLabel != "" {
@@ -333,6 +963,7 @@ func newNamespace(name, classLabel string) *corev1.Namespace {
 			Name:   name,
 			Labels: labels,
 		},
+		Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
 	}
 }
 
@@ -371,7 +1002,12 @@ func newNamespaceClass(name string, resources ...runtime.RawExtension) *v1alpha1
 	}
 }
 
+// requestFor returns the request that reconciles obj: a Namespace through the
+// namespace path, anything else by its name.
 func requestFor(obj client.Object) reconcile.Request {
+	if _, ok := obj.(*corev1.Namespace); ok {
+		return controller.NamespaceKey(obj.GetName())
+	}
 	return reconcile.Request{
 		NamespacedName: types.NamespacedName{
 			Name:      obj.GetName(),
@@ -388,14 +1024,25 @@ func setCleanupAnnotation(ns *corev1.Namespace) {
 }
 
 func setupTestReconciler(objs ...client.Object) (*controller.NamespaceClassReconciler, runtime.Scheme, context.Context) {
+	return setupTestReconcilerWithInterceptor(interceptor.Funcs{}, objs...)
+}
+
+func setupTestReconcilerWithInterceptor(
+	funcs interceptor.Funcs,
+	objs ...client.Object,
+) (*controller.NamespaceClassReconciler, runtime.Scheme, context.Context) {
 	scheme := runtime.NewScheme()
 	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	Expect(rbacv1.AddToScheme(scheme)).To(Succeed())
 	Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
 
 	client := fake.NewClientBuilder().
 		WithScheme(scheme).
+		WithRESTMapper(newRESTMapper(scheme)).
 		WithObjects(objs...).
+		WithIndex(&corev1.Namespace{}, controller.NamespaceClassIndexKey, controller.NamespaceClassIndex).
 		WithStatusSubresource(&v1alpha1.NamespaceClass{}).
+		WithInterceptorFuncs(funcs).
 		Build()
 
 	r := &controller.NamespaceClassReconciler{
@@ -410,3 +1057,18 @@ func setupTestReconciler(objs ...client.Object) (*controller.NamespaceClassRecon
 
 	return r, *scheme, ctx
 }
+
+// newRESTMapper maps every kind known to the scheme, treating Namespaces,
+// NamespaceClasses and ClusterRoles as cluster-scoped and everything else as
+// namespaced.
+func newRESTMapper(scheme *runtime.Scheme) *meta.DefaultRESTMapper {
+	mapper := meta.NewDefaultRESTMapper(scheme.PreferredVersionAllGroups())
+	for gvk := range scheme.AllKnownTypes() {
+		scope := meta.RESTScopeNamespace
+		if gvk.Kind == "Namespace" || gvk.Kind == "NamespaceClass" || gvk.Kind == "ClusterRole" {
+			scope = meta.RESTScopeRoot
+		}
+		mapper.Add(gvk, scope)
+	}
+	return mapper
+}
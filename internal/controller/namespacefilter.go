@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"path"
+)
+
+// DefaultNamespaceDenylist are the system namespaces the operator leaves alone
+// unless told otherwise.
+var DefaultNamespaceDenylist = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// managesNamespace reports whether the operator manages the namespace name: it
+// must match a pattern of NamespaceAllowlist, if any, and no pattern of
+// NamespaceDenylist. Namespaces that are not managed are neither applied to nor
+// cleaned up.
+func (r *NamespaceClassReconciler) managesNamespace(name string) bool {
+	if matchesAny(r.NamespaceDenylist, name) {
+		return false
+	}
+	return len(r.NamespaceAllowlist) == 0 || matchesAny(r.NamespaceAllowlist, name)
+}
+
+// matchesAny reports whether name matches one of the glob patterns.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateNamespacePatterns returns an error for the first malformed glob
+// pattern, see path.Match.
+func ValidateNamespacePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
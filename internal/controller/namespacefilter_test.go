@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Namespace allowlist and denylist", func() {
+	It("should only apply a class to allowed namespaces that are not denied", func() {
+		class := newNamespaceClass("filter-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		allowed := newNamespace("team-a", class.Name)
+		denied := newNamespace("team-sandbox", class.Name)
+		other := newNamespace("other", class.Name)
+		r, _, ctx := setupTestReconciler(class, allowed, denied, other)
+		r.NamespaceAllowlist = []string{"team-*"}
+		r.NamespaceDenylist = []string{"*-sandbox"}
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, allowed.Name)).To(HaveLen(1))
+		Expect(listConfigMaps(r.Client, ctx, denied.Name)).To(BeEmpty())
+		Expect(listConfigMaps(r.Client, ctx, other.Name)).To(BeEmpty())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.BoundNamespaces).To(Equal([]string{allowed.Name}))
+	})
+
+	It("should skip a denied namespace on the namespace path", func() {
+		class := newNamespaceClass("system-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		ns := newNamespace("kube-system", class.Name)
+		r, _, ctx := setupTestReconciler(class, ns)
+		r.NamespaceDenylist = controller.DefaultNamespaceDenylist
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+		_, err = r.Reconcile(ctx, controller.NamespaceRequest(class.Name, ns.Name))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+	})
+
+	It("should apply a class to a namespace that matches the allowlist on the namespace path", func() {
+		class := newNamespaceClass("pilot-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		ns := newNamespace("pilot-1", class.Name)
+		r, _, ctx := setupTestReconciler(class, ns)
+		r.NamespaceAllowlist = []string{"pilot-?"}
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+	})
+
+	It("should reject malformed patterns", func() {
+		Expect(controller.ValidateNamespacePatterns([]string{"team-*", "kube-system"})).To(Succeed())
+		Expect(controller.ValidateNamespacePatterns([]string{"team-["})).To(MatchError(ContainSubstring(`"team-["`)))
+	})
+})
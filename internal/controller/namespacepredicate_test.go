@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Namespace predicate", func() {
+	var old *corev1.Namespace
+
+	changed := func(mutate func(ns *corev1.Namespace)) bool {
+		updated := old.DeepCopy()
+		updated.ResourceVersion = "2"
+		mutate(updated)
+		return controller.NamespaceChanged.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated})
+	}
+
+	BeforeEach(func() {
+		old = newNamespace("predicate-ns", "predicate-class")
+		old.ResourceVersion = "1"
+		old.Annotations = map[string]string{"team": "a"}
+	})
+
+	It("should ignore updates that only bump the resource version", func() {
+		Expect(changed(func(*corev1.Namespace) {})).To(BeFalse())
+	})
+
+	It("should ignore unrelated annotation updates", func() {
+		Expect(changed(func(ns *corev1.Namespace) { ns.Annotations["team"] = "b" })).To(BeFalse())
+		Expect(changed(func(ns *corev1.Namespace) {
+			ns.Annotations[controller.NamespaceClassAppliedHashKey] = "abc"
+		})).To(BeFalse())
+	})
+
+	It("should react to a changed class label", func() {
+		Expect(changed(func(ns *corev1.Namespace) {
+			ns.Labels[controller.NamespaceClassNameKey] = "other-class"
+		})).To(BeTrue())
+		Expect(changed(func(ns *corev1.Namespace) {
+			delete(ns.Labels, controller.NamespaceClassNameKey)
+		})).To(BeTrue())
+	})
+
+	It("should react to other labels, which selectors and templates may use", func() {
+		Expect(changed(func(ns *corev1.Namespace) { ns.Labels["tier"] = "frontend" })).To(BeTrue())
+	})
+
+	It("should react to the cleanup annotations", func() {
+		Expect(changed(func(ns *corev1.Namespace) {
+			ns.Annotations[controller.NamespaceClassCleanupKey] = "true"
+		})).To(BeTrue())
+		Expect(changed(func(ns *corev1.Namespace) {
+			ns.Annotations[controller.NamespaceClassCleanupObsoleteKey] = "true"
+		})).To(BeTrue())
+	})
+
+	It("should react to deletion and termination", func() {
+		Expect(changed(func(ns *corev1.Namespace) {
+			now := metav1.Now()
+			ns.DeletionTimestamp = &now
+		})).To(BeTrue())
+		Expect(changed(func(ns *corev1.Namespace) {
+			ns.Finalizers = []string{controller.NamespaceClassFinalizerKey}
+		})).To(BeTrue())
+		Expect(changed(func(ns *corev1.Namespace) {
+			ns.Status.Phase = corev1.NamespaceTerminating
+		})).To(BeTrue())
+	})
+
+	It("should let creations and deletions through", func() {
+		Expect(controller.NamespaceChanged.Create(event.CreateEvent{Object: old})).To(BeTrue())
+		Expect(controller.NamespaceChanged.Delete(event.DeleteEvent{Object: old})).To(BeTrue())
+	})
+})
@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// namespaceRequest returns the request that applies a class to a single
+// namespace. NamespaceClasses are cluster-scoped, so the namespace of the
+// request is free to name the namespace the class is applied to.
+func namespaceRequest(className, namespace string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: className}}
+}
+
+// namespaceKeyPrefix starts the name of requests for a Namespace rather than a
+// NamespaceClass. Object names cannot contain a slash, so no class is ever
+// mistaken for a Namespace of the same name or the other way around.
+const namespaceKeyPrefix = "namespace/"
+
+// namespaceKey returns the request that reconciles the Namespace named name on
+// its own, e.g. to release it from its class.
+func namespaceKey(name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Name: namespaceKeyPrefix + name}}
+}
+
+// namespaceFromKey returns the name of the Namespace that req was created for
+// by namespaceKey. It returns false for requests for classes.
+func namespaceFromKey(req reconcile.Request) (string, bool) {
+	if req.Namespace != "" {
+		return "", false
+	}
+	return strings.CutPrefix(req.Name, namespaceKeyPrefix)
+}
+
+// requestSource feeds the requests passed to enqueue into the work queue.
+func (r *NamespaceClassReconciler) requestSource() source.Source {
+	r.requests = make(chan event.TypedGenericEvent[reconcile.Request], 1024)
+	return source.Channel(r.requests, handler.TypedEnqueueRequestsFromMapFunc(
+		func(_ context.Context, req reconcile.Request) []reconcile.Request { return []reconcile.Request{req} },
+	))
+}
+
+// enqueue adds req to the work queue. It does nothing outside a manager.
+func (r *NamespaceClassReconciler) enqueue(ctx context.Context, req reconcile.Request) {
+	if r.requests == nil {
+		return
+	}
+	select {
+	case r.requests <- event.TypedGenericEvent[reconcile.Request]{Object: req}:
+	case <-ctx.Done():
+	}
+}
+
+// applyToNamespace applies class to ns like reconcileNamespaceForClass, but
+// turns a panic into an error, so that one namespace cannot stop a class from
+// being applied to the others, and finishes within DrainTimeout when ctx is
+// cancelled.
+func (r *NamespaceClassReconciler) applyToNamespace(
+	ctx context.Context,
+	log logr.Logger,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+	lastApplied []runtime.RawExtension,
+	clusterApplied map[string]bool,
+) (changes []string, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Error(fmt.Errorf("%v", p), "Panic while applying NamespaceClass")
+			err = fmt.Errorf("panic while applying: %v", p)
+		}
+	}()
+	ctx, cancel := r.drainContext(ctx)
+	defer cancel()
+	return r.reconcileNamespaceForClass(ctx, log, ns, class, lastApplied, clusterApplied)
+}
+
+// reconcileNamespaceRequest applies the class named by req to the namespace
+// named by req only. Nothing is done if the namespace left the class in the
+// meantime. A failure is returned, so that the request is retried with
+// exponential backoff, until every failing resource ran out of retries.
+//
+// A request of a rollout records its outcome there, and the one that finishes
+// the rollout enqueues the class, which reports it in the status. A retry that
+// succeeds enqueues the class too, which applies it to every namespace again
+// and brings its status up to date.
+func (r *NamespaceClassReconciler) reconcileNamespaceRequest(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := classLogger(ctx, req.Name, req.Namespace)
+	classReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: req.Name}}
+
+	ro := r.rolloutFor(req.Name, req.Namespace)
+	var outcome *namespaceResult
+	clusterApplied := map[string]bool{}
+	if ro != nil {
+		defer func() {
+			if ro.finish(req.Namespace, outcome, clusterApplied) {
+				r.enqueue(ctx, classReq)
+			}
+		}()
+	}
+
+	class := &v1alpha1.NamespaceClass{}
+	if err := r.Get(ctx, types.NamespacedName{Name: req.Name}, class); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: req.Namespace}, ns); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if class.DeletionTimestamp != nil || ns.DeletionTimestamp != nil || isPaused(class) || !r.managesNamespace(ns.Name) {
+		return ctrl.Result{}, nil
+	}
+	if className, ok := r.classNameFor(ctx, ns); !ok || className != class.Name {
+		log.Info("Namespace left the NamespaceClass; skipping it")
+		return ctrl.Result{}, nil
+	}
+
+	if ro == nil {
+		log.Info("Retrying NamespaceClass for namespace")
+	} else if ro.forced {
+		ctx = withForceApply(ctx)
+	}
+	class, expandErr := r.effectiveClass(ctx, class)
+	if expandErr != nil {
+		log.Error(expandErr, "Not every resource of the class could be read")
+		ctx = withIncompleteSpec(ctx)
+	}
+	if r.tooManyResources(class) {
+		log.Info("NamespaceClass has too many resources; skipping namespace")
+		return ctrl.Result{}, nil
+	}
+	var lastApplied []runtime.RawExtension
+	if expandErr == nil {
+		lastApplied = class.Status.LastAppliedResources
+	}
+	changes, err := r.applyToNamespace(ctx, log, ns, class, lastApplied, clusterApplied)
+	outcome = &namespaceResult{changes: changes, err: err}
+	if ro == nil {
+		// A rollout leaves the unreadable resources to the class
+		err = errors.Join(err, expandErr)
+	}
+	again, statusErr := r.recordOutcome(ctx, class.Name, ns.Name, err)
+	if statusErr != nil {
+		log.Error(statusErr, "Failed to update NamespaceClass status")
+		return ctrl.Result{}, statusErr
+	}
+	if err != nil && again && !(ro != nil && errors.Is(err, errUnresolvableKind)) {
+		// Unresolvable kinds are waited for by requeueing the class
+		return ctrl.Result{}, err
+	}
+
+	if ro == nil {
+		r.enqueue(ctx, classReq)
+	}
+	return ctrl.Result{}, nil
+}
+
+// recordOutcome counts the attempt to apply the class named className to ns,
+// which ended with err, and records the field conflicts it ran into, in the
+// status of the class. It returns whether err is worth retrying, see
+// recordAttempt. The class is read again on conflicts, since the requests of
+// the other namespaces of the class write its status too.
+func (r *NamespaceClassReconciler) recordOutcome(ctx context.Context, className, ns string, err error) (bool, error) {
+	var again bool
+	updateErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		class := &v1alpha1.NamespaceClass{}
+		if err := r.Get(ctx, types.NamespacedName{Name: className}, class); err != nil {
+			return err
+		}
+		before := class.Status.DeepCopy()
+		again = r.recordAttempt(class, ns, err)
+		setFieldConflicts(class, ns, namespaceConflicts(ns, err))
+		if equality.Semantic.DeepEqual(before, &class.Status) {
+			return nil
+		}
+		return r.Status().Update(ctx, class)
+	})
+	return again, updateErr
+}
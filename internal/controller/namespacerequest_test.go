@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Per-namespace requests", func() {
+	var failing bool
+
+	// failIn fails, or with panics set panics on, every create in namespace
+	// while failing is set.
+	failIn := func(namespace string, panics bool) interceptor.Funcs {
+		return interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if failing && obj.GetNamespace() == namespace {
+					if panics {
+						panic("boom")
+					}
+					return errors.New("boom")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		failing = true
+	})
+
+	It("should apply the class to the other namespaces when one fails", func() {
+		class := newNamespaceClass("split-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		good1 := newNamespace("split-a", class.Name)
+		bad := newNamespace("split-b", class.Name)
+		good2 := newNamespace("split-c", class.Name)
+		r, _, ctx := setupTestReconcilerWithInterceptor(failIn(bad.Name, false), class, good1, bad, good2)
+		requests := r.WithRequestQueue(10)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("enqueueing a request per namespace instead of applying the class")
+		Expect(requests).To(HaveLen(3))
+		Expect(listConfigMaps(r.Client, ctx, good1.Name)).To(BeEmpty())
+
+		failed := reconcileQueued(ctx, r, requests)
+		Expect(listConfigMaps(r.Client, ctx, good1.Name)).To(HaveLen(1))
+		Expect(listConfigMaps(r.Client, ctx, good2.Name)).To(HaveLen(1))
+		Expect(listConfigMaps(r.Client, ctx, bad.Name)).To(BeEmpty())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.Namespaces).To(HaveLen(3))
+		Expect(persisted.Status.Namespaces[0].Ready).To(BeTrue())
+		Expect(persisted.Status.Namespaces[1].Ready).To(BeFalse())
+		Expect(persisted.Status.Namespaces[2].Ready).To(BeTrue())
+		Expect(persisted.Status.Phase).To(Equal(v1alpha1.ClassPhaseError))
+
+		By("retrying only the failed namespace")
+		Expect(failed).To(HaveLen(1))
+		Expect(failed).To(HaveKey(controller.NamespaceRequest(class.Name, bad.Name)))
+	})
+
+	It("should apply the class again once a rollout that was reconciled meanwhile is done", func() {
+		class := newNamespaceClass("stale-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		ns := newNamespace("stale-ns", class.Name)
+		r, _, ctx := setupTestReconciler(class, ns)
+		requests := r.WithRequestQueue(10)
+		req := controller.NamespaceRequest(class.Name, ns.Name)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+		Expect((<-requests).Object).To(Equal(req))
+
+		By("not starting another rollout while the namespace is pending")
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(BeEmpty())
+
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+		Expect((<-requests).Object).To(Equal(requestFor(class)))
+
+		By("reporting the rollout and starting the next one")
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.Phase).To(Equal(v1alpha1.ClassPhaseReady))
+		Expect(requests).To(HaveLen(1))
+		Expect((<-requests).Object).To(Equal(requestFor(class)))
+	})
+
+	It("should apply the class to the other namespaces when one panics", func() {
+		class := newNamespaceClass("panic-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		bad := newNamespace("panic-a", class.Name)
+		good := newNamespace("panic-b", class.Name)
+		r, _, ctx := setupTestReconcilerWithInterceptor(failIn(bad.Name, true), class, bad, good)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, good.Name)).To(HaveLen(1))
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.Namespaces[0].Ready).To(BeFalse())
+		Expect(persisted.Status.Namespaces[0].Message).To(ContainSubstring("panic while applying: boom"))
+	})
+
+	It("should retry a single namespace until it succeeds and then refresh the class", func() {
+		class := newNamespaceClass("retry-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		bad := newNamespace("retry-a", class.Name)
+		other := newNamespace("retry-b", class.Name)
+		r, _, ctx := setupTestReconcilerWithInterceptor(failIn(bad.Name, false), class, bad, other)
+		requests := r.WithRequestQueue(10)
+		req := controller.NamespaceRequest(class.Name, bad.Name)
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).To(MatchError(ContainSubstring("boom")))
+		Expect(requests).To(BeEmpty())
+
+		failing = false
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, bad.Name)).To(HaveLen(1))
+		Expect(listConfigMaps(r.Client, ctx, other.Name)).To(BeEmpty())
+		Expect(requests).To(HaveLen(1))
+		Expect((<-requests).Object).To(Equal(requestFor(class)))
+	})
+
+	It("should skip a namespace that left the class", func() {
+		class := newNamespaceClass("left-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		ns := newNamespace("left-ns", "another-class")
+		r, _, ctx := setupTestReconciler(class, ns)
+
+		result, err := r.Reconcile(ctx, controller.NamespaceRequest(class.Name, ns.Name))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+	})
+
+	It("should not mistake a class for a namespace of the same name", func() {
+		sharedNs := newNamespace("shared", "team")
+		other := newNamespace("other", "shared")
+		team := newNamespaceClass("team", mustRawConfigMap("team-cfg", map[string]string{"foo": "bar"}))
+		sharedClass := newNamespaceClass("shared", mustRawConfigMap("shared-cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(sharedNs, other, team, sharedClass)
+
+		_, err := r.Reconcile(ctx, requestFor(sharedClass))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, other.Name)).To(HaveLen(1))
+		Expect(listConfigMaps(r.Client, ctx, sharedNs.Name)).To(BeEmpty())
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: sharedClass.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.BoundNamespaces).To(Equal([]string{other.Name}))
+
+		_, err = r.Reconcile(ctx, requestFor(sharedNs))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, sharedNs.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Name).To(Equal("team-cfg"))
+	})
+})
+
+// reconcileQueued reconciles the requests that r enqueued to requests, and the
+// ones these enqueue in turn, like the work queue of a manager. It returns the
+// errors of the requests that failed, which a manager would retry.
+func reconcileQueued(
+	ctx context.Context,
+	r *controller.NamespaceClassReconciler,
+	requests <-chan event.TypedGenericEvent[reconcile.Request],
+) map[reconcile.Request]error {
+	failed := map[reconcile.Request]error{}
+	for len(requests) > 0 {
+		req := (<-requests).Object
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			failed[req] = err
+		}
+	}
+	return failed
+}
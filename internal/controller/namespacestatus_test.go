@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+var _ = Describe("Namespace status", func() {
+	It("should report the error of a namespace that fails to apply", func() {
+		healthy := newNamespace("healthy-ns", "status-class")
+		full := newNamespace("full-ns", "status-class")
+		class := newNamespaceClass("status-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconcilerWithInterceptor(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if obj.GetNamespace() == full.Name {
+					return errors.New("exceeded quota: configmaps")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}, healthy, full, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.Namespaces).To(HaveLen(2))
+
+		failed, ok := namespaceStatusOf(persisted, full.Name)
+		Expect(ok).To(BeTrue())
+		Expect(failed.Ready).To(BeFalse())
+		Expect(failed.Message).To(ContainSubstring("exceeded quota: configmaps"))
+		Expect(failed.LastApplied).To(BeNil())
+
+		ready, ok := namespaceStatusOf(persisted, healthy.Name)
+		Expect(ok).To(BeTrue())
+		Expect(ready.Ready).To(BeTrue())
+		Expect(ready.Message).To(BeEmpty())
+		Expect(ready.LastApplied).NotTo(BeNil())
+	})
+
+	It("should prune namespaces that no longer exist", func() {
+		ns := newNamespace("remaining-ns", "prune-class")
+		class := newNamespaceClass("prune-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		class.Status.Namespaces = []v1alpha1.NamespaceStatus{
+			{Name: "deleted-ns", Ready: true, LastApplied: &metav1.Time{}},
+		}
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.Namespaces).To(ConsistOf(HaveField("Name", "remaining-ns")))
+	})
+})
+
+func namespaceStatusOf(class v1alpha1.NamespaceClass, name string) (v1alpha1.NamespaceStatus, bool) {
+	for _, status := range class.Status.Namespaces {
+		if status.Name == name {
+			return status, true
+		}
+	}
+	return v1alpha1.NamespaceStatus{}, false
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// ObsoleteCleanup controls how the resources a class no longer defines are
+// found in namespaces that opted into NamespaceClassCleanupObsoleteKey.
+type ObsoleteCleanup string
+
+const (
+	// ObsoleteCleanupStatus deletes the resources of the lastAppliedResources
+	// status of the class that its spec no longer defines.
+	ObsoleteCleanupStatus ObsoleteCleanup = "status"
+	// ObsoleteCleanupLabels lists the objects in the namespace that carry the
+	// ownership label of the class and deletes those it no longer defines. It
+	// does not depend on the status, which may be stale after the operator
+	// stopped during an apply.
+	ObsoleteCleanupLabels ObsoleteCleanup = "labels"
+)
+
+// obsoleteResources returns the resources of class in ns that are to be
+// deleted as obsolete. removed are the ones derived from the status and desired
+// the objects the class currently applies to ns. Those among desired are kept,
+// e.g. when an additional class of ns still defines what class dropped. complete tells whether desired
+// holds every resource the class defines; when it does not, no objects are
+// looked up by label, since those missing from desired may still be defined.
+// Objects that do not carry the ownership label of class are never returned.
+func (r *NamespaceClassReconciler) obsoleteResources(
+	ctx context.Context,
+	log logr.Logger,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+	removed map[string]schema.GroupVersionKind,
+	desired []*unstructured.Unstructured,
+	complete bool,
+) []*unstructured.Unstructured {
+	if r.ObsoleteCleanup == ObsoleteCleanupLabels {
+		if !complete {
+			log.Info("Skipping obsolete cleanup since not every resource of the class could be built")
+			return nil
+		}
+		return r.obsoleteByLabel(ctx, log, ns, class, desired)
+	}
+
+	wanted := map[string]bool{}
+	for _, obj := range desired {
+		if obj.GetNamespace() == ns.Name {
+			wanted[objectKey(obj)] = true
+		}
+	}
+
+	var obsolete []*unstructured.Unstructured
+	for _, name := range slices.Sorted(maps.Keys(removed)) {
+		gvk := removed[name]
+		if !r.kindAllowed(gvk) {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetName(name)
+		obj.SetNamespace(ns.Name)
+		if wanted[objectKey(obj)] {
+			continue
+		}
+		if live := r.ownedObject(ctx, log, ns, gvk, client.ObjectKeyFromObject(obj), class.Name); live != nil {
+			obsolete = append(obsolete, live)
+		}
+	}
+	return obsolete
+}
+
+// removedResources returns, by their names as rendered for ns, the resources
+// of lastApplied that class no longer defines. Nothing is removed when a
+// resource of class fails to render, since its name is not known then.
+func (r *NamespaceClassReconciler) removedResources(
+	ctx context.Context,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+	lastApplied []runtime.RawExtension,
+) map[string]schema.GroupVersionKind {
+	if len(lastApplied) == 0 {
+		return nil
+	}
+	current, ok := r.renderedNames(ctx, ns, class, class.Spec.Resources)
+	if !ok {
+		return nil
+	}
+	previous, _ := r.renderedNames(ctx, ns, class, lastApplied)
+	return diffRemoved(previous, current)
+}
+
+// renderedNames is toNameGVKMap for resources of class rendered for ns.
+// Resources that fail to render are left out, and ok is false then.
+func (r *NamespaceClassReconciler) renderedNames(
+	ctx context.Context,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+	resources []runtime.RawExtension,
+) (names map[string]schema.GroupVersionKind, ok bool) {
+	values, err := r.templateValues(ctx, class)
+	if err != nil {
+		return nil, false
+	}
+	ok = true
+	rendered := make([]runtime.RawExtension, 0, len(resources))
+	for _, res := range resources {
+		raw, err := renderResource(res.Raw, ns, class.Name, values)
+		if err != nil {
+			ok = false
+			continue
+		}
+		rendered = append(rendered, runtime.RawExtension{Raw: raw})
+	}
+	return toNameGVKMap(rendered), ok
+}
+
+// obsoleteByLabel lists the objects in ns that are owned by class and not
+// among desired. Only the kinds that are watched or that the class defines now
+// or did according to its status are listed. Immutable resources, and
+// resources that another namespace of the class redirected into ns, are kept.
+func (r *NamespaceClassReconciler) obsoleteByLabel(
+	ctx context.Context,
+	log logr.Logger,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+	desired []*unstructured.Unstructured,
+) []*unstructured.Unstructured {
+	wanted := map[string]bool{}
+	for _, obj := range desired {
+		if obj.GetNamespace() == ns.Name {
+			wanted[objectKey(obj)] = true
+		}
+	}
+
+	var obsolete []*unstructured.Unstructured
+	for _, gvk := range r.ownedKinds(class) {
+		mapping, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil || mapping.Scope.Name() == meta.RESTScopeNameRoot {
+			continue
+		}
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.List(ctx, list, client.InNamespace(ns.Name),
+			client.MatchingLabels{NamespaceClassOwnedByKey: class.Name}); err != nil {
+			withResource(log, gvk, "").Error(err, "Failed to list owned resources")
+			continue
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if wanted[objectKey(obj)] || isImmutable(obj) ||
+				obj.GetAnnotations()[NamespaceClassTargetNamespaceKey] != "" {
+				continue
+			}
+			obsolete = append(obsolete, obj)
+		}
+	}
+	return obsolete
+}
+
+// ownedKinds returns the allowed kinds that objects owned by class may have,
+// sorted for a stable order.
+func (r *NamespaceClassReconciler) ownedKinds(class *v1alpha1.NamespaceClass) []schema.GroupVersionKind {
+	seen := map[schema.GroupVersionKind]bool{}
+	var kinds []schema.GroupVersionKind
+	add := func(gvk schema.GroupVersionKind) {
+		if seen[gvk] || !r.kindAllowed(gvk) {
+			return
+		}
+		seen[gvk] = true
+		kinds = append(kinds, gvk)
+	}
+	for _, gvk := range r.watchedKinds() {
+		add(gvk)
+	}
+	for _, resources := range [][]runtime.RawExtension{class.Spec.Resources, class.Status.LastAppliedResources} {
+		for _, gvk := range toNameGVKMap(resources) {
+			add(gvk)
+		}
+		for _, raw := range resources {
+			// toNameGVKMap leaves out resources named by generateName
+			obj := &unstructured.Unstructured{}
+			if err := obj.UnmarshalJSON(raw.Raw); err == nil && obj.GetName() == "" && obj.GetGenerateName() != "" {
+				add(obj.GroupVersionKind())
+			}
+		}
+	}
+	slices.SortFunc(kinds, func(a, b schema.GroupVersionKind) int { return strings.Compare(a.String(), b.String()) })
+	return kinds
+}
+
+// isProtected reports whether the obsolete obj, or the object in the cluster
+// it stands for when obj only carries its kind and name, has
+// NamespaceClassProtectKey set to "true". An object that cannot be read is not
+// protected; deleting it fails the same way.
+func (r *NamespaceClassReconciler) isProtected(ctx context.Context, obj *unstructured.Unstructured) bool {
+	if obj.GetAnnotations()[NamespaceClassProtectKey] == "true" {
+		return true
+	}
+	if obj.GetResourceVersion() != "" {
+		return false
+	}
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		return false
+	}
+	return live.GetAnnotations()[NamespaceClassProtectKey] == "true"
+}
+
+// reportRetained emits an ObsoleteResourceRetained Event on ns for every
+// resource that was removed from class but is kept because the namespace did
+// not opt into NamespaceClassCleanupObsoleteKey, so that users can tell why it
+// is still around. Resources that are gone, or that no longer belong to the
+// class, are not reported.
+func (r *NamespaceClassReconciler) reportRetained(
+	ctx context.Context,
+	log logr.Logger,
+	ns *corev1.Namespace,
+	class *v1alpha1.NamespaceClass,
+	removed map[string]schema.GroupVersionKind,
+) {
+	for _, name := range slices.Sorted(maps.Keys(removed)) {
+		gvk := removed[name]
+		if !r.kindAllowed(gvk) {
+			continue
+		}
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(gvk)
+		if err := r.Get(ctx, client.ObjectKey{Namespace: ns.Name, Name: name}, live); err != nil {
+			continue
+		}
+		if live.GetLabels()[NamespaceClassOwnedByKey] != class.Name {
+			continue
+		}
+		withObject(log, live).Info("Keeping resource that was removed from the class")
+		r.Recorder.Eventf(ns, corev1.EventTypeNormal, "ObsoleteResourceRetained",
+			"%s '%s' was removed from NamespaceClass '%s' but is kept because the namespace does not have %s=true",
+			gvk.Kind, name, class.Name, NamespaceClassCleanupObsoleteKey)
+	}
+}
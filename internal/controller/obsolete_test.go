@@ -0,0 +1,324 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Label-based obsolete cleanup", func() {
+	// newOwnedConfigMap returns a ConfigMap that an earlier reconcile of class
+	// injected, but that is missing from the stale status of the class.
+	newOwnedConfigMap := func(name, ns, class string) *corev1.ConfigMap {
+		cm := newInjectedConfigMap(name, ns, map[string]string{"foo": "bar"})
+		cm.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class}
+		return cm
+	}
+
+	cleanupNamespace := func(name, class string) *corev1.Namespace {
+		ns := newNamespace(name, class)
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		return ns
+	}
+
+	configMapNames := func(ctx context.Context, r *controller.NamespaceClassReconciler, ns string) []string {
+		var names []string
+		for _, cm := range listConfigMaps(r.Client, ctx, ns) {
+			names = append(names, cm.Name)
+		}
+		return names
+	}
+
+	It("should delete owned resources the class no longer defines although the status does not list them", func() {
+		ns := cleanupNamespace("labels-ns", "labels-class")
+		class := newNamespaceClass("labels-class", mustRawConfigMap("current", map[string]string{"foo": "bar"}))
+		stale := newOwnedConfigMap("stale", ns.Name, class.Name)
+		unowned := newInjectedConfigMap("handmade", ns.Name, map[string]string{"foo": "bar"})
+		foreign := newOwnedConfigMap("foreign", ns.Name, "other-class")
+		r, _, ctx := setupTestReconciler(ns, class, stale, unowned, foreign)
+		r.ObsoleteCleanup = controller.ObsoleteCleanupLabels
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(configMapNames(ctx, r, ns.Name)).To(ConsistOf("current", "handmade", "foreign"))
+	})
+
+	It("should not delete owned resources by label when a resource of the class fails to render", func() {
+		ns := cleanupNamespace("labels-broken-ns", "labels-broken-class")
+		class := newNamespaceClass("labels-broken-class",
+			mustRawConfigMap("current", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("broken", map[string]string{"team": "{{ .Namespace.Labels.tema }}"}),
+		)
+		broken := newOwnedConfigMap("broken", ns.Name, class.Name)
+		r, _, ctx := setupTestReconciler(ns, class, broken)
+		r.ObsoleteCleanup = controller.ObsoleteCleanupLabels
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(configMapNames(ctx, r, ns.Name)).To(ConsistOf("current", "broken"))
+	})
+
+	It("should keep stale owned resources with status-based cleanup", func() {
+		ns := cleanupNamespace("status-ns", "status-class")
+		class := newNamespaceClass("status-class", mustRawConfigMap("current", map[string]string{"foo": "bar"}))
+		stale := newOwnedConfigMap("stale", ns.Name, class.Name)
+		r, _, ctx := setupTestReconciler(ns, class, stale)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(configMapNames(ctx, r, ns.Name)).To(ConsistOf("current", "stale"))
+	})
+
+	It("should not delete owned resources of a namespace that did not opt into cleanup", func() {
+		ns := newNamespace("labels-keep-ns", "labels-keep-class")
+		class := newNamespaceClass("labels-keep-class", mustRawConfigMap("current", map[string]string{"foo": "bar"}))
+		stale := newOwnedConfigMap("stale", ns.Name, class.Name)
+		r, _, ctx := setupTestReconciler(ns, class, stale)
+		r.ObsoleteCleanup = controller.ObsoleteCleanupLabels
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(configMapNames(ctx, r, ns.Name)).To(ConsistOf("current", "stale"))
+	})
+
+	It("should not delete an owned immutable resource", func() {
+		ns := cleanupNamespace("labels-immutable-ns", "labels-immutable-class")
+		class := newNamespaceClass("labels-immutable-class", mustRawConfigMap("current", map[string]string{"foo": "bar"}))
+		token := newOwnedConfigMap("token", ns.Name, class.Name)
+		token.Annotations = map[string]string{controller.NamespaceClassImmutableKey: "true"}
+		r, _, ctx := setupTestReconciler(ns, class, token)
+		r.ObsoleteCleanup = controller.ObsoleteCleanupLabels
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(configMapNames(ctx, r, ns.Name)).To(ConsistOf("current", "token"))
+	})
+
+	It("should delete resources removed while the cleanup was off once it is enabled", func() {
+		ns := newNamespace("late-cleanup-ns", "late-cleanup-class")
+		class := newNamespaceClass("late-cleanup-class",
+			mustRawConfigMap("current", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("dropped", map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configMapNames(ctx, r, ns.Name)).To(ConsistOf("current", "dropped"))
+
+		By("removing a resource from the class while the cleanup is off")
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		persisted.Spec.Resources = []runtime.RawExtension{mustRawConfigMap("current", map[string]string{"foo": "bar"})}
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configMapNames(ctx, r, ns.Name)).To(ConsistOf("current", "dropped"))
+
+		By("enabling the cleanup of obsolete resources")
+		var live corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &live)).To(Succeed())
+		live.Annotations[controller.NamespaceClassCleanupObsoleteKey] = "true"
+		Expect(r.Update(ctx, &live)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configMapNames(ctx, r, ns.Name)).To(ConsistOf("current"))
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &live)).To(Succeed())
+		Expect(live.Annotations).To(HaveKeyWithValue(controller.NamespaceClassAppliedCleanupObsoleteKey, "true"))
+	})
+})
+
+var _ = Describe("Protected obsolete resources", func() {
+	dropResource := func(ctx context.Context, r *controller.NamespaceClassReconciler, className string, keep ...runtime.RawExtension) {
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: className}, &persisted)).To(Succeed())
+		persisted.Spec.Resources = keep
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+	}
+
+	It("should keep a resource that was annotated in the namespace", func() {
+		ns := newNamespace("protect-ns", "protect-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		class := newNamespaceClass("protect-class",
+			mustRawConfigMap("current", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("data", map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var data corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: "data"}, &data)).To(Succeed())
+		data.Annotations[controller.NamespaceClassProtectKey] = "true"
+		Expect(r.Update(ctx, &data)).To(Succeed())
+
+		dropResource(ctx, r, class.Name, mustRawConfigMap("current", map[string]string{"foo": "bar"}))
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: "data"}, &data)).To(Succeed())
+
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		Expect(events).To(ContainElement(ContainSubstring("ObsoleteResourceProtected")))
+	})
+
+	It("should keep a resource the class protects with label-based cleanup", func() {
+		ns := newNamespace("protect-labels-ns", "protect-labels-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		class := newNamespaceClass("protect-labels-class",
+			mustRawConfigMap("current", map[string]string{"foo": "bar"}),
+			mustRawAnnotatedConfigMap("data", map[string]string{controller.NamespaceClassProtectKey: "true"},
+				map[string]string{"foo": "bar"}),
+			mustRawConfigMap("scratch", map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+		r.ObsoleteCleanup = controller.ObsoleteCleanupLabels
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		dropResource(ctx, r, class.Name, mustRawConfigMap("current", map[string]string{"foo": "bar"}))
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var names []string
+		for _, cm := range listConfigMaps(r.Client, ctx, ns.Name) {
+			names = append(names, cm.Name)
+		}
+		Expect(names).To(ConsistOf("current", "data"))
+	})
+})
+
+var _ = Describe("Retained obsolete resources", func() {
+	It("should report a resource that is kept because the namespace did not opt into cleanup", func() {
+		ns := newNamespace("retain-ns", "retain-class")
+		class := newNamespaceClass("retain-class",
+			mustRawConfigMap("current", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("data", map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			<-r.Recorder.(*record.FakeRecorder).Events
+		}
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		persisted.Spec.Resources = persisted.Spec.Resources[:1]
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var data corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: "data"}, &data)).To(Succeed())
+
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		Expect(events).To(ContainElement(And(
+			ContainSubstring("Normal ObsoleteResourceRetained"),
+			ContainSubstring("ConfigMap 'data'"),
+		)))
+		Expect(events).NotTo(ContainElement(ContainSubstring("ConfigMap 'current'")))
+	})
+})
+
+var _ = Describe("Ownership of deleted resources", func() {
+	It("should only delete obsolete resources that the class owns", func() {
+		ns := newNamespace("owned-obsolete-ns", "owned-obsolete-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		class := newNamespaceClass("owned-obsolete-class", mustRawConfigMap("current", map[string]string{"foo": "bar"}))
+		class.Status.LastAppliedResources = []runtime.RawExtension{
+			mustRawConfigMap("current", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("gone", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("handmade", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("foreign", map[string]string{"foo": "bar"}),
+		}
+		gone := newInjectedConfigMap("gone", ns.Name, map[string]string{"foo": "bar"})
+		gone.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class.Name}
+		handmade := newInjectedConfigMap("handmade", ns.Name, map[string]string{"precious": "data"})
+		foreign := newInjectedConfigMap("foreign", ns.Name, map[string]string{"foo": "bar"})
+		foreign.Labels = map[string]string{controller.NamespaceClassOwnedByKey: "other-class"}
+		r, _, ctx := setupTestReconciler(ns, class, gone, handmade, foreign)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var names []string
+		for _, cm := range listConfigMaps(r.Client, ctx, ns.Name) {
+			names = append(names, cm.Name)
+		}
+		Expect(names).To(ConsistOf("current", "handmade", "foreign"))
+
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		Expect(events).To(ContainElement(And(ContainSubstring("DeletionSkipped"), ContainSubstring("'handmade'"))))
+	})
+
+	It("should only delete resources of the previous class that it owns", func() {
+		ns := newNamespace("owned-switch-ns", "owned-switch-b")
+		ns.Annotations = map[string]string{
+			controller.NamespaceClassCleanupKey:      "true",
+			controller.NamespaceClassAppliedClassKey: "owned-switch-a",
+		}
+		classA := newNamespaceClass("owned-switch-a",
+			mustRawConfigMap("cm-a", map[string]string{"foo": "a"}),
+			mustRawConfigMap("handmade", map[string]string{"foo": "a"}),
+		)
+		classB := newNamespaceClass("owned-switch-b", mustRawConfigMap("cm-b", map[string]string{"foo": "b"}))
+		injected := newInjectedConfigMap("cm-a", ns.Name, map[string]string{"foo": "a"})
+		injected.Labels = map[string]string{controller.NamespaceClassOwnedByKey: classA.Name}
+		handmade := newInjectedConfigMap("handmade", ns.Name, map[string]string{"precious": "data"})
+		r, _, ctx := setupTestReconciler(ns, classA, classB, injected, handmade)
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		var names []string
+		for _, cm := range listConfigMaps(r.Client, ctx, ns.Name) {
+			names = append(names, cm.Name)
+		}
+		Expect(names).To(ConsistOf("cm-b", "handmade"))
+	})
+})
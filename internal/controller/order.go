@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// sortByApplyOrder sorts objs by their NamespaceClassApplyOrderKey annotation,
+// lowest first, so that e.g. a ServiceAccount can be created before the
+// RoleBinding that refers to it. Objects without the annotation, or with a
+// value that is not an integer, have order 0. The sort is stable, so objects of
+// the same order keep the order of the class.
+func sortByApplyOrder(log logr.Logger, objs []*unstructured.Unstructured) {
+	orders := make(map[*unstructured.Unstructured]int, len(objs))
+	for _, obj := range objs {
+		value, ok := obj.GetAnnotations()[NamespaceClassApplyOrderKey]
+		if !ok {
+			continue
+		}
+		order, err := strconv.Atoi(value)
+		if err != nil {
+			withObject(log, obj).Info("Ignoring invalid apply order", "value", value)
+			continue
+		}
+		orders[obj] = order
+	}
+
+	slices.SortStableFunc(objs, func(a, b *unstructured.Unstructured) int {
+		return cmp.Compare(orders[a], orders[b])
+	})
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Apply order", func() {
+	var created []string
+
+	recordCreates := interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			created = append(created, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+			return c.Create(ctx, obj, opts...)
+		},
+	}
+
+	BeforeEach(func() {
+		created = nil
+	})
+
+	DescribeTable("should create lower-ordered resources first",
+		func(viaNamespace bool) {
+			ns := newNamespace("ordered-ns", "ordered-class")
+			class := newNamespaceClass("ordered-class",
+				mustRawRoleBinding("app", "app", map[string]string{controller.NamespaceClassApplyOrderKey: "1"}),
+				mustRawAnnotatedConfigMap("settings", nil, map[string]string{"foo": "bar"}),
+				mustRawServiceAccount("app"),
+			)
+			r, _, ctx := setupTestReconcilerWithInterceptor(recordCreates, ns, class)
+
+			request := requestFor(class)
+			if viaNamespace {
+				request = requestFor(ns)
+			}
+			_, err := r.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(created).To(Equal([]string{"ConfigMap/settings", "ServiceAccount/app", "RoleBinding/app"}))
+		},
+		Entry("when the class is reconciled", false),
+		Entry("when the namespace is reconciled", true),
+	)
+
+	It("should delete resources in reverse apply order on cleanup", func() {
+		ns := newNamespace("ordered-cleanup-ns", "ordered-cleanup-class")
+		setCleanupAnnotation(ns)
+		class := newDeletedNamespaceClass("ordered-cleanup-class",
+			mustRawRoleBinding("app", "app", map[string]string{controller.NamespaceClassApplyOrderKey: "1"}),
+			mustRawAnnotatedConfigMap("settings", nil, map[string]string{"foo": "bar"}),
+			mustRawServiceAccount("app"),
+		)
+		withName := func(name string) metav1.ObjectMeta {
+			return metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns.Name,
+				Labels:    map[string]string{controller.NamespaceClassOwnedByKey: class.Name},
+			}
+		}
+
+		var deleted []string
+		r, _, ctx := setupTestReconcilerWithInterceptor(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				deleted = append(deleted, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+				return c.Delete(ctx, obj, opts...)
+			},
+		}, ns, class,
+			&corev1.ConfigMap{ObjectMeta: withName("settings")},
+			&corev1.ServiceAccount{ObjectMeta: withName("app")},
+			&rbacv1.RoleBinding{
+				ObjectMeta: withName("app"),
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+			},
+		)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(deleted).To(Equal([]string{"RoleBinding/app", "ServiceAccount/app", "ConfigMap/settings"}))
+	})
+
+	It("should apply and delete obsolete resources in the same order every time", func() {
+		names := []string{"kilo", "echo", "alpha", "hotel", "delta", "charlie", "golf", "bravo"}
+		resources := make([]runtime.RawExtension, 0, len(names))
+		expectedCreates := make([]string, 0, len(names))
+		for _, name := range names {
+			resources = append(resources, mustRawConfigMap(name, map[string]string{"foo": "bar"}))
+			expectedCreates = append(expectedCreates, "ConfigMap/"+name)
+		}
+
+		for range 5 {
+			created = nil
+			ns := newNamespace("stable-ns", "stable-class")
+			ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+			class := newNamespaceClass("stable-class", resources...)
+
+			var deleted []string
+			funcs := recordCreates
+			funcs.Delete = func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				deleted = append(deleted, obj.GetName())
+				return c.Delete(ctx, obj, opts...)
+			}
+			r, _, ctx := setupTestReconcilerWithInterceptor(funcs, ns, class)
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(Equal(expectedCreates))
+
+			var persisted v1alpha1.NamespaceClass
+			Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+			persisted.Spec.Resources = resources[:1]
+			Expect(r.Update(ctx, &persisted)).To(Succeed())
+
+			_, err = r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal([]string{"alpha", "bravo", "charlie", "delta", "echo", "golf", "hotel"}))
+		}
+	})
+})
+
+func mustRawServiceAccount(name string) runtime.RawExtension {
+	sa := &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	raw, err := json.Marshal(sa)
+	Expect(err).NotTo(HaveOccurred())
+	return runtime.RawExtension{Raw: raw}
+}
+
+func mustRawRoleBinding(name, serviceAccount string, annotations map[string]string) runtime.RawExtension {
+	rb := &rbacv1.RoleBinding{
+		TypeMeta:   metav1.TypeMeta{Kind: "RoleBinding", APIVersion: rbacv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: serviceAccount}},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+	}
+	raw, err := json.Marshal(rb)
+	Expect(err).NotTo(HaveOccurred())
+	return runtime.RawExtension{Raw: raw}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// FindOrphanedResources lists the objects of the given kinds, across all
+// namespaces, whose NamespaceClassOwnedByKey label names a NamespaceClass that
+// no longer exists, e.g. because the class was deleted without cleanup, or
+// whose NamespaceClassUIDKey label shows that they were created by an earlier
+// class of that name that was deleted and recreated since. Kinds
+// default to DefaultWatchedKinds; kinds that are not installed are skipped.
+// The objects are sorted by kind, namespace and name.
+func FindOrphanedResources(ctx context.Context, c client.Reader, kinds []schema.GroupVersionKind) ([]*unstructured.Unstructured, error) {
+	if kinds == nil {
+		kinds = DefaultWatchedKinds
+	}
+
+	var classes v1alpha1.NamespaceClassList
+	if err := c.List(ctx, &classes); err != nil {
+		return nil, fmt.Errorf("listing NamespaceClasses: %w", err)
+	}
+	uids := map[string]types.UID{}
+	for _, class := range classes.Items {
+		uids[class.Name] = class.UID
+	}
+
+	var orphans []*unstructured.Unstructured
+	for _, gvk := range kinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, list, client.HasLabels{NamespaceClassOwnedByKey}); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("listing %s: %w", gvk.Kind, err)
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			uid, exists := uids[obj.GetLabels()[NamespaceClassOwnedByKey]]
+			// Objects from before the UID was recorded have no label
+			stamped, ok := obj.GetLabels()[NamespaceClassUIDKey]
+			if !exists || ok && types.UID(stamped) != uid {
+				orphans = append(orphans, obj)
+			}
+		}
+	}
+
+	slices.SortStableFunc(orphans, func(a, b *unstructured.Unstructured) int {
+		if n := strings.Compare(a.GetKind(), b.GetKind()); n != 0 {
+			return n
+		}
+		if n := strings.Compare(a.GetNamespace(), b.GetNamespace()); n != 0 {
+			return n
+		}
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return orphans, nil
+}
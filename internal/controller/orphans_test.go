@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Orphaned resources", func() {
+	owned := func(name, namespace, class string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{controller.NamespaceClassOwnedByKey: class},
+		}}
+	}
+
+	It("should list resources whose owning class no longer exists", func() {
+		class := newNamespaceClass("live-class")
+		r, _, ctx := setupTestReconciler(class,
+			owned("kept", "team-a", "live-class"),
+			owned("left-behind", "team-b", "deleted-class"),
+			owned("also-left", "team-a", "deleted-class"),
+			newInjectedConfigMap("unlabeled", "team-a", map[string]string{"foo": "bar"}),
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+				Name:      "robot",
+				Namespace: "team-b",
+				Labels:    map[string]string{controller.NamespaceClassOwnedByKey: "deleted-class"},
+			}},
+		)
+
+		orphans, err := controller.FindOrphanedResources(ctx, r.Client, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		var refs []string
+		for _, obj := range orphans {
+			refs = append(refs, obj.GetKind()+" "+obj.GetNamespace()+"/"+obj.GetName())
+		}
+		Expect(refs).To(Equal([]string{
+			"ConfigMap team-a/also-left",
+			"ConfigMap team-b/left-behind",
+			"ServiceAccount team-b/robot",
+		}))
+	})
+
+	It("should list resources left behind by a recreated class", func() {
+		ns := newNamespace("recreated-ns", "recreated-class")
+		class := newNamespaceClass("recreated-class",
+			mustRawConfigMap("kept", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("dropped", map[string]string{"foo": "bar"}),
+		)
+		class.UID = "old-uid"
+
+		r, _, ctx := setupTestReconciler(ns, class)
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		for _, cm := range listConfigMaps(r.Client, ctx, ns.Name) {
+			Expect(cm.Labels).To(HaveKeyWithValue(controller.NamespaceClassUIDKey, "old-uid"))
+		}
+
+		// Delete the class without cleanup and recreate it with fewer resources
+		var current v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &current)).To(Succeed())
+		current.Finalizers = nil
+		Expect(r.Update(ctx, &current)).To(Succeed())
+		Expect(r.Delete(ctx, &current)).To(Succeed())
+		recreated := newNamespaceClass("recreated-class", mustRawConfigMap("kept", map[string]string{"foo": "bar"}))
+		recreated.UID = "new-uid"
+		Expect(r.Create(ctx, recreated)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(recreated))
+		Expect(err).NotTo(HaveOccurred())
+
+		orphans, err := controller.FindOrphanedResources(ctx, r.Client, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orphans).To(HaveLen(1))
+		Expect(orphans[0].GetName()).To(Equal("dropped"))
+		Expect(orphans[0].GetLabels()).To(HaveKeyWithValue(controller.NamespaceClassUIDKey, "old-uid"))
+
+		var kept corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: "kept"}, &kept)).To(Succeed())
+		Expect(kept.Labels).To(HaveKeyWithValue(controller.NamespaceClassUIDKey, "new-uid"))
+	})
+
+	It("should only scan the given kinds", func() {
+		r, _, ctx := setupTestReconciler(
+			owned("left-behind", "team-a", "deleted-class"),
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+				Name:      "robot",
+				Namespace: "team-a",
+				Labels:    map[string]string{controller.NamespaceClassOwnedByKey: "deleted-class"},
+			}},
+		)
+
+		orphans, err := controller.FindOrphanedResources(ctx, r.Client,
+			[]schema.GroupVersionKind{{Version: "v1", Kind: "ServiceAccount"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orphans).To(HaveLen(1))
+		Expect(orphans[0].GetName()).To(Equal("robot"))
+	})
+})
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// paging reports whether namespaces are listed in pages from the API server.
+func (r *NamespaceClassReconciler) paging() bool {
+	return r.ListPageSize > 0 && r.APIReader != nil
+}
+
+// listNamespaces calls fn with the namespaces that match opts and that the
+// operator manages. When paging, they are read from the API server with one
+// call, and one call of fn, per page of ListPageSize namespaces. Otherwise they
+// are listed from the cache and passed to fn at once.
+//
+// Applying a page can outlast the continue token, which the API server then
+// refuses as expired. The list is restarted in that case, and the namespaces
+// that were already passed to fn are left out of the pages that follow.
+func (r *NamespaceClassReconciler) listNamespaces(
+	ctx context.Context,
+	fn func([]corev1.Namespace) error,
+	opts ...client.ListOption,
+) error {
+	if !r.paging() {
+		var nsList corev1.NamespaceList
+		if err := r.List(ctx, &nsList, opts...); err != nil {
+			return err
+		}
+		return fn(r.managedNamespaces(nsList.Items))
+	}
+
+	opts = append(opts, client.Limit(r.ListPageSize))
+	seen := sets.New[string]()
+	for token := ""; ; {
+		var nsList corev1.NamespaceList
+		err := r.APIReader.List(ctx, &nsList, append(opts, client.Continue(token))...)
+		if token != "" && (apierrors.IsResourceExpired(err) || apierrors.IsGone(err)) {
+			ctrl.LoggerFrom(ctx).Info("Continue token expired; listing namespaces again", "listed", seen.Len())
+			token = ""
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		page := slices.DeleteFunc(r.managedNamespaces(nsList.Items), func(ns corev1.Namespace) bool { return seen.Has(ns.Name) })
+		for _, ns := range page {
+			seen.Insert(ns.Name)
+		}
+		if len(page) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+		if token = nsList.Continue; token == "" {
+			return nil
+		}
+	}
+}
+
+// classMembers selects the namespaces labeled with className. The API server
+// cannot serve NamespaceClassIndexKey, so pages select by label instead.
+func (r *NamespaceClassReconciler) classMembers(className string) client.ListOption {
+	if r.paging() {
+		return client.MatchingLabels{r.classLabelKey(): className}
+	}
+	return client.MatchingFields{NamespaceClassIndexKey: className}
+}
+
+// managedNamespaces returns the namespaces the operator manages.
+func (r *NamespaceClassReconciler) managedNamespaces(namespaces []corev1.Namespace) []corev1.Namespace {
+	return slices.DeleteFunc(namespaces, func(ns corev1.Namespace) bool { return !r.managesNamespace(ns.Name) })
+}
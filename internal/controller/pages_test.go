@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Namespace paging", func() {
+	const count = 50
+
+	var (
+		pages int
+		// expire makes the next list with a continue token fail like one whose
+		// token outlived its lifetime.
+		expire bool
+	)
+
+	// pagingReader serves namespace lists in pages like the API server, which
+	// the fake client does not.
+	pagingReader := func(c client.WithWatch) client.Reader {
+		return interceptor.NewClient(c, interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				nsList, ok := list.(*corev1.NamespaceList)
+				if !ok {
+					return c.List(ctx, list, opts...)
+				}
+				listOpts := (&client.ListOptions{}).ApplyOptions(opts)
+				if expire && listOpts.Continue != "" {
+					expire = false
+					return apierrors.NewResourceExpired("the provided continue parameter is too old")
+				}
+				unpaged := *listOpts
+				unpaged.Limit, unpaged.Continue = 0, ""
+				if err := c.List(ctx, nsList, &unpaged); err != nil {
+					return err
+				}
+				slices.SortFunc(nsList.Items, func(a, b corev1.Namespace) int { return strings.Compare(a.Name, b.Name) })
+
+				offset := 0
+				if listOpts.Continue != "" {
+					offset, _ = strconv.Atoi(listOpts.Continue)
+				}
+				total := len(nsList.Items)
+				end := min(offset+int(listOpts.Limit), total)
+				nsList.Items = nsList.Items[offset:end]
+				nsList.Continue = ""
+				if end < total {
+					nsList.Continue = strconv.Itoa(end)
+				}
+				pages++
+				return nil
+			},
+		})
+	}
+
+	seed := func(className string, annotations map[string]string) []client.Object {
+		objs := make([]client.Object, 0, count)
+		for i := range count {
+			ns := newNamespace(fmt.Sprintf("%s-%03d", className, i), className)
+			ns.Annotations = annotations
+			objs = append(objs, ns)
+		}
+		// Unrelated namespaces must not be picked up by the label selector
+		return append(objs, newNamespace("unrelated", "other-class"))
+	}
+
+	BeforeEach(func() {
+		pages = 0
+		expire = false
+	})
+
+	It("should apply the class to every namespace across pages", func() {
+		class := newNamespaceClass("paged-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(append(seed(class.Name, nil), class)...)
+		r.ListPageSize = 20
+		r.APIReader = pagingReader(r.Client.(client.WithWatch))
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(pages).To(Equal(3))
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.BoundNamespaces).To(HaveLen(count))
+		Expect(persisted.Status.BoundNamespaces).NotTo(ContainElement("unrelated"))
+		for _, name := range persisted.Status.BoundNamespaces {
+			Expect(listConfigMaps(r.Client, ctx, name)).To(HaveLen(1), name)
+		}
+	})
+
+	It("should list the namespaces again when the continue token expires", func() {
+		class := newNamespaceClass("expired-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(append(seed(class.Name, nil), class)...)
+		r.ListPageSize = 20
+		r.APIReader = pagingReader(r.Client.(client.WithWatch))
+		expire = true
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(expire).To(BeFalse())
+		Expect(pages).To(Equal(4))
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.BoundNamespaces).To(HaveLen(count))
+		for _, name := range persisted.Status.BoundNamespaces {
+			Expect(listConfigMaps(r.Client, ctx, name)).To(HaveLen(1), name)
+		}
+	})
+
+	It("should clean up every namespace across pages when the class is deleted", func() {
+		class := newDeletedNamespaceClass("paged-delete-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		objs := seed(class.Name, map[string]string{controller.NamespaceClassCleanupKey: "true"})
+		for i := range count {
+			cm := newInjectedConfigMap("cfg", fmt.Sprintf("%s-%03d", class.Name, i), nil)
+			cm.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class.Name}
+			objs = append(objs, cm)
+		}
+		r, _, ctx := setupTestReconciler(append(objs, class)...)
+		r.ListPageSize = 20
+		r.APIReader = pagingReader(r.Client.(client.WithWatch))
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(pages).To(Equal(3))
+		for i := range count {
+			Expect(listConfigMaps(r.Client, ctx, fmt.Sprintf("%s-%03d", class.Name, i))).To(BeEmpty())
+		}
+	})
+})
@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PatchTypeMerge applies an embedded resource as a JSON merge patch.
+	PatchTypeMerge = "merge"
+	// PatchTypeStrategicMerge applies an embedded resource as a strategic
+	// merge patch, which merges lists such as imagePullSecrets by key.
+	PatchTypeStrategicMerge = "strategic-merge"
+)
+
+// isPatch reports whether an embedded resource patches an existing object
+// instead of owning it.
+func isPatch(obj *unstructured.Unstructured) bool {
+	_, ok := obj.GetAnnotations()[NamespaceClassPatchTypeKey]
+	return ok
+}
+
+// patchExisting applies obj as a patch to the existing object of the same kind
+// and name. The target is neither created nor labeled as owned by the class, so
+// it is never deleted by cleanup.
+func (r *NamespaceClassReconciler) patchExisting(ctx context.Context, obj *unstructured.Unstructured) error {
+	log := resourceLogger(ctx, obj)
+
+	var patchType types.PatchType
+	switch value := obj.GetAnnotations()[NamespaceClassPatchTypeKey]; value {
+	case PatchTypeMerge:
+		patchType = types.MergePatchType
+	case PatchTypeStrategicMerge:
+		patchType = types.StrategicMergePatchType
+	default:
+		return fmt.Errorf("unknown %s %q", NamespaceClassPatchTypeKey, value)
+	}
+
+	body := obj.DeepCopy()
+	unstructured.RemoveNestedField(body.Object, "metadata", "annotations", NamespaceClassPatchTypeKey)
+	if len(body.GetAnnotations()) == 0 {
+		unstructured.RemoveNestedField(body.Object, "metadata", "annotations")
+	}
+	data, err := body.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	target := &unstructured.Unstructured{}
+	target.SetGroupVersionKind(obj.GroupVersionKind())
+	target.SetName(obj.GetName())
+	target.SetNamespace(obj.GetNamespace())
+	if err := r.writer().Patch(ctx, target, client.RawPatch(patchType, data), client.FieldOwner(FieldManager)); err != nil {
+		log.Error(err, "Failed to patch resource")
+		return err
+	}
+	r.countApplied(obj)
+
+	log.Info("Patched resource")
+	return nil
+}
+
+// layerPatch applies patch, an embedded resource with NamespaceClassPatchTypeKey,
+// to base, the resource of the same kind and name a class inherits, so that a
+// child class can change single fields of a parent's resource. The result is
+// owned like base. Strategic merge patches need the kind to be in the scheme,
+// as they do on the API server.
+func (r *NamespaceClassReconciler) layerPatch(base runtime.RawExtension, patch *unstructured.Unstructured) (runtime.RawExtension, error) {
+	var original map[string]interface{}
+	if err := json.Unmarshal(base.Raw, &original); err != nil {
+		return base, err
+	}
+
+	body := patch.DeepCopy()
+	unstructured.RemoveNestedField(body.Object, "metadata", "annotations", NamespaceClassPatchTypeKey)
+	if len(body.GetAnnotations()) == 0 {
+		unstructured.RemoveNestedField(body.Object, "metadata", "annotations")
+	}
+
+	var merged map[string]interface{}
+	switch value := patch.GetAnnotations()[NamespaceClassPatchTypeKey]; value {
+	case PatchTypeMerge:
+		merged = mergePatch(original, body.Object)
+	case PatchTypeStrategicMerge:
+		typed, err := r.Scheme.New(patch.GroupVersionKind())
+		if err != nil {
+			return base, fmt.Errorf("strategic merge patches are not supported for %s: %w", patch.GroupVersionKind().Kind, err)
+		}
+		if merged, err = strategicpatch.StrategicMergeMapPatch(original, body.Object, typed); err != nil {
+			return base, err
+		}
+	default:
+		return base, fmt.Errorf("unknown %s %q", NamespaceClassPatchTypeKey, value)
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return base, err
+	}
+	return runtime.RawExtension{Raw: data}, nil
+}
+
+// mergePatch applies a JSON merge patch (RFC 7386) to original: nested maps are
+// merged recursively, null removes a key, and any other value, including lists,
+// replaces it.
+func mergePatch(original, patch map[string]interface{}) map[string]interface{} {
+	if original == nil {
+		original = map[string]interface{}{}
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(original, key)
+			continue
+		}
+		patchMap, patchIsMap := value.(map[string]interface{})
+		if !patchIsMap {
+			original[key] = value
+			continue
+		}
+		originalMap, _ := original[key].(map[string]interface{})
+		original[key] = mergePatch(originalMap, patchMap)
+	}
+	return original
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Patches", func() {
+	mergePatch := map[string]string{controller.NamespaceClassPatchTypeKey: controller.PatchTypeMerge}
+
+	It("should merge a patch into an existing object without owning it", func() {
+		ns := newNamespace("patch-ns", "patch-class")
+		class := newNamespaceClass("patch-class",
+			mustRawAnnotatedConfigMap("settings", mergePatch, map[string]string{"added": "by-class"}))
+		existing := newInjectedConfigMap("settings", ns.Name, map[string]string{"kept": "by-user"})
+		r, _, ctx := setupTestReconciler(ns, class, existing)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "settings", Namespace: ns.Name}, &cm)).To(Succeed())
+		Expect(cm.Data).To(Equal(map[string]string{"kept": "by-user", "added": "by-class"}))
+		Expect(cm.Labels).NotTo(HaveKey(controller.NamespaceClassOwnedByKey))
+		Expect(cm.Annotations).NotTo(HaveKey(controller.NamespaceClassPatchTypeKey))
+	})
+
+	It("should add an imagePullSecret to the default ServiceAccount with a strategic merge patch", func() {
+		ns := newNamespace("sa-patch-ns", "sa-patch-class")
+		class := newNamespaceClass("sa-patch-class", mustRawPullSecretPatch("default", "registry"))
+		sa := &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: ns.Name, Labels: map[string]string{"team": "payments"}},
+			Secrets:    []corev1.ObjectReference{{Name: "default-token"}},
+		}
+		r, _, ctx := setupTestReconciler(ns, class, sa)
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		var patched corev1.ServiceAccount
+		Expect(r.Get(ctx, types.NamespacedName{Name: "default", Namespace: ns.Name}, &patched)).To(Succeed())
+		Expect(patched.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "registry"}))
+		Expect(patched.Secrets).To(ConsistOf(corev1.ObjectReference{Name: "default-token"}))
+		Expect(patched.Labels).To(Equal(map[string]string{"team": "payments"}))
+	})
+
+	It("should not create a missing patch target", func() {
+		ns := newNamespace("missing-target-ns", "missing-target-class")
+		class := newNamespaceClass("missing-target-class",
+			mustRawAnnotatedConfigMap("absent", mergePatch, map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+	})
+
+	It("should not delete a patched object during cleanup", func() {
+		ns := newNamespace("patch-cleanup-ns", "patch-cleanup-class")
+		setCleanupAnnotation(ns)
+		class := newDeletedNamespaceClass("patch-cleanup-class",
+			mustRawAnnotatedConfigMap("settings", mergePatch, map[string]string{"foo": "bar"}))
+		existing := newInjectedConfigMap("settings", ns.Name, map[string]string{"foo": "bar"})
+		r, _, ctx := setupTestReconciler(ns, class, existing)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+	})
+})
+
+func mustRawPullSecretPatch(serviceAccount, secret string) runtime.RawExtension {
+	raw, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ServiceAccount",
+		"metadata": map[string]interface{}{
+			"name": serviceAccount,
+			"annotations": map[string]string{
+				controller.NamespaceClassPatchTypeKey: controller.PatchTypeStrategicMerge,
+			},
+		},
+		"imagePullSecrets": []map[string]string{{"name": secret}},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return runtime.RawExtension{Raw: raw}
+}
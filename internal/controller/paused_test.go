@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Paused NamespaceClasses", func() {
+	paused := map[string]string{controller.NamespaceClassPausedKey: "true"}
+
+	// countResourceWrites counts the writes to every object but Namespaces and
+	// NamespaceClasses, i.e. to injected resources.
+	countResourceWrites := func(writes *int) interceptor.Funcs {
+		count := func(obj client.Object) {
+			switch obj.(type) {
+			case *corev1.Namespace, *v1alpha1.NamespaceClass:
+			default:
+				*writes++
+			}
+		}
+		return interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				count(obj)
+				return c.Create(ctx, obj, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				count(obj)
+				return c.Update(ctx, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				count(obj)
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				count(obj)
+				return c.Delete(ctx, obj, opts...)
+			},
+		}
+	}
+
+	It("should not create, update or delete resources of a paused class", func() {
+		ns := newNamespace("paused-ns", "paused-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		class := newNamespaceClass("paused-class",
+			mustRawConfigMap("new", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("changed", map[string]string{"foo": "new"}))
+		class.Annotations = paused
+		class.Status.LastAppliedResources = []runtime.RawExtension{mustRawConfigMap("obsolete", map[string]string{"foo": "bar"})}
+		changed := newInjectedConfigMap("changed", ns.Name, map[string]string{"foo": "old"})
+		obsolete := newInjectedConfigMap("obsolete", ns.Name, map[string]string{"foo": "bar"})
+
+		writes := 0
+		r, _, ctx := setupTestReconcilerWithInterceptor(countResourceWrites(&writes), ns, class, changed, obsolete)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = r.Reconcile(ctx, controller.NamespaceRequest(class.Name, ns.Name))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(writes).To(BeZero())
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(2))
+		for _, cm := range cms {
+			Expect(cm.Name).NotTo(Equal("new"))
+			if cm.Name == "changed" {
+				Expect(cm.Data).To(HaveKeyWithValue("foo", "old"))
+			}
+		}
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("ReconcilePaused")))
+	})
+
+	It("should apply the class once it is resumed", func() {
+		ns := newNamespace("resumed-ns", "resumed-class")
+		class := newNamespaceClass("resumed-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		class.Annotations = paused
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		persisted.Annotations = nil
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+	})
+
+	It("should let a paused class be deleted without deleting its resources", func() {
+		ns := newNamespace("paused-delete-ns", "paused-delete-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupKey: "true"}
+		class := newDeletedNamespaceClass("paused-delete-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		class.Annotations = paused
+		cm := newInjectedConfigMap("cfg", ns.Name, map[string]string{"foo": "bar"})
+		cm.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class.Name}
+		r, _, ctx := setupTestReconciler(ns, class, cm)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		err = r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)
+		if err == nil {
+			Expect(controllerutil.ContainsFinalizer(&persisted, controller.NamespaceClassFinalizerKey)).To(BeFalse())
+		} else {
+			Expect(client.IgnoreNotFound(err)).To(Succeed())
+		}
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+	})
+
+	It("should not delete resources of a paused class when a namespace leaves it", func() {
+		ns := newNamespace("paused-leave-ns", "")
+		ns.Annotations = map[string]string{
+			controller.NamespaceClassCleanupKey:      "true",
+			controller.NamespaceClassAppliedClassKey: "paused-leave-class",
+		}
+		class := newNamespaceClass("paused-leave-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		class.ObjectMeta = metav1.ObjectMeta{Name: class.Name, Annotations: paused}
+		cm := newInjectedConfigMap("cfg", ns.Name, map[string]string{"foo": "bar"})
+		cm.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class.Name}
+		r, _, ctx := setupTestReconciler(ns, class, cm)
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+		var persisted corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Annotations).NotTo(HaveKey(controller.NamespaceClassAppliedClassKey))
+	})
+})
@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// setPhase writes phase to the status of class, unless it is there already.
+// The write goes through a copy, since it returns the stored spec, which would
+// replace the inherited and referenced resources of an effective class.
+func (r *NamespaceClassReconciler) setPhase(ctx context.Context, class *v1alpha1.NamespaceClass, phase v1alpha1.ClassPhase) error {
+	if class.Status.Phase == phase {
+		return nil
+	}
+	stored := class.DeepCopy()
+	stored.Status.Phase = phase
+	if err := r.Status().Update(ctx, stored); err != nil {
+		return err
+	}
+	class.Status.Phase = phase
+	class.ResourceVersion = stored.ResourceVersion
+	return nil
+}
+
+// startApplying moves class into the Applying phase before its resources are
+// applied, but only for a generation that was not applied yet or a pending
+// force-reconcile. A resync of a class that is Ready or in Error keeps its
+// phase, so that it does not flap and the status is written at most once.
+func (r *NamespaceClassReconciler) startApplying(ctx context.Context, class *v1alpha1.NamespaceClass) error {
+	ready := meta.FindStatusCondition(class.Status.Conditions, v1alpha1.ConditionReady)
+	if ready != nil && ready.ObservedGeneration == class.Generation && !forceRequested(class) {
+		return nil
+	}
+	return r.setPhase(ctx, class, v1alpha1.ClassPhaseApplying)
+}
+
+// failPhase moves class into the Error phase after a reconcile failed before
+// its status could be written. Failing to do so is only logged, so that the
+// original error is returned.
+func (r *NamespaceClassReconciler) failPhase(ctx context.Context, log logr.Logger, class *v1alpha1.NamespaceClass) {
+	if err := r.setPhase(ctx, class, v1alpha1.ClassPhaseError); err != nil {
+		log.Error(err, "Failed to set NamespaceClass phase", "phase", v1alpha1.ClassPhaseError)
+	}
+}
+
+// phaseFor returns the phase of a class after a reconcile with the given
+// per-namespace failures.
+func phaseFor(failures []string) v1alpha1.ClassPhase {
+	if len(failures) > 0 {
+		return v1alpha1.ClassPhaseError
+	}
+	return v1alpha1.ClassPhaseReady
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+var _ = Describe("Class phase", func() {
+	// phases records the phase of every status write, in order
+	var phases []v1alpha1.ClassPhase
+	recordPhases := func(create func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error) interceptor.Funcs {
+		return interceptor.Funcs{
+			Create: create,
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if class, ok := obj.(*v1alpha1.NamespaceClass); ok {
+					phases = append(phases, class.Status.Phase)
+				}
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		phases = nil
+	})
+
+	phaseOf := func(r client.Reader, ctx context.Context, name string) v1alpha1.ClassPhase {
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: name}, &persisted)).To(Succeed())
+		return persisted.Status.Phase
+	}
+
+	It("should move a new class through Pending and Applying to Ready", func() {
+		ns := newNamespace("phase-ns", "phase-class")
+		class := newNamespaceClass("phase-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconcilerWithInterceptor(recordPhases(nil), ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(phases).To(Equal([]v1alpha1.ClassPhase{
+			v1alpha1.ClassPhasePending, v1alpha1.ClassPhaseApplying, v1alpha1.ClassPhaseReady,
+		}))
+		Expect(phaseOf(r, ctx, class.Name)).To(Equal(v1alpha1.ClassPhaseReady))
+
+		By("staying Ready without status writes while nothing changes")
+		phases = nil
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phases).To(BeEmpty())
+	})
+
+	It("should move a class that cannot be applied to Error", func() {
+		ns := newNamespace("phase-error-ns", "phase-error-class")
+		class := newNamespaceClass("phase-error-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconcilerWithInterceptor(recordPhases(
+			func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if _, ok := obj.(*corev1.Namespace); ok {
+					return c.Create(ctx, obj, opts...)
+				}
+				return errors.New("admission webhook denied the request")
+			}), ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(phases).To(Equal([]v1alpha1.ClassPhase{
+			v1alpha1.ClassPhasePending, v1alpha1.ClassPhaseApplying, v1alpha1.ClassPhaseError,
+		}))
+		Expect(phaseOf(r, ctx, class.Name)).To(Equal(v1alpha1.ClassPhaseError))
+
+		By("staying in Error on a resync of the same generation")
+		phases = nil
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phases).NotTo(ContainElement(v1alpha1.ClassPhaseApplying))
+		Expect(phaseOf(r, ctx, class.Name)).To(Equal(v1alpha1.ClassPhaseError))
+	})
+})
@@ -0,0 +1,43 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// deleteOptions returns the options the resources of class are deleted with.
+// NamespaceClassPropagationPolicyKey selects the propagation policy, so that
+// e.g. a Deployment is only gone once its Pods are. An unknown policy is
+// reported on the class and the API server default is used instead.
+func (r *NamespaceClassReconciler) deleteOptions(class *v1alpha1.NamespaceClass) []client.DeleteOption {
+	value, ok := class.Annotations[NamespaceClassPropagationPolicyKey]
+	if !ok {
+		return nil
+	}
+	switch policy := metav1.DeletionPropagation(value); policy {
+	case metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan:
+		return []client.DeleteOption{client.PropagationPolicy(policy)}
+	default:
+		r.warnOnce(class, "InvalidPropagationPolicy",
+			"Unknown %s %q; expected Foreground, Background or Orphan", NamespaceClassPropagationPolicyKey, value)
+		return nil
+	}
+}
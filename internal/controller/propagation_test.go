@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Propagation policy", func() {
+	// policies records the propagation policy of every delete, "" for none
+	var policies []string
+	recordPolicies := interceptor.Funcs{
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			options := &client.DeleteOptions{}
+			options.ApplyOptions(opts)
+			policy := ""
+			if options.PropagationPolicy != nil {
+				policy = string(*options.PropagationPolicy)
+			}
+			policies = append(policies, policy)
+			return c.Delete(ctx, obj, opts...)
+		},
+	}
+
+	BeforeEach(func() {
+		policies = nil
+	})
+
+	// injected is a ConfigMap the class created in ns
+	injected := func(ns *corev1.Namespace, class *v1alpha1.NamespaceClass) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      "cfg",
+			Namespace: ns.Name,
+			Labels:    map[string]string{controller.NamespaceClassOwnedByKey: class.Name},
+		}}
+	}
+
+	withPolicy := func(class *v1alpha1.NamespaceClass, policy string) *v1alpha1.NamespaceClass {
+		if policy != "" {
+			class.Annotations = map[string]string{controller.NamespaceClassPropagationPolicyKey: policy}
+		}
+		return class
+	}
+
+	DescribeTable("should delete the resources of a deleted class with the policy of the class",
+		func(policy string) {
+			ns := newNamespace("propagation-ns", "propagation-class")
+			setCleanupAnnotation(ns)
+			class := withPolicy(newDeletedNamespaceClass("propagation-class",
+				mustRawConfigMap("cfg", map[string]string{"foo": "bar"})), policy)
+			r, _, ctx := setupTestReconcilerWithInterceptor(recordPolicies, ns, class,
+				injected(ns, class))
+
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(policies).To(Equal([]string{policy}))
+		},
+		Entry("by default", ""),
+		Entry("in the foreground", string(metav1.DeletePropagationForeground)),
+		Entry("in the background", string(metav1.DeletePropagationBackground)),
+		Entry("orphaning dependents", string(metav1.DeletePropagationOrphan)),
+	)
+
+	It("should delete obsolete resources with the policy of the class", func() {
+		ns := newNamespace("propagation-obsolete-ns", "propagation-obsolete-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		class := withPolicy(newNamespaceClass("propagation-obsolete-class",
+			mustRawConfigMap("current", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("data", map[string]string{"foo": "bar"}),
+		), string(metav1.DeletePropagationForeground))
+		r, _, ctx := setupTestReconcilerWithInterceptor(recordPolicies, ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		persisted.Spec.Resources = persisted.Spec.Resources[:1]
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(policies).To(Equal([]string{string(metav1.DeletePropagationForeground)}))
+	})
+
+	It("should report an unknown policy and fall back to the default", func() {
+		ns := newNamespace("propagation-invalid-ns", "propagation-invalid-class")
+		setCleanupAnnotation(ns)
+		class := withPolicy(newDeletedNamespaceClass("propagation-invalid-class",
+			mustRawConfigMap("cfg", map[string]string{"foo": "bar"})), "Sideways")
+		r, _, ctx := setupTestReconcilerWithInterceptor(recordPolicies, ns, class,
+			injected(ns, class))
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(policies).To(Equal([]string{""}))
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		Expect(events).To(ContainElement(ContainSubstring("InvalidPropagationPolicy")))
+	})
+})
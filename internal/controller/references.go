@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// danglingReferences returns a message per ServiceAccount subject of a
+// RoleBinding of class that the class does not define, so that a typo or a
+// forgotten ServiceAccount is noticed before the binding grants nothing.
+// Subjects in other namespaces and the "default" ServiceAccount, which every
+// namespace has, are not checked.
+func danglingReferences(class *v1alpha1.NamespaceClass) []string {
+	serviceAccounts := map[string]bool{"default": true}
+	var bindings []*unstructured.Unstructured
+	for _, res := range class.Spec.Resources {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(res.Raw); err != nil || isPatch(obj) || isDeletion(obj) {
+			continue
+		}
+		switch obj.GroupVersionKind().GroupKind() {
+		case schema.GroupKind{Kind: "ServiceAccount"}:
+			serviceAccounts[obj.GetName()] = true
+		case schema.GroupKind{Group: rbacv1.GroupName, Kind: "RoleBinding"}:
+			bindings = append(bindings, obj)
+		}
+	}
+
+	var problems []string
+	for _, obj := range bindings {
+		var binding rbacv1.RoleBinding
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &binding); err != nil {
+			continue
+		}
+		for _, subject := range binding.Subjects {
+			if subject.Kind != rbacv1.ServiceAccountKind || subject.Namespace != "" || serviceAccounts[subject.Name] {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("%s binds ServiceAccount %q, which the class does not define",
+				resourceRef(obj), subject.Name))
+		}
+	}
+	return problems
+}
+
+// setReferencesValidCondition records the result of danglingReferences on
+// class, an effective class whose resources include the inherited and
+// referenced ones, and warns about dangling references with an Event. The
+// condition is only maintained when CheckReferences is enabled.
+func (r *NamespaceClassReconciler) setReferencesValidCondition(class *v1alpha1.NamespaceClass) {
+	if !r.CheckReferences {
+		return
+	}
+	cond := metav1.Condition{
+		Type:               v1alpha1.ConditionReferencesValid,
+		Status:             metav1.ConditionTrue,
+		Reason:             v1alpha1.ReasonReferencesResolved,
+		Message:            "All referenced ServiceAccounts are defined by the class",
+		ObservedGeneration: class.Generation,
+	}
+	if problems := danglingReferences(class); len(problems) > 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = v1alpha1.ReasonDanglingReference
+		cond.Message = conditionMessage(problems)
+		r.warnOnce(class, "DanglingReference", "NamespaceClass '%s' has dangling references: %s",
+			class.Name, cond.Message)
+	}
+	meta.SetStatusCondition(&class.Status.Conditions, cond)
+}
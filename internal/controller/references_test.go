@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+var _ = Describe("Reference checks", func() {
+	referencesValid := func(class *v1alpha1.NamespaceClass, check bool, objs ...runtime.RawExtension) (*metav1.Condition, []string) {
+		GinkgoHelper()
+		class.Spec.Resources = objs
+		ns := newNamespace(class.Name+"-ns", class.Name)
+		r, _, ctx := setupTestReconciler(ns, class)
+		r.CheckReferences = check
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		return meta.FindStatusCondition(persisted.Status.Conditions, v1alpha1.ConditionReferencesValid), events
+	}
+
+	It("should accept RoleBindings of ServiceAccounts the class defines", func() {
+		cond, events := referencesValid(newNamespaceClass("refs-valid"), true,
+			mustRawRoleBinding("deployer", "ci", nil),
+			mustRawServiceAccount("ci"),
+			mustRawRoleBinding("viewer", "default", nil),
+		)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(v1alpha1.ReasonReferencesResolved))
+		Expect(events).NotTo(ContainElement(ContainSubstring("DanglingReference")))
+	})
+
+	It("should report a RoleBinding subject the class does not define", func() {
+		cond, events := referencesValid(newNamespaceClass("refs-dangling"), true,
+			mustRawServiceAccount("ci"),
+			mustRawRoleBinding("deployer", "cl", nil),
+		)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(v1alpha1.ReasonDanglingReference))
+		Expect(cond.Message).To(Equal(`RoleBinding/deployer binds ServiceAccount "cl", which the class does not define`))
+		Expect(events).To(ContainElement(ContainSubstring("DanglingReference")))
+	})
+
+	It("should cap the message of a class with many dangling references", func() {
+		var objs []runtime.RawExtension
+		for i := range 500 {
+			objs = append(objs, mustRawRoleBinding(fmt.Sprintf("binding-%d", i), fmt.Sprintf("sa-%d-%s", i, strings.Repeat("x", 50)), nil))
+		}
+		cond, _ := referencesValid(newNamespaceClass("refs-many"), true, objs...)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(len(cond.Message)).To(BeNumerically("<=", 32768))
+		Expect(cond.Message).To(MatchRegexp(`; \.\.\. and \d+ more$`))
+	})
+
+	It("should not check references unless enabled", func() {
+		cond, events := referencesValid(newNamespaceClass("refs-off"), false,
+			mustRawRoleBinding("deployer", "missing", nil),
+		)
+		Expect(cond).To(BeNil())
+		Expect(events).NotTo(ContainElement(ContainSubstring("DanglingReference")))
+	})
+})
@@ -0,0 +1,218 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// needsRelease reports whether ns left the class it was applied from, either
+// because its class label was removed or because it is being deleted while it
+// still carries the operator's finalizer.
+func (r *NamespaceClassReconciler) needsRelease(ctx context.Context, ns client.Object) bool {
+	finalized := controllerutil.ContainsFinalizer(ns, NamespaceClassFinalizerKey)
+	if ns.GetDeletionTimestamp() != nil {
+		return finalized
+	}
+	if _, ok := r.classNameFor(ctx, ns); ok {
+		return false
+	}
+	return finalized || ns.GetAnnotations()[NamespaceClassAppliedClassKey] != ""
+}
+
+// releaseNamespace forgets the class that was last applied to ns. If the class
+// label was removed and the namespace opted into cleanup, the resources of that
+// class are deleted first. A namespace that is being deleted takes its resources
+// with it, except for the cluster-scoped ones no other namespace needs, which
+// are deleted here, and a class that no longer exists has nothing left to clean
+// up, so neither keeps the finalizer from being removed.
+func (r *NamespaceClassReconciler) releaseNamespace(ctx context.Context, log logr.Logger, ns *corev1.Namespace) error {
+	previous := ns.Annotations[NamespaceClassAppliedClassKey]
+	if previous != "" && ns.DeletionTimestamp != nil && !r.DryRun {
+		if err := r.releaseClusterScopedOf(ctx, log, ns, previous); err != nil {
+			return err
+		}
+	}
+	if previous != "" && ns.DeletionTimestamp == nil && ns.Annotations[NamespaceClassCleanupKey] == "true" {
+		var class v1alpha1.NamespaceClass
+		err := r.Get(ctx, types.NamespacedName{Name: previous}, &class)
+		switch {
+		case apierrors.IsNotFound(err):
+			log.Info("Previous class not found — skipping resource cleanup", logKeyClass, previous)
+		case err != nil:
+			return err
+		case isPaused(&class):
+			log.Info("Previous class is paused — skipping resource cleanup", logKeyClass, previous)
+		default:
+			log.Info("Namespace left its NamespaceClass; deleting its resources", logKeyClass, previous)
+			resolved, err := r.resolvedClass(ctx, ns, &class)
+			if err != nil {
+				// Deleting what can be read leaves less behind than deleting nothing
+				log.Error(err, "Not every resource of the previous class could be read", logKeyClass, previous)
+			}
+			r.deleteInjected(ctx, log, ns, resolved)
+		}
+	}
+
+	patch := client.MergeFrom(ns.DeepCopy())
+	delete(ns.Annotations, NamespaceClassAppliedClassKey)
+	delete(ns.Annotations, NamespaceClassAppliedHashKey)
+	delete(ns.Annotations, NamespaceClassAppliedCleanupObsoleteKey)
+	delete(ns.Annotations, NamespaceClassInventoryKey)
+	controllerutil.RemoveFinalizer(ns, NamespaceClassFinalizerKey)
+	return r.Patch(ctx, ns, patch)
+}
+
+// releaseClusterScopedOf releases the cluster-scoped resources of the class
+// named className for the deleted namespace ns and drops the deleted ones from
+// the status of the class, so that they are created again for the next
+// namespace that needs them.
+func (r *NamespaceClassReconciler) releaseClusterScopedOf(
+	ctx context.Context,
+	log logr.Logger,
+	ns *corev1.Namespace,
+	className string,
+) error {
+	var class v1alpha1.NamespaceClass
+	if err := r.Get(ctx, types.NamespacedName{Name: className}, &class); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	effective, err := r.effectiveClass(ctx, &class)
+	if err != nil {
+		return err
+	}
+	gone, err := r.releaseClusterScoped(ctx, log, ns, effective)
+	if len(gone) == 0 {
+		return err
+	}
+	class.Status.ClusterScopedResources = slices.DeleteFunc(class.Status.ClusterScopedResources,
+		func(key string) bool { return slices.Contains(gone, key) })
+	if updateErr := r.Status().Update(ctx, &class); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
+
+// deleteInjected deletes the resources of class from ns. Kinds that are not
+// allowed are skipped, and so are objects that do not carry the ownership label
+// of class, e.g. ones a user created by hand under the same name. Resources are
+// deleted in reverse apply order, so that dependents go before what they
+// depend on. Resources named by generateName are found by their prefix.
+func (r *NamespaceClassReconciler) deleteInjected(ctx context.Context, log logr.Logger, ns *corev1.Namespace, class *v1alpha1.NamespaceClass) {
+	objs := make([]*unstructured.Unstructured, 0, len(class.Spec.Resources))
+	for _, res := range class.Spec.Resources {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(res.Raw); err != nil || isPatch(obj) {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	sortByApplyOrder(log, objs)
+	slices.Reverse(objs)
+
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		name := obj.GetName()
+		if !r.kindAllowed(gvk) {
+			// Never created, and deleting it would bypass the allowlist
+			continue
+		}
+
+		target, ok := r.targetNamespace(obj, ns.Name)
+		if !ok {
+			continue
+		}
+		if name == "" && obj.GetGenerateName() != "" {
+			obj.SetNamespace(target)
+			generated, err := r.generatedName(ctx, obj, class.Name)
+			if err != nil {
+				withResource(log, gvk, obj.GetGenerateName()).Error(err, "Failed to look up generated name")
+				continue
+			}
+			if generated == "" {
+				continue
+			}
+			name = generated
+		}
+
+		live := r.ownedObject(ctx, log, ns, gvk, types.NamespacedName{Name: name, Namespace: target}, class.Name)
+		if live == nil {
+			continue
+		}
+
+		if err := r.writer().Delete(ctx, live, r.deleteOptions(class)...); err != nil {
+			withResource(log, gvk, name).Error(err, "Failed to delete resource")
+		} else {
+			withResource(log, gvk, name).Info("Deleted resource")
+		}
+	}
+}
+
+// ownedObject returns the live object of kind gvk under key if it carries the
+// ownership label of the class named className, and nil otherwise. Objects
+// that belong to another class, or to nobody, e.g. because a user created them
+// by hand under the same name, are reported with a DeletionSkipped Event on ns
+// so that they are never deleted on behalf of the class.
+func (r *NamespaceClassReconciler) ownedObject(
+	ctx context.Context,
+	log logr.Logger,
+	ns *corev1.Namespace,
+	gvk schema.GroupVersionKind,
+	key types.NamespacedName,
+	className string,
+) *unstructured.Unstructured {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(gvk)
+	if err := r.Get(ctx, key, live); err != nil {
+		if !apierrors.IsNotFound(err) {
+			withResource(log, gvk, key.Name).Error(err, "Failed to get resource for deletion")
+		}
+		return nil
+	}
+	if owner := live.GetLabels()[NamespaceClassOwnedByKey]; owner != className {
+		withResource(log, gvk, key.Name).Info("Skipping deletion of resource not owned by the class", "owner", owner)
+		r.warnOnce(ns, "DeletionSkipped",
+			"%s '%s' is not owned by NamespaceClass '%s' and was not deleted", gvk.Kind, key.Name, className)
+		return nil
+	}
+	return live
+}
+
+// ensureNamespaceFinalizer adds the operator's finalizer to ns when
+// NamespaceFinalizer is enabled, so that the operator gets to release the
+// namespace before it is gone.
+func (r *NamespaceClassReconciler) ensureNamespaceFinalizer(ctx context.Context, ns *corev1.Namespace) error {
+	if !r.NamespaceFinalizer || r.DryRun || ns.DeletionTimestamp != nil ||
+		controllerutil.ContainsFinalizer(ns, NamespaceClassFinalizerKey) {
+		return nil
+	}
+	patch := client.MergeFrom(ns.DeepCopy())
+	controllerutil.AddFinalizer(ns, NamespaceClassFinalizerKey)
+	return r.Patch(ctx, ns, patch)
+}
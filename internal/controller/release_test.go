@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Release", func() {
+	removeClassLabel := func(ctx context.Context, r *controller.NamespaceClassReconciler, ns *corev1.Namespace) {
+		GinkgoHelper()
+		var live corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &live)).To(Succeed())
+		delete(live.Labels, controller.NamespaceClassNameKey)
+		Expect(r.Update(ctx, &live)).To(Succeed())
+	}
+
+	getNamespace := func(ctx context.Context, r *controller.NamespaceClassReconciler, name string) *corev1.Namespace {
+		GinkgoHelper()
+		var live corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: name}, &live)).To(Succeed())
+		return &live
+	}
+
+	It("should add a finalizer to namespaces the class was applied to", func() {
+		ns := newNamespace("finalized-ns", "finalized-class")
+		class := newNamespaceClass("finalized-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+		r.NamespaceFinalizer = true
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(getNamespace(ctx, r, ns.Name).Finalizers).To(ContainElement(controller.NamespaceClassFinalizerKey))
+	})
+
+	It("should delete injected resources when the class label is removed and cleanup is enabled", func() {
+		ns := newNamespace("unlabeled-cleanup-ns", "release-class")
+		setCleanupAnnotation(ns)
+		class := newNamespaceClass("release-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+		r.NamespaceFinalizer = true
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+
+		removeClassLabel(ctx, r, ns)
+		live := getNamespace(ctx, r, ns.Name)
+		Expect(controller.MapNamespaceToNamespaceClass(r, ctx, live)).To(ConsistOf(
+			controller.NamespaceKey(ns.Name),
+		))
+
+		_, err = r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+		live = getNamespace(ctx, r, ns.Name)
+		Expect(live.Finalizers).NotTo(ContainElement(controller.NamespaceClassFinalizerKey))
+		Expect(live.Annotations).NotTo(HaveKey(controller.NamespaceClassAppliedClassKey))
+	})
+
+	It("should keep injected resources when the class label is removed without cleanup", func() {
+		ns := newNamespace("unlabeled-keep-ns", "keep-class")
+		class := newNamespaceClass("keep-class", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		removeClassLabel(ctx, r, ns)
+		_, err = r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+		Expect(getNamespace(ctx, r, ns.Name).Annotations).NotTo(HaveKey(controller.NamespaceClassAppliedClassKey))
+	})
+
+	It("should release the namespace when its class is already gone", func() {
+		ns := newNamespace("gone-class-ns", "")
+		setCleanupAnnotation(ns)
+		ns.Annotations[controller.NamespaceClassAppliedClassKey] = "gone-class"
+		ns.Finalizers = []string{controller.NamespaceClassFinalizerKey}
+		r, _, ctx := setupTestReconciler(ns)
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getNamespace(ctx, r, ns.Name).Finalizers).To(BeEmpty())
+	})
+
+	It("should not block deletion of a namespace whose class is gone", func() {
+		now := metav1.Now()
+		ns := newNamespace("deleted-ns", "gone-class")
+		ns.DeletionTimestamp = &now
+		ns.Finalizers = []string{controller.NamespaceClassFinalizerKey}
+		r, _, ctx := setupTestReconciler(ns)
+		r.NamespaceFinalizer = true
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		err = r.Get(ctx, types.NamespacedName{Name: ns.Name}, &corev1.Namespace{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// Render returns the objects class injects into ns, as the reconciler would
+// apply them: the effective resources of the class, including inherited,
+// referenced and repeated ones and those of the additional classes of ns,
+// rendered for ns, labeled as owned by the class
+// and placed in their namespace, in apply order. Kinds are not resolved, so
+// cluster-scoped objects keep the namespace. Render only reads parent classes
+// and referenced ConfigMaps and writes nothing, so it can be used outside the
+// reconcile loop, e.g. with a client that serves manifests from files.
+func (r *NamespaceClassReconciler) Render(
+	ctx context.Context,
+	class *v1alpha1.NamespaceClass,
+	ns *corev1.Namespace,
+) ([]*unstructured.Unstructured, error) {
+	effective, err := r.effectiveClass(ctx, class)
+	if err != nil {
+		return nil, err
+	}
+	if r.tooManyResources(effective) {
+		return nil, fmt.Errorf("NamespaceClass %q defines %d resources, more than the limit of %d",
+			class.Name, len(effective.Spec.Resources), r.MaxResourcesPerClass)
+	}
+	composed, err := r.composedClass(ctx, ns, effective)
+	if err != nil {
+		return nil, err
+	}
+	if r.tooManyResources(composed) {
+		return nil, fmt.Errorf("NamespaceClass %q and the additional classes of namespace %q define %d resources, "+
+			"more than the limit of %d", class.Name, ns.Name, len(composed.Spec.Resources), r.MaxResourcesPerClass)
+	}
+	objs, _, err := r.buildResources(ctx, classLogger(ctx, class.Name, ns.Name), ns, composed)
+	return objs, err
+}
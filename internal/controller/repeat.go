@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// repeatValue matches the {{ .Value }} action of repeated resources.
+var repeatValue = regexp.MustCompile(`{{-?\s*\.Value\s*-?}}`)
+
+// expandRepeats returns class with every embedded resource that has the
+// NamespaceClassRepeatKey annotation replaced by one copy per listed value.
+// Resources are expanded before they are rendered for a namespace, so that
+// obsolete cleanup sees every copy by its name. A resource whose copies do not
+// have unique names is dropped with a warning Event on class.
+func (r *NamespaceClassReconciler) expandRepeats(class *v1alpha1.NamespaceClass) *v1alpha1.NamespaceClass {
+	var resources []runtime.RawExtension
+	repeated := false
+	for i, res := range class.Spec.Resources {
+		copies, ok, err := expandRepeat(res)
+		if !ok {
+			resources = append(resources, res)
+			continue
+		}
+		repeated = true
+		if err != nil {
+			r.Recorder.Eventf(class, corev1.EventTypeWarning, "InvalidResource",
+				"Resource %d of NamespaceClass '%s' is invalid: %v", i, class.Name, err)
+			continue
+		}
+		resources = append(resources, copies...)
+	}
+	if !repeated {
+		return class
+	}
+
+	expanded := class.DeepCopy()
+	expanded.Spec.Resources = resources
+	return expanded
+}
+
+// expandRepeat returns a copy of res for every value of its
+// NamespaceClassRepeatKey annotation, in which {{ .Value }} is replaced by the
+// value, and whether res is repeated at all. The annotation is removed from
+// the copies.
+func expandRepeat(res runtime.RawExtension) ([]runtime.RawExtension, bool, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(res.Raw); err != nil {
+		return nil, false, nil
+	}
+	values, ok := obj.GetAnnotations()[NamespaceClassRepeatKey]
+	if !ok {
+		return nil, false, nil
+	}
+
+	var copies []runtime.RawExtension
+	names := map[string]bool{}
+	for _, value := range strings.Split(values, ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		// The action sits inside a JSON string, so the value is escaped as one
+		quoted, err := json.Marshal(value)
+		if err != nil {
+			return nil, true, err
+		}
+		copied := &unstructured.Unstructured{}
+		if err := copied.UnmarshalJSON(repeatValue.ReplaceAllLiteral(res.Raw, quoted[1:len(quoted)-1])); err != nil {
+			return nil, true, err
+		}
+		if names[copied.GetName()] {
+			return nil, true, fmt.Errorf("repeated name %q is not unique, use {{ .Value }} in metadata.name", copied.GetName())
+		}
+		names[copied.GetName()] = true
+
+		annotations := copied.GetAnnotations()
+		delete(annotations, NamespaceClassRepeatKey)
+		copied.SetAnnotations(annotations)
+		raw, err := copied.MarshalJSON()
+		if err != nil {
+			return nil, true, err
+		}
+		copies = append(copies, runtime.RawExtension{Raw: raw})
+	}
+	return copies, true, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Repeated resources", func() {
+	repeatedTiers := func(values string) runtime.RawExtension {
+		return mustRawAnnotatedConfigMap("tier-{{ .Value }}",
+			map[string]string{controller.NamespaceClassRepeatKey: values},
+			map[string]string{"tier": "{{ .Value }}", "namespace": "{{ .Namespace }}"},
+		)
+	}
+
+	configMapsByName := func(ctx context.Context, c client.Client, namespace string) map[string]map[string]string {
+		byName := map[string]map[string]string{}
+		for _, cm := range listConfigMaps(c, ctx, namespace) {
+			Expect(cm.Annotations).NotTo(HaveKey(controller.NamespaceClassRepeatKey))
+			byName[cm.Name] = cm.Data
+		}
+		return byName
+	}
+
+	It("should expand a repeated resource into one object per value", func() {
+		ns := newNamespace("repeat-ns", "repeat-class")
+		class := newNamespaceClass("repeat-class", repeatedTiers("frontend, backend,db"))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(configMapsByName(ctx, r.Client, ns.Name)).To(Equal(map[string]map[string]string{
+			"tier-frontend": {"tier": "frontend", "namespace": ns.Name},
+			"tier-backend":  {"tier": "backend", "namespace": ns.Name},
+			"tier-db":       {"tier": "db", "namespace": ns.Name},
+		}))
+	})
+
+	It("should delete the copy of a value removed from the list as obsolete", func() {
+		ns := newNamespace("repeat-obsolete-ns", "repeat-obsolete-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		class := newNamespaceClass("repeat-obsolete-class", repeatedTiers("frontend,backend,db"))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(3))
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		persisted.Spec.Resources = []runtime.RawExtension{repeatedTiers("frontend,backend")}
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(configMapsByName(ctx, r.Client, ns.Name)).To(HaveLen(2))
+		Expect(configMapsByName(ctx, r.Client, ns.Name)).NotTo(HaveKey("tier-db"))
+	})
+
+	It("should skip a repeated resource whose copies have the same name", func() {
+		ns := newNamespace("repeat-clash-ns", "repeat-clash-class")
+		class := newNamespaceClass("repeat-clash-class",
+			mustRawAnnotatedConfigMap("tier",
+				map[string]string{controller.NamespaceClassRepeatKey: "frontend,backend"},
+				map[string]string{"tier": "{{ .Value }}"},
+			),
+			mustRawConfigMap("cfg", map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(configMapsByName(ctx, r.Client, ns.Name)).To(HaveLen(1))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(`repeated name "tier" is not unique`)))
+	})
+})
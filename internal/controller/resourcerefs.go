@@ -0,0 +1,206 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/validation"
+)
+
+// withReferencedResources returns a copy of class whose Resources also hold
+// the documents of its ResourcesYAML, the manifests of its ResourceRefs and
+// copies of its SecretRefs, so that every code path that works on
+// spec.resources picks them up. A reference that cannot be read, or a
+// ResourcesYAML that cannot be decoded, is reported with a warning Event on the
+// class and skipped, and returned as an error together with the rest, since
+// the resources are then incomplete.
+func (r *NamespaceClassReconciler) withReferencedResources(
+	ctx context.Context,
+	class *v1alpha1.NamespaceClass,
+) (*v1alpha1.NamespaceClass, error) {
+	if class.Spec.ResourcesYAML == "" && len(class.Spec.ResourceRefs) == 0 && len(class.Spec.SecretRefs) == 0 {
+		return class, nil
+	}
+	log := classLogger(ctx, class.Name, "")
+
+	var errs []error
+	expanded := class.DeepCopy()
+	if class.Spec.ResourcesYAML != "" {
+		resources, err := decodeManifests(class.Spec.ResourcesYAML)
+		if err != nil {
+			log.Error(err, "Failed to decode resourcesYAML")
+			r.Recorder.Eventf(class, corev1.EventTypeWarning, "InvalidResourcesYAML",
+				"Failed to decode spec.resourcesYAML: %v", err)
+			errs = append(errs, fmt.Errorf("NamespaceClass %q: spec.resourcesYAML: %w", class.Name, err))
+		}
+		expanded.Spec.Resources = append(expanded.Spec.Resources, resources...)
+		expanded.Spec.ResourcesYAML = ""
+	}
+	for _, ref := range class.Spec.ResourceRefs {
+		resources, err := r.readResourceRef(ctx, ref)
+		if err != nil {
+			log.Error(err, "Failed to read resource reference", "configMap", ref.Name, "key", ref.Key)
+			r.Recorder.Eventf(class, corev1.EventTypeWarning, "ResourceRefError",
+				"Failed to read resources from ConfigMap '%s': %v", ref.Name, err)
+			errs = append(errs, fmt.Errorf("NamespaceClass %q: resourceRef %q: %w", class.Name, ref.Name, err))
+			continue
+		}
+		expanded.Spec.Resources = append(expanded.Spec.Resources, resources...)
+	}
+	for _, ref := range class.Spec.SecretRefs {
+		secret, err := r.readSecretRef(ctx, ref)
+		if err != nil {
+			log.Error(err, "Failed to read secret reference", "secret", r.secretRefSource(ref))
+			r.Recorder.Eventf(class, corev1.EventTypeWarning, "SecretRefError",
+				"Failed to copy Secret '%s': %v", r.secretRefSource(ref), err)
+			errs = append(errs, fmt.Errorf("NamespaceClass %q: secretRef %q: %w", class.Name, r.secretRefSource(ref), err))
+			continue
+		}
+		expanded.Spec.Resources = append(expanded.Spec.Resources, secret)
+	}
+	return expanded, errors.Join(errs...)
+}
+
+type incompleteSpecKey struct{}
+
+// withIncompleteSpec returns a context under which the class being applied is
+// known to lack resources that could not be read. Nothing is deleted as
+// obsolete under it, since the missing resources may still be defined, and the
+// namespaces are not recorded as in sync.
+func withIncompleteSpec(ctx context.Context) context.Context {
+	return context.WithValue(ctx, incompleteSpecKey{}, true)
+}
+
+// incompleteSpec reports whether ctx was returned by withIncompleteSpec.
+func incompleteSpec(ctx context.Context) bool {
+	incomplete, _ := ctx.Value(incompleteSpecKey{}).(bool)
+	return incomplete
+}
+
+// readResourceRef decodes the manifests of the referenced ConfigMap.
+func (r *NamespaceClassReconciler) readResourceRef(ctx context.Context, ref v1alpha1.ResourceRef) ([]runtime.RawExtension, error) {
+	if r.OperatorNamespace == "" {
+		return nil, errors.New("the operator namespace is not known")
+	}
+
+	var cm corev1.ConfigMap
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: r.OperatorNamespace}, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("ConfigMap %s/%s not found", r.OperatorNamespace, ref.Name)
+		}
+		return nil, err
+	}
+
+	keys := []string{ref.Key}
+	if ref.Key == "" {
+		keys = make([]string, 0, len(cm.Data))
+		for key := range cm.Data {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+	}
+
+	var resources []runtime.RawExtension
+	for _, key := range keys {
+		data, ok := cm.Data[key]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", key)
+		}
+		decoded, err := decodeManifests(data)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		resources = append(resources, decoded...)
+	}
+	return resources, nil
+}
+
+// templateValues returns the data of the ConfigMap that the valuesRef of class
+// names, or nil when the class has none.
+func (r *NamespaceClassReconciler) templateValues(ctx context.Context, class *v1alpha1.NamespaceClass) (map[string]string, error) {
+	if class.Spec.ValuesRef == nil {
+		return nil, nil
+	}
+	if r.OperatorNamespace == "" {
+		return nil, errors.New("the operator namespace is not known")
+	}
+
+	var cm corev1.ConfigMap
+	if err := r.Get(ctx, types.NamespacedName{Name: class.Spec.ValuesRef.Name, Namespace: r.OperatorNamespace}, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("ConfigMap %s/%s not found", r.OperatorNamespace, class.Spec.ValuesRef.Name)
+		}
+		return nil, err
+	}
+	return cm.Data, nil
+}
+
+// decodeManifests splits a YAML or JSON stream into one raw resource per
+// document, dropping empty documents, with the decoder the webhook validates
+// the stream with, see validation.DecodeDocuments.
+func decodeManifests(data string) ([]runtime.RawExtension, error) {
+	objs, err := validation.DecodeDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]runtime.RawExtension, 0, len(objs))
+	for _, obj := range objs {
+		raw, err := obj.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, runtime.RawExtension{Raw: raw})
+	}
+	return resources, nil
+}
+
+// mapResourceRefToNamespaceClasses enqueues the classes that reference a
+// ConfigMap of the operator's namespace in their resourceRefs or valuesRef.
+func (r *NamespaceClassReconciler) mapResourceRefToNamespaceClasses(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetNamespace() != r.OperatorNamespace {
+		return nil
+	}
+
+	var classes v1alpha1.NamespaceClassList
+	if err := r.List(ctx, &classes); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Failed to list NamespaceClasses for ConfigMap", "configMap", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, class := range classes.Items {
+		if slices.ContainsFunc(class.Spec.ResourceRefs, func(ref v1alpha1.ResourceRef) bool {
+			return ref.Name == obj.GetName()
+		}) || (class.Spec.ValuesRef != nil && class.Spec.ValuesRef.Name == obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: class.Name}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+const operatorNamespace = "namespaceclass-operator-system"
+
+const referencedManifests = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: from-ref
+data:
+  foo: bar
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: from-ref
+`
+
+var _ = Describe("ResourceRefs", func() {
+	It("should inject the resources of a referenced ConfigMap next to inline ones", func() {
+		ns := newNamespace("ref-ns", "ref-class")
+		class := newNamespaceClass("ref-class", mustRawConfigMap("inline", map[string]string{"foo": "bar"}))
+		class.Spec.ResourceRefs = []v1alpha1.ResourceRef{{Name: "shared", Key: "manifests.yaml"}}
+		source := newInjectedConfigMap("shared", operatorNamespace, map[string]string{"manifests.yaml": referencedManifests})
+		r, _, ctx := setupTestReconciler(ns, class, source)
+		r.OperatorNamespace = operatorNamespace
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(2))
+		var sa corev1.ServiceAccount
+		Expect(r.Get(ctx, types.NamespacedName{Name: "from-ref", Namespace: ns.Name}, &sa)).To(Succeed())
+
+		By("changing the referenced ConfigMap")
+		source.Data["manifests.yaml"] = `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "from-ref"}, "data": {"foo": "baz"}}`
+		Expect(r.Update(ctx, source)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "from-ref", Namespace: ns.Name}, &cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("foo", "baz"))
+	})
+
+	It("should apply inline resources and report a missing referenced ConfigMap", func() {
+		ns := newNamespace("missing-ref-ns", "missing-ref-class")
+		class := newNamespaceClass("missing-ref-class", mustRawConfigMap("inline", map[string]string{"foo": "bar"}))
+		class.Spec.ResourceRefs = []v1alpha1.ResourceRef{{Name: "absent"}}
+		r, _, ctx := setupTestReconciler(ns, class)
+		r.OperatorNamespace = operatorNamespace
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).To(MatchError(ContainSubstring(`resourceRef "absent"`)))
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("ResourceRefError")))
+	})
+
+	It("should not delete or forget the resources of a referenced ConfigMap that cannot be read", func() {
+		ns := newNamespace("unreadable-ref-ns", "unreadable-ref-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		class := newNamespaceClass("unreadable-ref-class", mustRawConfigMap("inline", map[string]string{"foo": "bar"}))
+		class.Spec.ResourceRefs = []v1alpha1.ResourceRef{{Name: "absent"}}
+		class.Status.LastAppliedResources = []runtime.RawExtension{
+			mustRawConfigMap("inline", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("from-ref", map[string]string{"foo": "bar"}),
+		}
+		fromRef := newInjectedConfigMap("from-ref", ns.Name, map[string]string{"foo": "bar"})
+		fromRef.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class.Name}
+		r, _, ctx := setupTestReconciler(ns, class, fromRef)
+		r.OperatorNamespace = operatorNamespace
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).To(HaveOccurred())
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "from-ref", Namespace: ns.Name}, &cm)).To(Succeed())
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.LastAppliedResources).To(HaveLen(2))
+	})
+
+	It("should map a referenced ConfigMap to the classes that use it", func() {
+		referencing := newNamespaceClass("referencing-class")
+		referencing.Spec.ResourceRefs = []v1alpha1.ResourceRef{{Name: "shared"}}
+		other := newNamespaceClass("other-class")
+		r, _, ctx := setupTestReconciler(referencing, other)
+		r.OperatorNamespace = operatorNamespace
+
+		source := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: operatorNamespace}}
+		Expect(controller.MapResourceRefToNamespaceClasses(r, ctx, source)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "referencing-class"}},
+		))
+
+		elsewhere := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default"}}
+		Expect(controller.MapResourceRefToNamespaceClasses(r, ctx, elsewhere)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("ResourcesYAML", func() {
+	It("should inject the documents of resourcesYAML next to inline resources", func() {
+		ns := newNamespace("yaml-ns", "yaml-class")
+		class := newNamespaceClass("yaml-class", mustRawConfigMap("inline", map[string]string{"foo": "bar"}))
+		class.Spec.ResourcesYAML = referencedManifests
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "inline", Namespace: ns.Name}, &cm)).To(Succeed())
+		Expect(r.Get(ctx, types.NamespacedName{Name: "from-ref", Namespace: ns.Name}, &cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("foo", "bar"))
+		var sa corev1.ServiceAccount
+		Expect(r.Get(ctx, types.NamespacedName{Name: "from-ref", Namespace: ns.Name}, &sa)).To(Succeed())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.AppliedResourceCount).To(Equal(3))
+		Expect(persisted.Status.LastAppliedResources).To(HaveLen(3))
+	})
+
+	It("should delete a document dropped from resourcesYAML as obsolete", func() {
+		ns := newNamespace("yaml-obsolete-ns", "yaml-obsolete-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		class := newNamespaceClass("yaml-obsolete-class")
+		class.Spec.ResourcesYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: deployer
+`
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		persisted.Spec.ResourcesYAML = "apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: deployer\n"
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+		var sa corev1.ServiceAccount
+		Expect(r.Get(ctx, types.NamespacedName{Name: "deployer", Namespace: ns.Name}, &sa)).To(Succeed())
+	})
+
+	It("should report a resourcesYAML that cannot be decoded", func() {
+		ns := newNamespace("yaml-broken-ns", "yaml-broken-class")
+		class := newNamespaceClass("yaml-broken-class", mustRawConfigMap("inline", map[string]string{"foo": "bar"}))
+		class.Spec.ResourcesYAML = "kind: ConfigMap\nmetadata: [broken"
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).To(MatchError(ContainSubstring("spec.resourcesYAML")))
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		Expect(events).To(ContainElement(ContainSubstring("InvalidResourcesYAML")))
+	})
+})
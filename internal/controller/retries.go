@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// errRetriesExhausted is the error of a resource that is no longer applied
+// because it failed as often as the retry limit allows.
+var errRetriesExhausted = errors.New("retry limit reached")
+
+// resourceError is the error of applying a single resource, so that failures
+// can be counted per resource.
+type resourceError struct {
+	resource string
+	err      error
+}
+
+func (e *resourceError) Error() string { return e.err.Error() }
+
+func (e *resourceError) Unwrap() error { return e.err }
+
+// failedResources splits the error of applying a class to a namespace into
+// the messages of the resources that failed, by resourceRef. Resources that ran
+// out of retries are left out. other is true if err holds errors that are not
+// about a single resource, e.g. a panic.
+func failedResources(err error) (failed map[string]string, other bool) {
+	failed = map[string]string{}
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	for _, err := range errs {
+		var re *resourceError
+		switch {
+		case errors.Is(err, errRetriesExhausted):
+		case errors.As(err, &re):
+			failed[re.resource] = re.Error()
+		default:
+			other = true
+		}
+	}
+	return failed, other
+}
+
+// retriesExhausted reports whether obj failed to apply to ns as often as
+// MaxRetries allows for the current generation of class.
+func (r *NamespaceClassReconciler) retriesExhausted(class *v1alpha1.NamespaceClass, ns string, obj *unstructured.Unstructured) bool {
+	if r.MaxRetries <= 0 {
+		return false
+	}
+	return slices.ContainsFunc(class.Status.ResourceFailures, func(f v1alpha1.ResourceFailure) bool {
+		return f.Failed && f.Generation == class.Generation && f.Namespace == ns && f.Resource == resourceRef(obj)
+	})
+}
+
+// recordAttempt counts an attempt to apply class to ns that ended with err in
+// the ResourceFailures of class. Resources that failed again count one more
+// attempt, and the ones that reach MaxRetries are marked failed and reported
+// with a warning Event. Resources of ns that succeeded start over. It returns
+// whether err is worth retrying, i.e. whether something failed that has
+// retries left.
+func (r *NamespaceClassReconciler) recordAttempt(class *v1alpha1.NamespaceClass, ns string, err error) bool {
+	if r.MaxRetries <= 0 {
+		return err != nil
+	}
+
+	failed, other := map[string]string{}, false
+	if err != nil {
+		failed, other = failedResources(err)
+	}
+
+	retry := other
+	count := func(f v1alpha1.ResourceFailure, msg string) v1alpha1.ResourceFailure {
+		f.Attempts++
+		f.Message = msg
+		if f.Attempts < r.MaxRetries {
+			retry = true
+			return f
+		}
+		f.Failed = true
+		r.Recorder.Eventf(class, corev1.EventTypeWarning, "RetriesExhausted",
+			"Giving up on %s in namespace '%s' after %d failed attempts: %s", f.Resource, ns, f.Attempts, msg)
+		return f
+	}
+
+	var failures []v1alpha1.ResourceFailure
+	for _, f := range class.Status.ResourceFailures {
+		if f.Generation != class.Generation {
+			// Attempts are counted afresh for a changed class
+			continue
+		}
+		if f.Namespace != ns || f.Failed {
+			failures = append(failures, f)
+			continue
+		}
+		if msg, ok := failed[f.Resource]; ok {
+			delete(failed, f.Resource)
+			failures = append(failures, count(f, msg))
+		}
+	}
+	for resource, msg := range failed {
+		failures = append(failures, count(v1alpha1.ResourceFailure{
+			Namespace: ns, Resource: resource, Generation: class.Generation,
+		}, msg))
+	}
+	slices.SortFunc(failures, func(a, b v1alpha1.ResourceFailure) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Resource, b.Resource))
+	})
+	class.Status.ResourceFailures = failures
+	return retry
+}
+
+// pruneResourceFailures drops the failures of namespaces that no longer use
+// class, or of an earlier generation of class.
+func (r *NamespaceClassReconciler) pruneResourceFailures(class *v1alpha1.NamespaceClass, namespaces []string) {
+	var failures []v1alpha1.ResourceFailure
+	for _, f := range class.Status.ResourceFailures {
+		if r.MaxRetries > 0 && f.Generation == class.Generation && slices.Contains(namespaces, f.Namespace) {
+			failures = append(failures, f)
+		}
+	}
+	class.Status.ResourceFailures = failures
+}
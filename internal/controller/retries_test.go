@@ -0,0 +1,178 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Retry limit", func() {
+	var attempts int
+
+	// rejectConfigMap fails every write of the ConfigMap named name and counts
+	// the attempts.
+	rejectConfigMap := func(name string) interceptor.Funcs {
+		reject := func(obj client.Object) error {
+			if obj.GetName() != name {
+				return nil
+			}
+			if _, ok := obj.(*corev1.ConfigMap); !ok && obj.GetObjectKind().GroupVersionKind().Kind != "ConfigMap" {
+				return nil
+			}
+			attempts++
+			return errors.New("rejected by admission")
+		}
+		return interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if err := reject(obj); err != nil {
+					return err
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if err := reject(obj); err != nil {
+					return err
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if err := reject(obj); err != nil {
+					return err
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}
+	}
+
+	getClass := func(ctx context.Context, c client.Client, name string) *v1alpha1.NamespaceClass {
+		var persisted v1alpha1.NamespaceClass
+		Expect(c.Get(ctx, types.NamespacedName{Name: name}, &persisted)).To(Succeed())
+		return &persisted
+	}
+
+	BeforeEach(func() {
+		attempts = 0
+	})
+
+	It("should stop retrying a resource that keeps failing and apply the rest", func() {
+		class := newNamespaceClass("retry-limit-class",
+			mustRawConfigMap("broken", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("fine", map[string]string{"foo": "bar"}),
+		)
+		ns := newNamespace("retry-limit-ns", class.Name)
+		r, _, ctx := setupTestReconcilerWithInterceptor(rejectConfigMap("broken"), class, ns)
+		r.MaxRetries = 2
+		requests := r.WithRequestQueue(10)
+		req := controller.NamespaceRequest(class.Name, ns.Name)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconcileQueued(ctx, r, requests)).To(HaveKey(req))
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+
+		persisted := getClass(ctx, r.Client, class.Name)
+		Expect(persisted.Status.ResourceFailures).To(HaveLen(1))
+		failure := persisted.Status.ResourceFailures[0]
+		Expect(failure.Namespace).To(Equal(ns.Name))
+		Expect(failure.Resource).To(Equal("ConfigMap/broken"))
+		Expect(failure.Attempts).To(Equal(1))
+		Expect(failure.Failed).To(BeFalse())
+		Expect(failure.Message).To(ContainSubstring("rejected by admission"))
+
+		By("exhausting the retries of the broken resource")
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts).To(Equal(2))
+		Expect(requests).To(HaveLen(1))
+		Expect((<-requests).Object).To(Equal(requestFor(class)))
+
+		persisted = getClass(ctx, r.Client, class.Name)
+		Expect(persisted.Status.ResourceFailures).To(HaveLen(1))
+		Expect(persisted.Status.ResourceFailures[0].Attempts).To(Equal(2))
+		Expect(persisted.Status.ResourceFailures[0].Failed).To(BeTrue())
+
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		Expect(events).To(ContainElement(SatisfyAll(
+			ContainSubstring("RetriesExhausted"),
+			ContainSubstring("ConfigMap/broken"),
+		)))
+
+		By("no longer applying the broken resource")
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconcileQueued(ctx, r, requests)).To(BeEmpty())
+		Expect(attempts).To(Equal(2))
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+
+		persisted = getClass(ctx, r.Client, class.Name)
+		Expect(persisted.Status.ResourceFailures).To(HaveLen(1))
+		Expect(persisted.Status.ResourceFailures[0].Failed).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(persisted.Status.Conditions, v1alpha1.ConditionReady)).To(BeTrue())
+		Expect(meta.FindStatusCondition(persisted.Status.Conditions, v1alpha1.ConditionReady).Message).
+			To(ContainSubstring("retry limit reached"))
+	})
+
+	It("should retry a failed resource again once the class changes", func() {
+		class := newNamespaceClass("retry-change-class", mustRawConfigMap("broken", map[string]string{"foo": "bar"}))
+		class.Generation = 2
+		class.Status.ResourceFailures = []v1alpha1.ResourceFailure{{
+			Namespace: "retry-change-ns", Resource: "ConfigMap/broken", Attempts: 3, Generation: 1, Failed: true,
+		}}
+		ns := newNamespace("retry-change-ns", class.Name)
+		r, _, ctx := setupTestReconcilerWithInterceptor(rejectConfigMap("broken"), class, ns)
+		r.MaxRetries = 3
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts).To(Equal(1))
+
+		persisted := getClass(ctx, r.Client, class.Name)
+		Expect(persisted.Status.ResourceFailures).To(HaveLen(1))
+		Expect(persisted.Status.ResourceFailures[0].Attempts).To(Equal(1))
+		Expect(persisted.Status.ResourceFailures[0].Generation).To(Equal(int64(2)))
+		Expect(persisted.Status.ResourceFailures[0].Failed).To(BeFalse())
+	})
+
+	It("should not track failures without a retry limit", func() {
+		class := newNamespaceClass("retry-unlimited-class", mustRawConfigMap("broken", map[string]string{"foo": "bar"}))
+		ns := newNamespace("retry-unlimited-ns", class.Name)
+		r, _, ctx := setupTestReconcilerWithInterceptor(rejectConfigMap("broken"), class, ns)
+		req := controller.NamespaceRequest(class.Name, ns.Name)
+
+		for range 3 {
+			_, err := r.Reconcile(ctx, req)
+			Expect(err).To(MatchError(ContainSubstring("rejected by admission")))
+		}
+		Expect(getClass(ctx, r.Client, class.Name).Status.ResourceFailures).To(BeEmpty())
+	})
+})
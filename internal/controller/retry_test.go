@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+var _ = Describe("Update conflicts", func() {
+	var updates int
+
+	failFirstUpdate := func(failure error) interceptor.Funcs {
+		return interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if obj.GetName() != "cfg" {
+					return c.Update(ctx, obj, opts...)
+				}
+				updates++
+				if updates == 1 {
+					return failure
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		updates = 0
+	})
+
+	It("should retry an update that hit a conflict", func() {
+		ns := newNamespace("conflict-ns", "conflict-class")
+		class := newNamespaceClass("conflict-class", mustRawConfigMap("cfg", map[string]string{"foo": "new"}))
+		existing := newInjectedConfigMap("cfg", ns.Name, map[string]string{"foo": "old"})
+		conflict := apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "cfg", errors.New("stale"))
+		r, _, ctx := setupTestReconcilerWithInterceptor(failFirstUpdate(conflict), ns, class, existing)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(updates).To(Equal(2))
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Data).To(HaveKeyWithValue("foo", "new"))
+	})
+
+	It("should not retry other errors", func() {
+		ns := newNamespace("forbidden-ns", "forbidden-class")
+		class := newNamespaceClass("forbidden-class", mustRawConfigMap("cfg", map[string]string{"foo": "new"}))
+		existing := newInjectedConfigMap("cfg", ns.Name, map[string]string{"foo": "old"})
+		forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "cfg", errors.New("denied"))
+		r, _, ctx := setupTestReconcilerWithInterceptor(failFirstUpdate(forbidden), ns, class, existing)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(updates).To(Equal(1))
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(persisted.Status.Conditions, v1alpha1.ConditionReady)).To(BeTrue())
+	})
+})
@@ -0,0 +1,210 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// rollout tracks one application of a class to its namespaces. The class
+// reconcile that starts it enqueues a request per namespace, see
+// namespaceRequest, so that every namespace is applied, and retried, on its
+// own. The namespace request that finishes the rollout enqueues the class
+// again, which reports the outcome in the status of the class.
+type rollout struct {
+	mu sync.Mutex
+
+	// forced re-applies every resource for the force-reconcile value forceValue.
+	forced     bool
+	forceValue string
+	// managed counts the namespaces of the class, bound are those that are not
+	// being deleted, and released are the cluster-scoped objects that only the
+	// terminating ones needed.
+	managed  int
+	bound    []string
+	released []string
+	// pending are the namespaces that were not applied yet.
+	pending map[string]bool
+	// results are the outcomes of the namespaces that were applied.
+	results map[string]namespaceResult
+	// clusterApplied are the cluster-scoped objects that were applied.
+	clusterApplied map[string]bool
+	// stale is set when the class was reconciled again before the rollout was
+	// done, so that it is applied once more afterwards.
+	stale bool
+}
+
+// namespaceResult is the outcome of applying a class to one namespace.
+type namespaceResult struct {
+	changes []string
+	err     error
+}
+
+// done reports whether every namespace of the rollout was applied.
+func (ro *rollout) done() bool {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	return len(ro.pending) == 0
+}
+
+// markStale marks the rollout stale unless it is done, and reports whether it
+// did.
+func (ro *rollout) markStale() bool {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	if len(ro.pending) == 0 {
+		return false
+	}
+	ro.stale = true
+	return true
+}
+
+// finish records the outcome of ns, nil if the class was not applied to it,
+// and the cluster-scoped objects applied along with it. It reports whether ns
+// was the last namespace of the rollout.
+func (ro *rollout) finish(ns string, result *namespaceResult, clusterApplied map[string]bool) bool {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	if !ro.pending[ns] {
+		return false
+	}
+	delete(ro.pending, ns)
+	if result != nil {
+		ro.results[ns] = *result
+	}
+	maps.Copy(ro.clusterApplied, clusterApplied)
+	return len(ro.pending) == 0
+}
+
+// currentRollout returns the rollout of the class named className, or nil.
+func (r *NamespaceClassReconciler) currentRollout(className string) *rollout {
+	r.rolloutsMu.Lock()
+	defer r.rolloutsMu.Unlock()
+	return r.rollouts[className]
+}
+
+// rolloutFor returns the rollout of the class named className that still has
+// to apply the class to ns, or nil.
+func (r *NamespaceClassReconciler) rolloutFor(className, ns string) *rollout {
+	ro := r.currentRollout(className)
+	if ro == nil {
+		return nil
+	}
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	if !ro.pending[ns] {
+		return nil
+	}
+	return ro
+}
+
+// forgetRollout drops the rollout of the class named className.
+func (r *NamespaceClassReconciler) forgetRollout(className string) {
+	r.rolloutsMu.Lock()
+	defer r.rolloutsMu.Unlock()
+	delete(r.rollouts, className)
+}
+
+// startRollout starts applying class to its namespaces. Without a manager to
+// enqueue the namespace requests to, e.g. in tests, they are reconciled right
+// away, one after another, so the rollout is done when it returns.
+func (r *NamespaceClassReconciler) startRollout(ctx context.Context, log logr.Logger, class *v1alpha1.NamespaceClass) (*rollout, error) {
+	if err := r.startApplying(ctx, class); err != nil {
+		log.Error(err, "Failed to update NamespaceClass status")
+		return nil, err
+	}
+
+	ro := &rollout{pending: map[string]bool{}, results: map[string]namespaceResult{}, clusterApplied: map[string]bool{}}
+	if forceRequested(class) {
+		ro.forced, ro.forceValue = true, class.Annotations[NamespaceClassForceReconcileKey]
+		log.Info("Forcing a full re-apply", "forceReconcile", ro.forceValue)
+		r.Recorder.Eventf(class, corev1.EventTypeNormal, "ForceReconcile",
+			"Re-applying every resource to every namespace for %s=%s", NamespaceClassForceReconcileKey, ro.forceValue)
+		if len(class.Status.ResourceFailures) > 0 {
+			// Resources that ran out of retries get another chance too
+			stored := class.DeepCopy()
+			stored.Status.ResourceFailures = nil
+			if err := r.Status().Update(ctx, stored); err != nil {
+				log.Error(err, "Failed to update NamespaceClass status")
+				return nil, err
+			}
+			class.Status.ResourceFailures = nil
+			class.ResourceVersion = stored.ResourceVersion
+		}
+	}
+
+	err := r.forEachNamespaceOfClass(ctx, class.Name, class.Spec.NamespaceSelector, func(namespaces []corev1.Namespace) error {
+		ro.managed += len(namespaces)
+		ro.bound = append(ro.bound, boundNamespaces(namespaces)...)
+		for _, ns := range namespaces {
+			if isTerminating(&ns) {
+				// Creates would be refused; only the shared objects it alone needed are released
+				log.V(1).Info("Skipping terminating namespace", logKeyNamespace, ns.Name)
+				if !r.DryRun {
+					gone, err := r.releaseClusterScoped(ctx, log.WithValues(logKeyNamespace, ns.Name), &ns, class)
+					if err != nil {
+						log.Error(err, "Failed to release cluster-scoped resources", logKeyNamespace, ns.Name)
+					}
+					ro.released = append(ro.released, gone...)
+				}
+				continue
+			}
+			ro.pending[ns.Name] = true
+		}
+		return nil
+	})
+	if err != nil {
+		r.failPhase(ctx, log, class)
+		return nil, err
+	}
+
+	r.rolloutsMu.Lock()
+	if r.rollouts == nil {
+		r.rollouts = map[string]*rollout{}
+	}
+	r.rollouts[class.Name] = ro
+	r.rolloutsMu.Unlock()
+
+	for _, ns := range slices.Sorted(maps.Keys(ro.pending)) {
+		if ctx.Err() != nil {
+			log.Info("Shutting down; not applying to further namespaces")
+			r.forgetRollout(class.Name)
+			r.failPhase(ctx, log, class)
+			return nil, fmt.Errorf("shutting down: %w", ctx.Err())
+		}
+		req := namespaceRequest(class.Name, ns)
+		if r.requests != nil {
+			r.enqueue(ctx, req)
+			continue
+		}
+		if _, err := r.reconcileNamespaceRequest(ctx, req); err != nil && ctx.Err() != nil {
+			r.forgetRollout(class.Name)
+			r.failPhase(ctx, log, class)
+			return nil, err
+		}
+	}
+	return ro, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// validateSchemas checks every embedded resource of class against the OpenAPI
+// schema of its kind and returns a message per invalid resource. Resources that
+// cannot be decoded are reported by buildResources instead, and kinds without
+// a schema, e.g. of CRDs installed after startup, are not checked.
+func (r *NamespaceClassReconciler) validateSchemas(class *v1alpha1.NamespaceClass) []string {
+	var problems []string
+	for i, res := range class.Spec.Resources {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(res.Raw); err != nil {
+			continue
+		}
+		_, err := r.TypeConverter.ObjectToTyped(obj)
+		if err == nil || isMissingSchema(err) {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("resource %d (%s): %v", i, resourceRef(obj), err))
+	}
+	return problems
+}
+
+// isMissingSchema reports whether err was returned for a kind the type
+// converter has no schema for. The converter does not export its error type.
+func isMissingSchema(err error) bool {
+	return strings.HasPrefix(err.Error(), "no corresponding type for ")
+}
+
+// setResourcesValidCondition records the result of validateSchemas on class.
+// The condition is only maintained when schema validation is enabled.
+func (r *NamespaceClassReconciler) setResourcesValidCondition(class *v1alpha1.NamespaceClass) {
+	if r.TypeConverter == nil {
+		return
+	}
+	cond := metav1.Condition{
+		Type:               v1alpha1.ConditionResourcesValid,
+		Status:             metav1.ConditionTrue,
+		Reason:             v1alpha1.ReasonSchemaValid,
+		Message:            "All resources match the schemas of their kinds",
+		ObservedGeneration: class.Generation,
+	}
+	if problems := r.validateSchemas(class); len(problems) > 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = v1alpha1.ReasonSchemaInvalid
+		cond.Message = conditionMessage(problems)
+	}
+	meta.SetStatusCondition(&class.Status.Conditions, cond)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// configMapSchemas is a minimal OpenAPI schema of ConfigMaps, as served by the
+// API server.
+const configMapSchemas = `{
+	"io.k8s.api.core.v1.ConfigMap": {
+		"type": "object",
+		"x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "ConfigMap"}],
+		"properties": {
+			"apiVersion": {"type": "string"},
+			"kind": {"type": "string"},
+			"metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+			"data": {"type": "object", "additionalProperties": {"type": "string"}}
+		}
+	},
+	"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"creationTimestamp": {"type": "string"},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"annotations": {"type": "object", "additionalProperties": {"type": "string"}}
+		}
+	}
+}`
+
+var _ = Describe("Schema validation", func() {
+	var converter managedfields.TypeConverter
+
+	BeforeEach(func() {
+		var schemas map[string]*spec.Schema
+		Expect(json.Unmarshal([]byte(configMapSchemas), &schemas)).To(Succeed())
+		var err error
+		converter, err = managedfields.NewTypeConverter(schemas, false)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	resourcesValid := func(class *v1alpha1.NamespaceClass, objs ...runtime.RawExtension) *metav1.Condition {
+		GinkgoHelper()
+		class.Spec.Resources = objs
+		ns := newNamespace(class.Name+"-ns", class.Name)
+		r, _, ctx := setupTestReconciler(ns, class)
+		r.TypeConverter = converter
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		return meta.FindStatusCondition(persisted.Status.Conditions, v1alpha1.ConditionResourcesValid)
+	}
+
+	It("should accept resources that match their schema", func() {
+		cond := resourcesValid(newNamespaceClass("schema-valid"),
+			mustRawConfigMap("cfg", map[string]string{"foo": "bar"}),
+			mustRawWidget("no-schema"),
+		)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(v1alpha1.ReasonSchemaValid))
+	})
+
+	It("should report a structurally invalid ConfigMap in the status", func() {
+		invalid := runtime.RawExtension{Raw: []byte(
+			`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "nested"}, "data": {"key": {"not": "a string"}}}`,
+		)}
+		cond := resourcesValid(newNamespaceClass("schema-invalid"),
+			mustRawConfigMap("cfg", map[string]string{"foo": "bar"}),
+			invalid,
+		)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(v1alpha1.ReasonSchemaInvalid))
+		Expect(cond.Message).To(HavePrefix("resource 1 (ConfigMap/nested): "))
+		Expect(cond.Message).To(ContainSubstring(".data.key"))
+	})
+
+	It("should not set the condition without schema validation", func() {
+		ns := newNamespace("schema-off-ns", "schema-off")
+		class := newNamespaceClass("schema-off", mustRawConfigMap("cfg", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(meta.FindStatusCondition(persisted.Status.Conditions, v1alpha1.ConditionResourcesValid)).To(BeNil())
+	})
+})
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/base64"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var secretGroupKind = schema.GroupKind{Kind: "Secret"}
+
+// foldStringData moves the stringData of a Secret into its base64 encoded data,
+// the same way the API server does on write. stringData is write-only: a live
+// Secret never has it, so keeping it in the desired object would make the
+// Secret look drifted forever and lose its values on the Get/Update round-trip
+// of upsert. Like on the server, stringData wins over data for the same key.
+func foldStringData(obj *unstructured.Unstructured) error {
+	if obj.GroupVersionKind().GroupKind() != secretGroupKind {
+		return nil
+	}
+	stringData, found, err := unstructured.NestedStringMap(obj.Object, "stringData")
+	if err != nil || !found {
+		return err
+	}
+	data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = map[string]string{}
+	}
+	for key, value := range stringData {
+		data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	unstructured.RemoveNestedField(obj.Object, "stringData")
+	return unstructured.SetNestedStringMap(obj.Object, data, "data")
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Secrets", func() {
+	It("should keep stringData values across reconciles", func() {
+		ns := newNamespace("secret-ns", "secret-class")
+		class := newNamespaceClass("secret-class", mustRawSecret("creds",
+			map[string]string{"user": base64.StdEncoding.EncodeToString([]byte("admin"))},
+			map[string]string{"password": "s3cret"},
+		))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		for range 2 {
+			_, err := r.Reconcile(ctx, requestFor(class))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		var secret corev1.Secret
+		Expect(r.Get(ctx, types.NamespacedName{Name: "creds", Namespace: ns.Name}, &secret)).To(Succeed())
+		Expect(secret.StringData).To(BeEmpty())
+		Expect(secret.Data).To(HaveKeyWithValue("user", []byte("admin")))
+		Expect(secret.Data).To(HaveKeyWithValue("password", []byte("s3cret")))
+	})
+
+	It("should not update an unchanged Secret", func() {
+		ns := newNamespace("secret-sync-ns", "secret-sync-class")
+		class := newNamespaceClass("secret-sync-class", mustRawSecret("creds", nil, map[string]string{"password": "s3cret"}))
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		var before corev1.Secret
+		Expect(r.Get(ctx, types.NamespacedName{Name: "creds", Namespace: ns.Name}, &before)).To(Succeed())
+
+		// Reconciling the namespace checks the live Secret for drift
+		_, err = r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		var after corev1.Secret
+		Expect(r.Get(ctx, types.NamespacedName{Name: "creds", Namespace: ns.Name}, &after)).To(Succeed())
+		Expect(after.ResourceVersion).To(Equal(before.ResourceVersion))
+	})
+})
+
+func mustRawSecret(name string, data, stringData map[string]string) runtime.RawExtension {
+	secret := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": name},
+	}
+	if data != nil {
+		secret["data"] = data
+	}
+	if stringData != nil {
+		secret["stringData"] = stringData
+	}
+	raw, err := json.Marshal(secret)
+	Expect(err).NotTo(HaveOccurred())
+	return runtime.RawExtension{Raw: raw}
+}
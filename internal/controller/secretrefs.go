@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// secretRefSource returns the namespace and name of the Secret ref copies.
+func (r *NamespaceClassReconciler) secretRefSource(ref v1alpha1.SecretRef) types.NamespacedName {
+	if ref.Namespace == "" {
+		return types.NamespacedName{Namespace: r.OperatorNamespace, Name: ref.Name}
+	}
+	return types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+}
+
+// readSecretRef returns the manifest of the copy of the referenced Secret. The
+// copy keeps the type and data of its source and records the source in
+// NamespaceClassSourceSecretKey. Only Secrets of the operator's namespace are
+// copied. The source is read from the API server, so that the cache does not
+// have to hold the Secrets of the cluster.
+func (r *NamespaceClassReconciler) readSecretRef(ctx context.Context, ref v1alpha1.SecretRef) (runtime.RawExtension, error) {
+	source := r.secretRefSource(ref)
+	if r.OperatorNamespace == "" {
+		return runtime.RawExtension{}, errors.New("the operator namespace is not known")
+	}
+	if source.Namespace != r.OperatorNamespace {
+		return runtime.RawExtension{}, fmt.Errorf("Secrets can only be copied from the operator namespace %q", r.OperatorNamespace)
+	}
+
+	var reader client.Reader = r.Client
+	if r.APIReader != nil {
+		reader = r.APIReader
+	}
+	var secret corev1.Secret
+	if err := reader.Get(ctx, source, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return runtime.RawExtension{}, fmt.Errorf("Secret %s not found", source)
+		}
+		return runtime.RawExtension{}, err
+	}
+
+	name := ref.TargetName
+	if name == "" {
+		name = ref.Name
+	}
+	cp := corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{NamespaceClassSourceSecretKey: source.String()},
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+	raw, err := json.Marshal(&cp)
+	if err != nil {
+		return runtime.RawExtension{}, err
+	}
+	return runtime.RawExtension{Raw: raw}, nil
+}
+
+// isSecretCopy reports whether obj is the copy of a Secret of spec.secretRefs.
+func isSecretCopy(obj *unstructured.Unstructured) bool {
+	return obj.GetKind() == "Secret" && obj.GetAnnotations()[NamespaceClassSourceSecretKey] != ""
+}
+
+// withoutCopiedSecretData returns resources with the data of Secret copies
+// removed, so that the status of a class, which only needs to know what was
+// applied, does not hold the contents of Secrets of other namespaces.
+func withoutCopiedSecretData(resources []runtime.RawExtension) []runtime.RawExtension {
+	out := make([]runtime.RawExtension, 0, len(resources))
+	for _, res := range resources {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(res.Raw); err != nil || obj.GetAnnotations()[NamespaceClassSourceSecretKey] == "" {
+			out = append(out, res)
+			continue
+		}
+		unstructured.RemoveNestedField(obj.Object, "data")
+		raw, err := obj.MarshalJSON()
+		if err != nil {
+			out = append(out, res)
+			continue
+		}
+		out = append(out, runtime.RawExtension{Raw: raw})
+	}
+	return out
+}
+
+// mapSecretRefToNamespaceClasses enqueues the classes that copy a Secret, so
+// that changes of the Secret reach its copies. Only Secrets of the operator's
+// namespace can be copied.
+func (r *NamespaceClassReconciler) mapSecretRefToNamespaceClasses(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetNamespace() != r.OperatorNamespace {
+		return nil
+	}
+
+	var classes v1alpha1.NamespaceClassList
+	if err := r.List(ctx, &classes); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Failed to list NamespaceClasses for Secret",
+			"secret", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	source := client.ObjectKeyFromObject(obj)
+	var requests []reconcile.Request
+	for _, class := range classes.Items {
+		for _, ref := range class.Spec.SecretRefs {
+			if r.secretRefSource(ref) == source {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: class.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
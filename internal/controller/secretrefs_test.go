@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("SecretRefs", func() {
+	newSecret := func(name, namespace string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       data,
+		}
+	}
+
+	It("should copy a referenced Secret into the namespaces and keep it up to date", func() {
+		ns := newNamespace("secret-ref-ns", "secret-ref-class")
+		class := newNamespaceClass("secret-ref-class")
+		class.Spec.SecretRefs = []v1alpha1.SecretRef{{Name: "registry", TargetName: "pull-secret"}}
+		source := newSecret("registry", operatorNamespace, map[string][]byte{".dockerconfigjson": []byte(`{"auths":{}}`)})
+		r, _, ctx := setupTestReconciler(ns, class, source)
+		r.OperatorNamespace = operatorNamespace
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var copied corev1.Secret
+		Expect(r.Get(ctx, types.NamespacedName{Name: "pull-secret", Namespace: ns.Name}, &copied)).To(Succeed())
+		Expect(copied.Type).To(Equal(corev1.SecretTypeDockerConfigJson))
+		Expect(copied.Data).To(HaveKeyWithValue(".dockerconfigjson", []byte(`{"auths":{}}`)))
+		Expect(copied.Annotations).To(HaveKeyWithValue(controller.NamespaceClassSourceSecretKey,
+			operatorNamespace+"/registry"))
+
+		By("keeping the data of the copy out of the class status")
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.LastAppliedResources).To(HaveLen(1))
+		Expect(string(persisted.Status.LastAppliedResources[0].Raw)).To(ContainSubstring("pull-secret"))
+		Expect(string(persisted.Status.LastAppliedResources[0].Raw)).NotTo(ContainSubstring(".dockerconfigjson"))
+
+		By("keeping the data of the copy out of its last-applied annotation")
+		Expect(copied.Annotations[controller.NamespaceClassLastAppliedKey]).To(ContainSubstring("pull-secret"))
+		Expect(copied.Annotations[controller.NamespaceClassLastAppliedKey]).NotTo(ContainSubstring(".dockerconfigjson"))
+
+		By("changing the referenced Secret")
+		source.Data[".dockerconfigjson"] = []byte(`{"auths":{"registry.example.com":{}}}`)
+		Expect(r.Update(ctx, source)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: "pull-secret", Namespace: ns.Name}, &copied)).To(Succeed())
+		Expect(copied.Data).To(HaveKeyWithValue(".dockerconfigjson", []byte(`{"auths":{"registry.example.com":{}}}`)))
+	})
+
+	It("should map a referenced Secret to the classes that copy it", func() {
+		copying := newNamespaceClass("copying-class")
+		copying.Spec.SecretRefs = []v1alpha1.SecretRef{{Name: "registry"}}
+		elsewhereClass := newNamespaceClass("elsewhere-class")
+		elsewhereClass.Spec.SecretRefs = []v1alpha1.SecretRef{{Namespace: "shared", Name: "registry"}}
+		r, _, ctx := setupTestReconciler(copying, elsewhereClass, newNamespaceClass("other-class"))
+		r.OperatorNamespace = operatorNamespace
+
+		Expect(controller.MapSecretRefToNamespaceClasses(r, ctx, newSecret("registry", operatorNamespace, nil))).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "copying-class"}},
+		))
+		Expect(controller.MapSecretRefToNamespaceClasses(r, ctx, newSecret("registry", "shared", nil))).To(BeEmpty())
+		Expect(controller.MapSecretRefToNamespaceClasses(r, ctx, newSecret("registry", "default", nil))).To(BeEmpty())
+	})
+	It("should refuse to copy a Secret from outside the operator namespace", func() {
+		ns := newNamespace("secret-ref-foreign-ns", "secret-ref-foreign-class")
+		class := newNamespaceClass("secret-ref-foreign-class")
+		class.Spec.SecretRefs = []v1alpha1.SecretRef{{Namespace: "kube-system", Name: "token"}}
+		source := newSecret("token", "kube-system", map[string][]byte{"token": []byte("secret")})
+		r, _, ctx := setupTestReconciler(ns, class, source)
+		r.OperatorNamespace = operatorNamespace
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).To(MatchError(ContainSubstring("can only be copied from the operator namespace")))
+
+		var copied corev1.Secret
+		Expect(r.Get(ctx, types.NamespacedName{Name: "token", Namespace: ns.Name}, &copied)).NotTo(Succeed())
+
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		Expect(events).To(ContainElement(ContainSubstring("SecretRefError")))
+	})
+})
@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// selectingClasses returns the names, sorted, of the classes whose namespace
+// selector matches ns. Namespaces with a class label and excluded namespaces
+// are never selected.
+func (r *NamespaceClassReconciler) selectingClasses(ctx context.Context, ns client.Object) []string {
+	if _, ok := ns.GetLabels()[r.classLabelKey()]; ok || r.isExcluded(ns.GetName()) {
+		return nil
+	}
+
+	var classes v1alpha1.NamespaceClassList
+	if err := r.List(ctx, &classes); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Failed to list NamespaceClasses for selectors")
+		return nil
+	}
+
+	var names []string
+	for _, class := range classes.Items {
+		if class.Spec.NamespaceSelector == nil || class.DeletionTimestamp != nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(class.Spec.NamespaceSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(ns.GetLabels())) {
+			names = append(names, class.Name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// isExcluded reports whether the namespace named name is one of the
+// ExcludedNamespaces.
+func (r *NamespaceClassReconciler) isExcluded(name string) bool {
+	return slices.Contains(r.ExcludedNamespaces, name)
+}
+
+// forEachNamespaceSelectedBy calls fn with the unlabeled namespaces that
+// selector matches and that belong to className, one page at a time.
+// Namespaces that another class wins are reported with a warning Event instead,
+// and excluded namespaces are skipped.
+func (r *NamespaceClassReconciler) forEachNamespaceSelectedBy(
+	ctx context.Context,
+	className string,
+	selector *metav1.LabelSelector,
+	fn func([]corev1.Namespace) error,
+) error {
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return err
+	}
+
+	return r.listNamespaces(ctx, func(page []corev1.Namespace) error {
+		var namespaces []corev1.Namespace
+		for _, ns := range page {
+			if _, ok := ns.Labels[r.classLabelKey()]; ok || r.isExcluded(ns.Name) {
+				continue
+			}
+			selecting := r.selectingClasses(ctx, &ns)
+			if len(selecting) > 0 && selecting[0] != className {
+				r.warnOnce(&ns, "NamespaceClassConflict",
+					"Namespace is selected by NamespaceClasses %s; applying '%s'", strings.Join(selecting, ", "), selecting[0])
+				continue
+			}
+			namespaces = append(namespaces, ns)
+		}
+		return fn(namespaces)
+	}, client.MatchingLabelsSelector{Selector: sel})
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("NamespaceSelector", func() {
+	teamNamespace := func(name, team, classLabel string) *corev1.Namespace {
+		ns := newNamespace(name, classLabel)
+		ns.Labels["team"] = team
+		return ns
+	}
+
+	selectingClass := func(name, team string) *v1alpha1.NamespaceClass {
+		class := newNamespaceClass(name, mustRawConfigMap(name+"-cfg", map[string]string{"foo": "bar"}))
+		class.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": team}}
+		return class
+	}
+
+	It("should bind unlabeled namespaces that match the selector", func() {
+		class := selectingClass("team-a", "a")
+		selected := teamNamespace("selected-ns", "a", "")
+		unmatched := teamNamespace("unmatched-ns", "b", "")
+		labeled := teamNamespace("labeled-ns", "a", "other-class")
+		r, _, ctx := setupTestReconciler(class, selected, unmatched, labeled)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, selected.Name)).To(HaveLen(1))
+		Expect(listConfigMaps(r.Client, ctx, unmatched.Name)).To(BeEmpty())
+		Expect(listConfigMaps(r.Client, ctx, labeled.Name)).To(BeEmpty())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.BoundNamespaces).To(Equal([]string{selected.Name}))
+	})
+
+	It("should apply the selecting class when the namespace is reconciled", func() {
+		class := selectingClass("team-c", "c")
+		ns := teamNamespace("team-c-ns", "c", "")
+		r, _, ctx := setupTestReconciler(class, ns)
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+	})
+
+	It("should report a namespace that several classes select and apply only the first", func() {
+		first := selectingClass("alpha-class", "shared")
+		second := selectingClass("beta-class", "shared")
+		ns := teamNamespace("contested-ns", "shared", "")
+		r, _, ctx := setupTestReconciler(first, second, ns)
+
+		Expect(controller.MapNamespaceToNamespaceClass(r, ctx, ns)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: first.Name}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: second.Name}},
+		))
+
+		_, err := r.Reconcile(ctx, requestFor(second))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(And(
+			ContainSubstring("NamespaceClassConflict"), ContainSubstring("alpha-class, beta-class"))))
+
+		_, err = r.Reconcile(ctx, requestFor(first))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+	})
+
+	It("should report a contested namespace once within the event cooldown", func() {
+		first := selectingClass("gamma-class", "contested")
+		second := selectingClass("delta-class", "contested")
+		ns := teamNamespace("contested-once-ns", "contested", "")
+		r, _, ctx := setupTestReconciler(first, second, ns)
+		r.EventCooldown = time.Hour
+
+		for range 3 {
+			_, err := r.Reconcile(ctx, requestFor(first))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		var conflicts []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			if event := <-r.Recorder.(*record.FakeRecorder).Events; strings.Contains(event, "NamespaceClassConflict") {
+				conflicts = append(conflicts, event)
+			}
+		}
+		Expect(conflicts).To(HaveLen(1))
+	})
+
+	It("should not select excluded namespaces", func() {
+		class := selectingClass("team-x", "x")
+		excluded := teamNamespace("kube-system", "x", "")
+		r, _, ctx := setupTestReconciler(class, excluded)
+		r.ExcludedNamespaces = []string{excluded.Name}
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, excluded.Name)).To(BeEmpty())
+
+		_, err = r.Reconcile(ctx, requestFor(excluded))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, excluded.Name)).To(BeEmpty())
+		Expect(controller.MapNamespaceToNamespaceClass(r, ctx, excluded)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FieldManager is the field manager the operator writes injected resources with.
+const FieldManager = "namespaceclass-operator"
+
+// ApplyStrategy controls how existing injected resources are brought in line
+// with their NamespaceClass.
+type ApplyStrategy string
+
+const (
+	// ApplyStrategyUpdate reads the live object and replaces it with an Update.
+	ApplyStrategyUpdate ApplyStrategy = "update"
+	// ApplyStrategyServerSide uses server-side apply, so that fields owned by
+	// other managers are detected as conflicts instead of being overwritten.
+	ApplyStrategyServerSide ApplyStrategy = "server-side"
+)
+
+// serverSideApply applies obj with server-side apply without forcing ownership.
+// A conflict with another field manager is reported as a warning Event on the
+// resource, naming the conflicting fields and their managers, and returned.
+func (r *NamespaceClassReconciler) serverSideApply(ctx context.Context, obj *unstructured.Unstructured) error {
+	log := resourceLogger(ctx, obj)
+
+	if err := r.writer().Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager)); err != nil {
+		if conflicts := fieldConflicts(err); len(conflicts) > 0 {
+			r.Recorder.Eventf(obj, corev1.EventTypeWarning, "ApplyConflict",
+				"Fields of %s '%s' are owned by another manager: %s", obj.GetKind(), obj.GetName(), describeConflicts(conflicts))
+		} else if apierrors.IsConflict(err) {
+			r.Recorder.Eventf(obj, corev1.EventTypeWarning, "ApplyConflict",
+				"Fields of %s '%s' are owned by another manager: %v", obj.GetKind(), obj.GetName(), err)
+		}
+		log.Error(err, "Failed to apply resource")
+		return err
+	}
+
+	log.Info("Applied resource")
+	return nil
+}
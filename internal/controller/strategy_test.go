@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Apply strategy", func() {
+	var (
+		ns       = newNamespace("strategy-ns", "strategy-class")
+		class    = newNamespaceClass("strategy-class", mustRawConfigMap("shared", map[string]string{"foo": "ours"}))
+		existing = newInjectedConfigMap("shared", "strategy-ns", map[string]string{"foo": "theirs"})
+	)
+
+	// conflictOnApply rejects server-side applies the way the API server does
+	// when another manager owns .data.foo.
+	conflictOnApply := interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() == types.ApplyPatchType {
+				return apierrors.NewApplyConflict([]metav1.StatusCause{{
+					Type:    metav1.CauseTypeFieldManagerConflict,
+					Message: `conflict with "other-controller": .data.foo`,
+					Field:   ".data.foo",
+				}}, "Apply failed with 1 conflict")
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}
+
+	It("should overwrite fields owned by another manager with the update strategy", func() {
+		r, _, ctx := setupTestReconcilerWithInterceptor(conflictOnApply, ns.DeepCopy(), class.DeepCopy(), existing.DeepCopy())
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Data).To(HaveKeyWithValue("foo", "ours"))
+	})
+
+	It("should report a conflict instead of overwriting with the server-side strategy", func() {
+		r, _, ctx := setupTestReconcilerWithInterceptor(conflictOnApply, ns.DeepCopy(), class.DeepCopy(), existing.DeepCopy())
+		r.ApplyStrategy = controller.ApplyStrategyServerSide
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Data).To(HaveKeyWithValue("foo", "theirs"))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("ApplyConflict")))
+	})
+
+	It("should record the conflicting fields and their managers in the status", func() {
+		conflicting := true
+		r, _, ctx := setupTestReconcilerWithInterceptor(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if patch.Type() == types.ApplyPatchType && !conflicting {
+					// The fake client cannot apply; the other manager gave up the field
+					return nil
+				}
+				return conflictOnApply.Patch(ctx, c, obj, patch, opts...)
+			},
+		}, ns.DeepCopy(), class.DeepCopy(), existing.DeepCopy())
+		r.ApplyStrategy = controller.ApplyStrategyServerSide
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(Equal(
+			`Warning ApplyConflict Fields of ConfigMap 'shared' are owned by another manager: .data.foo (owned by "other-controller")`)))
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.FieldConflicts).To(Equal([]v1alpha1.FieldConflict{{
+			Namespace: ns.Name,
+			Resource:  "ConfigMap/shared",
+			Field:     ".data.foo",
+			Manager:   "other-controller",
+		}}))
+
+		By("resolving the conflict")
+		conflicting = false
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.FieldConflicts).To(BeEmpty())
+	})
+
+	It("should apply with the operator's field manager without forcing ownership", func() {
+		var applied *client.PatchOptions
+		r, _, ctx := setupTestReconcilerWithInterceptor(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if patch.Type() != types.ApplyPatchType {
+					return c.Patch(ctx, obj, patch, opts...)
+				}
+				applied = (&client.PatchOptions{}).ApplyOptions(opts)
+				return nil
+			},
+		}, ns.DeepCopy(), class.DeepCopy())
+		r.ApplyStrategy = controller.ApplyStrategyServerSide
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(applied).NotTo(BeNil())
+		Expect(applied.FieldManager).To(Equal(controller.FieldManager))
+		Expect(applied.Force).To(BeNil())
+	})
+})
@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// targetNamespace returns the namespace obj is applied to in place of ns: the
+// value of its NamespaceClassTargetNamespaceKey annotation, or ns when it has
+// none. It reports false when the annotation names a namespace that is not in
+// TargetNamespaces.
+func (r *NamespaceClassReconciler) targetNamespace(obj *unstructured.Unstructured, ns string) (string, bool) {
+	target, ok := obj.GetAnnotations()[NamespaceClassTargetNamespaceKey]
+	if !ok || target == ns {
+		return ns, true
+	}
+	return target, slices.Contains(r.TargetNamespaces, target)
+}
+
+// applyTargetNamespaces moves the objects with a target namespace annotation
+// into that namespace. Objects that target a namespace which is not allowed are
+// dropped and reported with a warning Event on the namespace, so that a class
+// cannot write into other tenants' namespaces.
+func (r *NamespaceClassReconciler) applyTargetNamespaces(
+	log logr.Logger,
+	ns *corev1.Namespace,
+	className string,
+	objs []*unstructured.Unstructured,
+) []*unstructured.Unstructured {
+	allowed := objs[:0]
+	for _, obj := range objs {
+		target, ok := r.targetNamespace(obj, ns.Name)
+		if !ok {
+			withObject(log, obj).Info("Skipping resource with a target namespace that is not allowed",
+				"targetNamespace", target)
+			r.Recorder.Eventf(ns, corev1.EventTypeWarning, "TargetNamespaceNotAllowed",
+				"NamespaceClass '%s' targets %s '%s' at namespace '%s', which is not in the allowed target namespaces",
+				className, obj.GetKind(), obj.GetName(), target)
+			continue
+		}
+		obj.SetNamespace(target)
+		allowed = append(allowed, obj)
+	}
+	return allowed
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Target namespaces", func() {
+	toMonitoring := map[string]string{controller.NamespaceClassTargetNamespaceKey: "monitoring"}
+
+	var (
+		ns         *corev1.Namespace
+		monitoring *corev1.Namespace
+	)
+
+	BeforeEach(func() {
+		ns = newNamespace("tenant-ns", "monitored-class")
+		monitoring = newNamespace("monitoring", "")
+	})
+
+	DescribeTable("should create the resource in an allowed target namespace",
+		func(viaNamespace bool) {
+			class := newNamespaceClass("monitored-class",
+				mustRawConfigMap("local", map[string]string{"foo": "bar"}),
+				mustRawAnnotatedConfigMap("tenant-ns-scrape", toMonitoring, map[string]string{"target": "tenant-ns"}),
+			)
+			r, _, ctx := setupTestReconciler(ns, monitoring, class)
+			r.TargetNamespaces = []string{"monitoring"}
+
+			request := requestFor(class)
+			if viaNamespace {
+				request = requestFor(ns)
+			}
+			_, err := r.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(ConsistOf(HaveField("Name", "local")))
+			Expect(listConfigMaps(r.Client, ctx, "monitoring")).To(ConsistOf(HaveField("Name", "tenant-ns-scrape")))
+		},
+		Entry("when the class is reconciled", false),
+		Entry("when the namespace is reconciled", true),
+	)
+
+	It("should reject a target namespace that is not allowed", func() {
+		class := newNamespaceClass("monitored-class",
+			mustRawConfigMap("local", map[string]string{"foo": "bar"}),
+			mustRawAnnotatedConfigMap("tenant-ns-scrape", toMonitoring, map[string]string{"target": "tenant-ns"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, monitoring, class)
+		r.TargetNamespaces = []string{"logging"}
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(ConsistOf(HaveField("Name", "local")))
+		Expect(listConfigMaps(r.Client, ctx, "monitoring")).To(BeEmpty())
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("TargetNamespaceNotAllowed")))
+	})
+
+	It("should delete the resource from the target namespace on cleanup", func() {
+		setCleanupAnnotation(ns)
+		class := newDeletedNamespaceClass("monitored-class",
+			mustRawAnnotatedConfigMap("scrape", toMonitoring, map[string]string{"foo": "bar"}))
+		scrape := newInjectedConfigMap("scrape", "monitoring", map[string]string{"foo": "bar"})
+		scrape.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class.Name}
+		r, _, ctx := setupTestReconciler(ns, monitoring, class, scrape)
+		r.TargetNamespaces = []string{"monitoring"}
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, "monitoring")).To(BeEmpty())
+	})
+})
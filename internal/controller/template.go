@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// templateNamespace is the namespace as seen by resource templates. It prints
+// as its name, so both {{ .Namespace }} and {{ .Namespace.Labels.team }} work.
+type templateNamespace struct {
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+func (n templateNamespace) String() string {
+	return n.Name
+}
+
+// templateData is the data embedded resources are rendered with.
+type templateData struct {
+	Namespace templateNamespace
+	ClassName string
+	// Values are the data of the ConfigMap of the class's valuesRef.
+	Values map[string]string
+}
+
+// renderResource runs the strings of an embedded resource, keys and values,
+// through text/template after it is decoded, so that whatever a template
+// renders stays inside its string and cannot break the document or add
+// fields. Unknown fields and missing map keys are errors, so that a typo does
+// not silently render as "<no value>". Resources without template actions are
+// returned as is.
+func renderResource(raw []byte, ns *corev1.Namespace, className string, values map[string]string) ([]byte, error) {
+	if !bytes.Contains(raw, []byte("{{")) {
+		return raw, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	// Numbers are kept as written instead of going through float64
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	data := templateData{
+		Namespace: templateNamespace{
+			Name:        ns.Name,
+			Labels:      ns.Labels,
+			Annotations: ns.Annotations,
+		},
+		ClassName: className,
+		Values:    values,
+	}
+	rendered, err := renderValue(doc, className, data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(rendered)
+}
+
+// renderValue renders every string in the decoded JSON value v.
+func renderValue(v interface{}, name string, data templateData) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return renderString(v, name, data)
+	case map[string]interface{}:
+		rendered := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			renderedKey, err := renderString(key, name, data)
+			if err != nil {
+				return nil, err
+			}
+			if rendered[renderedKey], err = renderValue(value, name, data); err != nil {
+				return nil, err
+			}
+		}
+		return rendered, nil
+	case []interface{}:
+		rendered := make([]interface{}, len(v))
+		for i, value := range v {
+			var err error
+			if rendered[i], err = renderValue(value, name, data); err != nil {
+				return nil, err
+			}
+		}
+		return rendered, nil
+	default:
+		return v, nil
+	}
+}
+
+// renderString executes s as a template with data.
+func renderString(s, name string, data templateData) (string, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(s)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
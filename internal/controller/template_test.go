@@ -0,0 +1,218 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Templates", func() {
+	It("should inject the namespace name into ConfigMap data", func() {
+		ns := newNamespace("tmpl-ns", "tmpl-class")
+		class := newNamespaceClass("tmpl-class",
+			mustRawConfigMap("settings", map[string]string{"namespace": "{{ .Namespace }}"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Data).To(HaveKeyWithValue("namespace", "tmpl-ns"))
+	})
+
+	It("should expose namespace labels and the class name", func() {
+		ns := newNamespace("tmpl-labels-ns", "tmpl-labels-class")
+		ns.Labels["team"] = "payments"
+		class := newNamespaceClass("tmpl-labels-class",
+			mustRawConfigMap("settings", map[string]string{
+				"team":  "{{ .Namespace.Labels.team }}",
+				"class": "{{ .ClassName }}",
+			}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Data).To(HaveKeyWithValue("team", "payments"))
+		Expect(cms[0].Data).To(HaveKeyWithValue("class", "tmpl-labels-class"))
+	})
+
+	It("should keep rendered values inside their string", func() {
+		ns := newNamespace("tmpl-escape-ns", "tmpl-escape-class")
+		ns.Annotations = map[string]string{"note": `x", "injected": "y`}
+		class := newNamespaceClass("tmpl-escape-class",
+			mustRawConfigMap("settings", map[string]string{"note": `{{ index .Namespace.Annotations "note" }}`}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Data).To(Equal(map[string]string{"note": `x", "injected": "y`}))
+	})
+
+	It("should not record the namespace as in sync while a resource fails to render", func() {
+		ns := newNamespace("tmpl-unsynced-ns", "tmpl-unsynced-class")
+		class := newNamespaceClass("tmpl-unsynced-class",
+			mustRawConfigMap("broken", map[string]string{"team": "{{ .Namespace.Labels.tema }}"}),
+			mustRawConfigMap("plain", map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var live corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &live)).To(Succeed())
+		Expect(live.Annotations).NotTo(HaveKey(controller.NamespaceClassAppliedHashKey))
+	})
+
+	It("should clean up a resource with a templated name once it is removed from the class", func() {
+		ns := newNamespace("tmpl-obsolete-ns", "tmpl-obsolete-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		class := newNamespaceClass("tmpl-obsolete-class",
+			mustRawConfigMap("{{ .Namespace }}-settings", map[string]string{"foo": "bar"}),
+			mustRawConfigMap("plain", map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(2))
+
+		By("removing the templated resource")
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		persisted.Spec.Resources = persisted.Spec.Resources[1:]
+		Expect(r.Update(ctx, &persisted)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Name).To(Equal("plain"))
+	})
+
+	It("should skip the resource with an event when a key is missing", func() {
+		ns := newNamespace("tmpl-missing-ns", "tmpl-missing-class")
+		class := newNamespaceClass("tmpl-missing-class",
+			mustRawConfigMap("broken", map[string]string{"team": "{{ .Namespace.Labels.tema }}"}),
+			mustRawConfigMap("plain", map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(ns))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Name).To(Equal("plain"))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("TemplateError")))
+	})
+})
+
+var _ = Describe("Template values", func() {
+	It("should render the values of the referenced ConfigMap and pick up their changes", func() {
+		ns := newNamespace("values-ns", "values-class")
+		class := newNamespaceClass("values-class",
+			mustRawConfigMap("settings", map[string]string{"replicas": "{{ .Values.replicas }}"}),
+		)
+		class.Spec.ValuesRef = &v1alpha1.ValuesRef{Name: "staging"}
+		values := newInjectedConfigMap("staging", operatorNamespace, map[string]string{"replicas": "2"})
+		r, _, ctx := setupTestReconciler(ns, class, values)
+		r.OperatorNamespace = operatorNamespace
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, types.NamespacedName{Name: "settings", Namespace: ns.Name}, &cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("replicas", "2"))
+
+		By("changing the values")
+		values.Data["replicas"] = "5"
+		Expect(r.Update(ctx, values)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: "settings", Namespace: ns.Name}, &cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKeyWithValue("replicas", "5"))
+	})
+
+	It("should fail the namespace and keep its resources when the values ConfigMap is missing", func() {
+		ns := newNamespace("values-missing-ns", "values-missing-class")
+		ns.Annotations = map[string]string{controller.NamespaceClassCleanupObsoleteKey: "true"}
+		class := newNamespaceClass("values-missing-class",
+			mustRawConfigMap("settings", map[string]string{"replicas": "{{ .Values.replicas }}"}),
+			mustRawConfigMap("plain", map[string]string{"foo": "bar"}),
+		)
+		class.Spec.ValuesRef = &v1alpha1.ValuesRef{Name: "absent"}
+		settings := newInjectedConfigMap("settings", ns.Name, map[string]string{"replicas": "2"})
+		settings.Labels = map[string]string{controller.NamespaceClassOwnedByKey: class.Name}
+		r, _, ctx := setupTestReconciler(ns, class, settings)
+		r.OperatorNamespace = operatorNamespace
+		r.ObsoleteCleanup = controller.ObsoleteCleanupLabels
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		cms := listConfigMaps(r.Client, ctx, ns.Name)
+		Expect(cms).To(HaveLen(1))
+		Expect(cms[0].Name).To(Equal("settings"))
+
+		var live corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &live)).To(Succeed())
+		Expect(live.Annotations).NotTo(HaveKey(controller.NamespaceClassAppliedHashKey))
+
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		Expect(events).To(ContainElement(ContainSubstring("ValuesRefError")))
+	})
+
+	It("should map a values ConfigMap to the classes that use it", func() {
+		parameterized := newNamespaceClass("parameterized-class")
+		parameterized.Spec.ValuesRef = &v1alpha1.ValuesRef{Name: "staging"}
+		other := newNamespaceClass("unparameterized-class")
+		r, _, ctx := setupTestReconciler(parameterized, other)
+		r.OperatorNamespace = operatorNamespace
+
+		values := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: operatorNamespace}}
+		Expect(controller.MapResourceRefToNamespaceClasses(r, ctx, values)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "parameterized-class"}},
+		))
+	})
+})
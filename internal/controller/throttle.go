@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// applyLimiter returns the token bucket that paces the namespaces of the class,
+// or nil when ApplyQPS does not limit them. Every class has a bucket of its
+// own, so that a class with many namespaces does not hold up the others.
+func (r *NamespaceClassReconciler) applyLimiter(className string) *rate.Limiter {
+	if r.ApplyQPS <= 0 {
+		return nil
+	}
+	r.limitersMu.Lock()
+	defer r.limitersMu.Unlock()
+	if r.limiters == nil {
+		r.limiters = map[string]*rate.Limiter{}
+	}
+	limiter, ok := r.limiters[className]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(r.ApplyQPS), max(r.ApplyBurst, 1))
+		r.limiters[className] = limiter
+	}
+	return limiter
+}
+
+// waitToApply blocks until the class may apply to another namespace, or ctx is
+// done.
+func (r *NamespaceClassReconciler) waitToApply(ctx context.Context, className string) error {
+	limiter := r.applyLimiter(className)
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
+
+// forgetApplyLimiter drops the bucket of a deleted class.
+func (r *NamespaceClassReconciler) forgetApplyLimiter(className string) {
+	r.limitersMu.Lock()
+	defer r.limitersMu.Unlock()
+	delete(r.limiters, className)
+}
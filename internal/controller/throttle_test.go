@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+var _ = Describe("Apply rate limit", func() {
+	// recordCreates notes when each namespace got its first object.
+	recordCreates := func(mu *sync.Mutex, first map[string]time.Time) interceptor.Funcs {
+		return interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				mu.Lock()
+				if _, ok := first[obj.GetNamespace()]; !ok {
+					first[obj.GetNamespace()] = time.Now()
+				}
+				mu.Unlock()
+				return c.Create(ctx, obj, opts...)
+			},
+		}
+	}
+
+	It("should bound the rate at which a class is applied to its namespaces", func() {
+		class := newNamespaceClass("paced-class", mustRawConfigMap("settings", map[string]string{"foo": "bar"}))
+		objs := []client.Object{class}
+		for i := range 5 {
+			objs = append(objs, newNamespace(fmt.Sprintf("paced-ns-%d", i), class.Name))
+		}
+		var mu sync.Mutex
+		first := map[string]time.Time{}
+		r, _, ctx := setupTestReconcilerWithInterceptor(recordCreates(&mu, first), objs...)
+		r.ApplyQPS = 20
+		r.ApplyBurst = 1
+
+		start := time.Now()
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		// One namespace right away, then one every 50ms
+		Expect(time.Since(start)).To(BeNumerically(">=", 190*time.Millisecond))
+		Expect(first).To(HaveLen(5))
+		times := slices.SortedFunc(maps.Values(first), time.Time.Compare)
+		for i := 1; i < len(times); i++ {
+			Expect(times[i].Sub(times[i-1])).To(BeNumerically(">=", 40*time.Millisecond))
+		}
+	})
+
+	It("should pace every class on its own", func() {
+		busy := newNamespaceClass("busy-class", mustRawConfigMap("settings", map[string]string{"foo": "bar"}))
+		quiet := newNamespaceClass("quiet-class", mustRawConfigMap("settings", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(busy, quiet, newNamespace("busy-ns", busy.Name), newNamespace("quiet-ns", quiet.Name))
+		r.ApplyQPS = 0.1
+		r.ApplyBurst = 1
+
+		_, err := r.Reconcile(ctx, requestFor(busy))
+		Expect(err).NotTo(HaveOccurred())
+
+		// The bucket of busy-class is empty for the next 10s
+		start := time.Now()
+		_, err = r.Reconcile(ctx, requestFor(quiet))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(listConfigMaps(r.Client, ctx, "quiet-ns")).To(HaveLen(1))
+	})
+
+	It("should not pace namespaces that are in sync", func() {
+		class := newNamespaceClass("synced-class", mustRawConfigMap("settings", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(class,
+			newNamespace("synced-a", class.Name), newNamespace("synced-b", class.Name), newNamespace("synced-c", class.Name))
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+
+		r.ApplyQPS = 0.01
+		r.ApplyBurst = 1
+		start := time.Now()
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.Namespaces).To(HaveEach(HaveField("Ready", true)))
+	})
+
+	It("should stop waiting when the reconcile is cancelled", func() {
+		class := newNamespaceClass("stalled-class", mustRawConfigMap("settings", map[string]string{"foo": "bar"}))
+		r, _, ctx := setupTestReconciler(class,
+			newNamespace("stalled-a", class.Name), newNamespace("stalled-b", class.Name))
+		r.ApplyQPS = 0.01
+		r.ApplyBurst = 1
+
+		ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.Namespaces).To(ContainElement(And(
+			HaveField("Name", "stalled-b"),
+			HaveField("Ready", false),
+			HaveField("Message", ContainSubstring("waiting to apply")),
+		)))
+	})
+})
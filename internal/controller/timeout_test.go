@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+var _ = Describe("Apply timeout", func() {
+	// delayCreate makes every create take delay, like a slow API server, unless
+	// the context of the call is done first.
+	delayCreate := func(delay time.Duration) interceptor.Funcs {
+		return interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(delay):
+					return c.Create(ctx, obj, opts...)
+				}
+			},
+		}
+	}
+
+	It("should fail a write that takes longer than the timeout", func() {
+		class := newNamespaceClass("slow-class", mustRawConfigMap("settings", map[string]string{"foo": "bar"}))
+		ns := newNamespace("slow-ns", class.Name)
+		r, _, ctx := setupTestReconcilerWithInterceptor(delayCreate(time.Minute), class, ns)
+		r.ApplyTimeout = 50 * time.Millisecond
+		requests := r.WithRequestQueue(10)
+
+		start := time.Now()
+		_, _ = r.Reconcile(ctx, requestFor(class))
+		reconcileQueued(ctx, r, requests)
+		Expect(time.Since(start)).To(BeNumerically("<", 10*time.Second))
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+		var persisted v1alpha1.NamespaceClass
+		Expect(r.Get(ctx, types.NamespacedName{Name: class.Name}, &persisted)).To(Succeed())
+		Expect(persisted.Status.Namespaces).To(ConsistOf(And(
+			HaveField("Name", ns.Name),
+			HaveField("Ready", false),
+			HaveField("Message", ContainSubstring(context.DeadlineExceeded.Error())),
+		)))
+	})
+
+	It("should not fail a write that completes within the timeout", func() {
+		class := newNamespaceClass("quick-class", mustRawConfigMap("settings", map[string]string{"foo": "bar"}))
+		ns := newNamespace("quick-ns", class.Name)
+		r, _, ctx := setupTestReconcilerWithInterceptor(delayCreate(10*time.Millisecond), class, ns)
+		r.ApplyTimeout = 5 * time.Second
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(HaveLen(1))
+	})
+})
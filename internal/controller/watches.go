@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultWatchedKinds are the injected kinds watched for deletion when
+// WatchedKinds is not set. They match the RBAC the operator is granted.
+var DefaultWatchedKinds = []schema.GroupVersionKind{
+	{Version: "v1", Kind: "ConfigMap"},
+	{Version: "v1", Kind: "Secret"},
+	{Version: "v1", Kind: "Service"},
+	{Version: "v1", Kind: "ServiceAccount"},
+}
+
+// ParseKinds parses a comma-separated list of kinds. Each entry is either a
+// bare core kind ("ConfigMap") or "group/version/Kind" ("apps/v1/Deployment").
+func ParseKinds(value string) ([]schema.GroupVersionKind, error) {
+	var kinds []schema.GroupVersionKind
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, "/")
+		switch len(parts) {
+		case 1:
+			kinds = append(kinds, schema.GroupVersionKind{Version: "v1", Kind: parts[0]})
+		case 2:
+			kinds = append(kinds, schema.GroupVersionKind{Version: parts[0], Kind: parts[1]})
+		case 3:
+			kinds = append(kinds, schema.GroupVersionKind{Group: parts[0], Version: parts[1], Kind: parts[2]})
+		default:
+			return nil, fmt.Errorf("invalid kind %q, expected Kind or group/version/Kind", entry)
+		}
+	}
+	return kinds, nil
+}
+
+func (r *NamespaceClassReconciler) watchedKinds() []schema.GroupVersionKind {
+	if r.WatchedKinds != nil {
+		return r.WatchedKinds
+	}
+	return DefaultWatchedKinds
+}
+
+// ownedResourceDeleted only lets through deletions of objects injected by the
+// operator, so that they can be re-injected.
+var ownedResourceDeleted = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	UpdateFunc:  func(event.UpdateEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		_, ok := e.Object.GetLabels()[NamespaceClassOwnedByKey]
+		return ok
+	},
+}
+
+func watchedObject(gvk schema.GroupVersionKind) client.Object {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+func (r *NamespaceClassReconciler) mapOwnedResourceToNamespaceClass(ctx context.Context, obj client.Object) []reconcile.Request {
+	className := obj.GetLabels()[NamespaceClassOwnedByKey]
+	if className == "" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Name: className},
+	}}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+var _ = Describe("ParseKinds", func() {
+	It("should parse bare core kinds and group/version/Kind entries", func() {
+		kinds, err := ParseKinds("ConfigMap, v1/Secret,apps/v1/Deployment,")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kinds).To(Equal([]schema.GroupVersionKind{
+			{Version: "v1", Kind: "ConfigMap"},
+			{Version: "v1", Kind: "Secret"},
+			{Group: "apps", Version: "v1", Kind: "Deployment"},
+		}))
+	})
+
+	It("should reject malformed entries", func() {
+		_, err := ParseKinds("a/b/c/d")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Self-healing", Label("envtest"), Ordered, func() {
+	var stopManager context.CancelFunc
+
+	BeforeAll(func() {
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			Scheme:  scheme.Scheme,
+			Metrics: metricsserver.Options{BindAddress: "0"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect((&NamespaceClassReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr)).To(Succeed())
+
+		var mgrCtx context.Context
+		mgrCtx, stopManager = context.WithCancel(ctx)
+		go func() {
+			defer GinkgoRecover()
+			Expect(mgr.Start(mgrCtx)).To(Succeed())
+		}()
+	})
+
+	AfterAll(func() {
+		stopManager()
+	})
+
+	It("should recreate an injected resource after it is deleted", func() {
+		const className = "self-healing"
+
+		cm := &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "healed-config"},
+			Data:       map[string]string{"key": "value"},
+		}
+		raw, err := json.Marshal(cm)
+		Expect(err).NotTo(HaveOccurred())
+
+		class := &v1alpha1.NamespaceClass{
+			ObjectMeta: metav1.ObjectMeta{Name: className},
+			Spec: v1alpha1.NamespaceClassSpec{
+				Resources: []runtime.RawExtension{{Raw: raw}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, class)).To(Succeed())
+
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "self-healing",
+			Labels: map[string]string{NamespaceClassNameKey: className},
+		}}
+		Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+
+		key := types.NamespacedName{Name: "healed-config", Namespace: ns.Name}
+		injected := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, injected)
+		}, 10*time.Second, 100*time.Millisecond).Should(Succeed())
+		originalUID := injected.UID
+
+		By("deleting the injected ConfigMap")
+		Expect(k8sClient.Delete(ctx, injected)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			recreated := &corev1.ConfigMap{}
+			g.Expect(k8sClient.Get(ctx, key, recreated)).To(Succeed())
+			g.Expect(recreated.UID).NotTo(Equal(originalUID))
+			g.Expect(recreated.Data).To(HaveKeyWithValue("key", "value"))
+		}, 10*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+})
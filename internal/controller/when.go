@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// filterByWhen drops the objects whose NamespaceClassWhenKey selector does not
+// match the labels of ns, so that a class can apply a resource to only some of
+// its namespaces. A selector that does not parse is reported with a warning
+// Event on the namespace, and its object is dropped as well.
+func (r *NamespaceClassReconciler) filterByWhen(
+	log logr.Logger,
+	ns *corev1.Namespace,
+	className string,
+	objs []*unstructured.Unstructured,
+) []*unstructured.Unstructured {
+	matching := objs[:0]
+	for _, obj := range objs {
+		when, ok := obj.GetAnnotations()[NamespaceClassWhenKey]
+		if !ok {
+			matching = append(matching, obj)
+			continue
+		}
+		selector, err := labels.Parse(when)
+		if err != nil {
+			withObject(log, obj).Error(err, "Skipping resource with an invalid when selector")
+			r.Recorder.Eventf(ns, corev1.EventTypeWarning, "InvalidWhenSelector",
+				"NamespaceClass '%s' applies %s '%s' when %q, which is not a valid label selector: %v",
+				className, obj.GetKind(), obj.GetName(), when, err)
+			continue
+		}
+		if !selector.Matches(labels.Set(ns.Labels)) {
+			withObject(log, obj).V(1).Info("Skipping resource whose when selector does not match", "when", when)
+			continue
+		}
+		matching = append(matching, obj)
+	}
+	return matching
+}
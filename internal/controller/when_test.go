@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("When selectors", func() {
+	configMapNames := func(cms []corev1.ConfigMap) []string {
+		var names []string
+		for _, cm := range cms {
+			names = append(names, cm.Name)
+		}
+		return names
+	}
+
+	newTieredNamespace := func(name, tier string) *corev1.Namespace {
+		ns := newNamespace(name, "tiered-class")
+		ns.Labels["tier"] = tier
+		return ns
+	}
+
+	newFrontendOnly := func() runtime.RawExtension {
+		return mustRawAnnotatedConfigMap("frontend-only",
+			map[string]string{controller.NamespaceClassWhenKey: "tier=frontend"}, map[string]string{"foo": "bar"})
+	}
+
+	DescribeTable("should only apply a resource to namespaces that match its selector",
+		func(viaNamespace bool) {
+			frontend := newTieredNamespace("when-frontend", "frontend")
+			backend := newTieredNamespace("when-backend", "backend")
+			class := newNamespaceClass("tiered-class",
+				mustRawConfigMap("common", map[string]string{"foo": "bar"}),
+				newFrontendOnly(),
+			)
+			r, _, ctx := setupTestReconciler(frontend, backend, class)
+
+			if viaNamespace {
+				for _, ns := range []*corev1.Namespace{frontend, backend} {
+					_, err := r.Reconcile(ctx, requestFor(ns))
+					Expect(err).NotTo(HaveOccurred())
+				}
+			} else {
+				_, err := r.Reconcile(ctx, requestFor(class))
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(configMapNames(listConfigMaps(r.Client, ctx, frontend.Name))).To(ConsistOf("common", "frontend-only"))
+			Expect(configMapNames(listConfigMaps(r.Client, ctx, backend.Name))).To(ConsistOf("common"))
+		},
+		Entry("when the class is reconciled", false),
+		Entry("when the namespace is reconciled", true),
+	)
+
+	It("should apply the resource once the namespace starts to match", func() {
+		ns := newTieredNamespace("when-relabeled", "backend")
+		class := newNamespaceClass("tiered-class", newFrontendOnly())
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listConfigMaps(r.Client, ctx, ns.Name)).To(BeEmpty())
+
+		var live corev1.Namespace
+		Expect(r.Get(ctx, types.NamespacedName{Name: ns.Name}, &live)).To(Succeed())
+		live.Labels["tier"] = "frontend"
+		Expect(r.Update(ctx, &live)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configMapNames(listConfigMaps(r.Client, ctx, ns.Name))).To(ConsistOf("frontend-only"))
+	})
+
+	It("should skip a resource with an invalid selector", func() {
+		ns := newTieredNamespace("when-invalid", "frontend")
+		class := newNamespaceClass("tiered-class",
+			mustRawConfigMap("common", map[string]string{"foo": "bar"}),
+			mustRawAnnotatedConfigMap("broken",
+				map[string]string{controller.NamespaceClassWhenKey: "tier in (frontend"}, map[string]string{"foo": "bar"}),
+		)
+		r, _, ctx := setupTestReconciler(ns, class)
+
+		_, err := r.Reconcile(ctx, requestFor(class))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configMapNames(listConfigMaps(r.Client, ctx, ns.Name))).To(ConsistOf("common"))
+
+		var events []string
+		for len(r.Recorder.(*record.FakeRecorder).Events) > 0 {
+			events = append(events, <-r.Recorder.(*record.FakeRecorder).Events)
+		}
+		Expect(events).To(ContainElement(ContainSubstring("InvalidWhenSelector")))
+	})
+})
@@ -0,0 +1,206 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package render previews what a NamespaceClass injects into a namespace from
+// manifests on disk, without a cluster.
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+// Options select what to render.
+type Options struct {
+	// Class is the name of the NamespaceClass to render.
+	Class string
+	// Namespace is the name of the namespace to render the class for. The
+	// labels of a Namespace manifest of that name are used by templates;
+	// without one the namespace has no labels.
+	Namespace string
+	// OperatorNamespace is the namespace of the ConfigMaps that resourceRefs
+	// point at.
+	OperatorNamespace string
+}
+
+// Load reads the NamespaceClasses, Namespaces and ConfigMaps of the YAML files
+// at paths, which are files or directories. Other objects are ignored, since
+// rendering never reads them.
+func Load(scheme *runtime.Scheme, paths ...string) ([]client.Object, error) {
+	var objs []client.Object
+	for _, path := range paths {
+		files, err := manifestFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			loaded, err := readFile(scheme, file)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			objs = append(objs, loaded...)
+		}
+	}
+	return objs, nil
+}
+
+// Resources returns what the class named in opts injects into the namespace
+// named in opts, resolved from objs the same way the operator does. The
+// warnings the operator would report as Events, e.g. of a missing parent
+// class, are returned as well.
+func Resources(
+	ctx context.Context,
+	scheme *runtime.Scheme,
+	objs []client.Object,
+	opts Options,
+) ([]*unstructured.Unstructured, []string, error) {
+	recorder := record.NewFakeRecorder(1024)
+	r := &controller.NamespaceClassReconciler{
+		Client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Scheme:            scheme,
+		Recorder:          recorder,
+		OperatorNamespace: opts.OperatorNamespace,
+	}
+
+	var class v1alpha1.NamespaceClass
+	if err := r.Get(ctx, types.NamespacedName{Name: opts.Class}, &class); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("NamespaceClass %q not found in the manifests", opts.Class)
+		}
+		return nil, nil, err
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: opts.Namespace}}
+	if err := r.Get(ctx, types.NamespacedName{Name: opts.Namespace}, ns); client.IgnoreNotFound(err) != nil {
+		return nil, nil, err
+	}
+
+	rendered, err := r.Render(ctx, &class, ns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	for len(recorder.Events) > 0 {
+		if event := <-recorder.Events; strings.HasPrefix(event, corev1.EventTypeWarning) {
+			warnings = append(warnings, event)
+		}
+	}
+	return rendered, warnings, nil
+}
+
+// Write prints objs to w as a multi-document YAML stream.
+func Write(w io.Writer, objs []*unstructured.Unstructured) error {
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func manifestFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		files = append(files, filepath.Join(path, entry.Name()))
+	}
+	return files, nil
+}
+
+// readFile decodes the documents of a file into typed objects of the kinds
+// rendering reads.
+func readFile(scheme *runtime.Scheme, path string) ([]client.Object, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []client.Object
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		u := &unstructured.Unstructured{}
+		if err := decoder.Decode(&u.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode: %w", err)
+		}
+		if !readable(u) {
+			continue
+		}
+
+		obj, err := scheme.New(u.GroupVersionKind())
+		if err != nil {
+			return nil, err
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", u.GetKind(), u.GetName(), err)
+		}
+		objs = append(objs, obj.(client.Object))
+	}
+	return objs, nil
+}
+
+// readable reports whether rendering may read objects like u.
+func readable(u *unstructured.Unstructured) bool {
+	switch u.GroupVersionKind() {
+	case v1alpha1.GroupVersion.WithKind("NamespaceClass"),
+		corev1.SchemeGroupVersion.WithKind("Namespace"),
+		corev1.SchemeGroupVersion.WithKind("ConfigMap"):
+		return true
+	}
+	return false
+}
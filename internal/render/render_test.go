@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+	"github.com/kardolus/namespaceclass-operator/internal/render"
+)
+
+var _ = Describe("Render", func() {
+	var (
+		scheme *runtime.Scheme
+		objs   []client.Object
+		ctx    = context.Background()
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		var err error
+		objs, err = render.Load(scheme, filepath.Join("testdata", "classes"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	resources := func(opts render.Options) ([]*unstructured.Unstructured, []string) {
+		if opts.OperatorNamespace == "" {
+			opts.OperatorNamespace = "namespaceclass-operator-system"
+		}
+		rendered, warnings, err := render.Resources(ctx, scheme, objs, opts)
+		Expect(err).NotTo(HaveOccurred())
+		return rendered, warnings
+	}
+
+	It("should only load the kinds rendering reads", func() {
+		var kinds []string
+		for _, obj := range objs {
+			gvk, _, err := scheme.ObjectKinds(obj)
+			Expect(err).NotTo(HaveOccurred())
+			kinds = append(kinds, gvk[0].Kind+"/"+obj.GetName())
+		}
+		Expect(kinds).To(ConsistOf(
+			"NamespaceClass/base", "NamespaceClass/team", "NamespaceClass/orphan",
+			"Namespace/payments", "ConfigMap/shared-manifests",
+		))
+	})
+
+	It("should resolve inheritance, references and templates for the namespace", func() {
+		rendered, warnings := resources(render.Options{Class: "team", Namespace: "payments"})
+		Expect(warnings).To(BeEmpty())
+
+		var refs []string
+		for _, obj := range rendered {
+			refs = append(refs, obj.GetKind()+"/"+obj.GetName())
+			Expect(obj.GetNamespace()).To(Equal("payments"))
+			Expect(obj.GetLabels()).To(HaveKeyWithValue(controller.NamespaceClassOwnedByKey, "team"))
+		}
+		Expect(refs).To(Equal([]string{"ConfigMap/settings", "ServiceAccount/deployer", "Secret/payments-token"}))
+
+		data, _, err := unstructured.NestedStringMap(rendered[0].Object, "data")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal(map[string]string{"tier": "team", "owner": "billing"}))
+	})
+
+	It("should render for a namespace without a manifest", func() {
+		rendered, _ := resources(render.Options{Class: "base", Namespace: "scratch"})
+
+		Expect(rendered).To(HaveLen(2))
+		for _, obj := range rendered {
+			Expect(obj.GetNamespace()).To(Equal("scratch"))
+		}
+	})
+
+	It("should return the warnings the operator would report as events", func() {
+		rendered, warnings := resources(render.Options{Class: "orphan", Namespace: "payments"})
+
+		Expect(rendered).To(HaveLen(1))
+		Expect(warnings).To(ConsistOf(ContainSubstring("MissingParentClass")))
+	})
+
+	It("should fail for a class that is not in the manifests", func() {
+		_, _, err := render.Resources(ctx, scheme, objs, render.Options{Class: "absent", Namespace: "payments"})
+		Expect(err).To(MatchError(ContainSubstring(`NamespaceClass "absent" not found`)))
+	})
+
+	It("should write the resources as a YAML stream", func() {
+		rendered, _ := resources(render.Options{Class: "base", Namespace: "payments"})
+
+		var out bytes.Buffer
+		Expect(render.Write(&out, rendered)).To(Succeed())
+		Expect(out.String()).To(Equal(`---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  annotations:
+    namespaceclass.kardolus.dev/class-generation: "0"
+  labels:
+    namespaceclass.kardolus.dev/owned-by: base
+  name: deployer
+  namespace: payments
+---
+apiVersion: v1
+data:
+  tier: base
+kind: ConfigMap
+metadata:
+  annotations:
+    namespaceclass.kardolus.dev/class-generation: "0"
+  labels:
+    namespaceclass.kardolus.dev/owned-by: base
+  name: settings
+  namespace: payments
+`))
+	})
+})
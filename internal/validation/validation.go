@@ -0,0 +1,183 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation contains offline checks for NamespaceClass objects that
+// do not require access to a cluster.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	apipath "k8s.io/apimachinery/pkg/api/validation/path"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+)
+
+// ValidateNamespaceClass checks that every embedded resource of the class,
+// including the documents of its resourcesYAML, can be decoded into a
+// Kubernetes object with an apiVersion, a kind and a name, or generateName
+// prefix, that is valid for its kind and does not set a namespace, which the
+// controller would otherwise silently replace, that the namespace selector
+// parses and that the class does not extend itself.
+func ValidateNamespaceClass(class *v1alpha1.NamespaceClass) field.ErrorList {
+	var errs field.ErrorList
+
+	resourcesPath := field.NewPath("spec", "resources")
+	for i, res := range class.Spec.Resources {
+		path := resourcesPath.Index(i)
+
+		// Decoded as plain JSON, so that a missing kind is reported as such
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(res.Raw, &obj.Object); err != nil {
+			errs = append(errs, field.Invalid(path, string(res.Raw), "not a valid Kubernetes object: "+err.Error()))
+			continue
+		}
+		if typeErrs := validateTypeMeta(path, obj); len(typeErrs) > 0 {
+			errs = append(errs, typeErrs...)
+			continue
+		}
+		errs = append(errs, validateResource(path, obj)...)
+	}
+
+	if class.Spec.ResourcesYAML != "" {
+		yamlPath := field.NewPath("spec", "resourcesYAML")
+		objs, err := DecodeDocuments(class.Spec.ResourcesYAML)
+		if err != nil {
+			errs = append(errs, field.Invalid(yamlPath, "", err.Error()))
+		}
+		for i, obj := range objs {
+			if typeErrs := validateTypeMeta(yamlPath.Index(i), obj); len(typeErrs) > 0 {
+				errs = append(errs, typeErrs...)
+				continue
+			}
+			errs = append(errs, validateResource(yamlPath.Index(i), obj)...)
+		}
+	}
+
+	if class.Spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(class.Spec.NamespaceSelector); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "namespaceSelector"),
+				class.Spec.NamespaceSelector, err.Error()))
+		}
+	}
+
+	if class.Spec.Extends != "" && class.Spec.Extends == class.Name {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "extends"), class.Spec.Extends,
+			"a class cannot extend itself"))
+	}
+
+	return errs
+}
+
+// validateTypeMeta checks that obj, the resource at path, sets apiVersion and
+// kind. Without them the controller cannot tell what to create, and the API
+// server rejects the object with an error that does not point at the class.
+func validateTypeMeta(path *field.Path, obj *unstructured.Unstructured) field.ErrorList {
+	var errs field.ErrorList
+	if obj.GetAPIVersion() == "" {
+		errs = append(errs, field.Required(path.Child("apiVersion"),
+			"not a valid Kubernetes object: embedded resources must set apiVersion, e.g. \"v1\""))
+	}
+	if obj.GetKind() == "" {
+		errs = append(errs, field.Required(path.Child("kind"),
+			"not a valid Kubernetes object: embedded resources must set kind, e.g. \"ConfigMap\""))
+	}
+	return errs
+}
+
+// validateResource checks that obj, the resource at path, has a name or
+// generateName prefix that is valid for its kind and does not set a namespace.
+func validateResource(path *field.Path, obj *unstructured.Unstructured) field.ErrorList {
+	var errs field.ErrorList
+	switch {
+	case obj.GetName() == "" && obj.GetGenerateName() != "":
+		// Generated names append random characters to the prefix
+		if msgs := validateName(obj.GroupVersionKind(), obj.GetGenerateName()+"x"); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(path.Child("metadata", "generateName"), obj.GetGenerateName(),
+				"not a valid "+obj.GetKind()+" name prefix: "+strings.Join(msgs, "; ")))
+		}
+	case obj.GetName() == "":
+		errs = append(errs, field.Required(path.Child("metadata", "name"),
+			"embedded resources must set a name or generateName"))
+	default:
+		if msgs := validateName(obj.GroupVersionKind(), obj.GetName()); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(path.Child("metadata", "name"), obj.GetName(),
+				"not a valid "+obj.GetKind()+" name: "+strings.Join(msgs, "; ")))
+		}
+	}
+	if obj.GetNamespace() != "" {
+		errs = append(errs, field.Forbidden(path.Child("metadata", "namespace"),
+			"embedded resources are created in the namespaces that use the class"))
+	}
+	return errs
+}
+
+// DecodeDocuments splits a YAML or JSON stream into its objects, dropping empty
+// documents. On error, the objects decoded before the failing document are
+// returned with it.
+func DecodeDocuments(data string) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(data), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return objs, fmt.Errorf("failed to decode document %d: %w", len(objs), err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+}
+
+// templateAction matches a template action in a name, e.g. "{{ .Value }}".
+var templateAction = regexp.MustCompile(`\{\{.*?\}\}`)
+
+// validateName checks name the way the API server does for objects of kind
+// gvk, so that an invalid name is reported at admission rather than when the
+// resource is created in each namespace. Services need DNS-1035 labels and
+// RBAC objects path segments, e.g. "system:viewer"; every other kind,
+// including custom resources, needs a DNS-1123 subdomain. Template actions,
+// which are only rendered per namespace, are checked as if they rendered to
+// "x".
+func validateName(gvk schema.GroupVersionKind, name string) []string {
+	name = templateAction.ReplaceAllString(name, "x")
+	switch gvk.GroupKind() {
+	case schema.GroupKind{Kind: "Service"}:
+		return utilvalidation.IsDNS1035Label(name)
+	case schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "Role"},
+		schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"},
+		schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+		schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:
+		return apipath.IsValidPathSegmentName(name)
+	default:
+		return utilvalidation.IsDNS1123Subdomain(name)
+	}
+}
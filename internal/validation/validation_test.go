@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/validation"
+)
+
+var _ = Describe("ValidateNamespaceClass", func() {
+	newClass := func(resources ...string) *v1alpha1.NamespaceClass {
+		class := &v1alpha1.NamespaceClass{ObjectMeta: metav1.ObjectMeta{Name: "validated-class"}}
+		for _, res := range resources {
+			class.Spec.Resources = append(class.Spec.Resources, runtime.RawExtension{Raw: []byte(res)})
+		}
+		return class
+	}
+
+	It("should check templated names as if every action rendered to a valid name", func() {
+		class := newClass(
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .Namespace }}-scrape"}}`,
+			`{"apiVersion":"v1","kind":"Service","metadata":{"name":"{{ .Namespace }}"}}`,
+			`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"Role","metadata":{"name":"allow-{{ .Value }}",`+
+				`"annotations":{"namespaceclass.kardolus.dev/repeat":"read,write"}}}`,
+		)
+		class.Spec.ResourcesYAML = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: 'tier-{{ .Value }}'\n"
+
+		Expect(validation.ValidateNamespaceClass(class)).To(BeEmpty())
+	})
+
+	It("should still reject what is invalid around a template action", func() {
+		class := newClass(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"{{ .Namespace }}.backend"}}`)
+
+		errs := validation.ValidateNamespaceClass(class)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.resources[0].metadata.name"))
+	})
+})
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	namespacev1alpha1 "github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var namespacelog = logf.Log.WithName("namespace-resource")
+
+// SetupNamespaceWebhookWithManager registers the webhook for Namespace in the manager.
+// classLabelKey is the label that names the class of a namespace.
+func SetupNamespaceWebhookWithManager(mgr ctrl.Manager, classLabelKey string) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&corev1.Namespace{}).
+		WithDefaulter(&NamespaceCustomDefaulter{Client: mgr.GetClient(), ClassLabelKey: classLabelKey}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate--v1-namespace,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=namespaces,verbs=create;update,versions=v1,name=mnamespace-v1.kb.io,admissionReviewVersions=v1
+
+// NamespaceCustomDefaulter sets the cleanup annotations of namespaces from the
+// cleanupPolicy of the NamespaceClass their class label names. Annotations the
+// namespace already sets are kept, so a namespace can still opt out of the
+// policy of its class. Namespaces bound by a selector or the default class are
+// left alone, since they may match several classes or none at admission.
+type NamespaceCustomDefaulter struct {
+	Client client.Reader
+	// ClassLabelKey is the label that names the class of a namespace.
+	// Defaults to controller.NamespaceClassNameKey.
+	ClassLabelKey string
+}
+
+var _ webhook.CustomDefaulter = &NamespaceCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type Namespace.
+func (d *NamespaceCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return fmt.Errorf("expected a Namespace object but got %T", obj)
+	}
+
+	key := d.ClassLabelKey
+	if key == "" {
+		key = controller.NamespaceClassNameKey
+	}
+	className, ok := ns.Labels[key]
+	if !ok {
+		return nil
+	}
+	var class namespacev1alpha1.NamespaceClass
+	if err := d.Client.Get(ctx, types.NamespacedName{Name: className}, &class); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	var value string
+	switch class.Spec.CleanupPolicy {
+	case namespacev1alpha1.CleanupPolicyDelete:
+		value = "true"
+	case namespacev1alpha1.CleanupPolicyOrphan:
+		value = "false"
+	default:
+		return nil
+	}
+	namespacelog.Info("Defaulting cleanup annotations for Namespace", "name", ns.GetName(),
+		"class", className, "cleanupPolicy", class.Spec.CleanupPolicy)
+
+	if ns.Annotations == nil {
+		ns.Annotations = map[string]string{}
+	}
+	for _, key := range []string{controller.NamespaceClassCleanupKey, controller.NamespaceClassCleanupObsoleteKey} {
+		if _, ok := ns.Annotations[key]; !ok {
+			ns.Annotations[key] = value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	namespacev1alpha1 "github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("Namespace Webhook", func() {
+	ctx := context.Background()
+
+	newDefaulter := func(classes ...*namespacev1alpha1.NamespaceClass) *NamespaceCustomDefaulter {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(namespacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		builder := fake.NewClientBuilder().WithScheme(scheme)
+		for _, class := range classes {
+			builder = builder.WithObjects(class)
+		}
+		return &NamespaceCustomDefaulter{Client: builder.Build()}
+	}
+
+	newClass := func(name string, policy namespacev1alpha1.CleanupPolicy) *namespacev1alpha1.NamespaceClass {
+		return &namespacev1alpha1.NamespaceClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       namespacev1alpha1.NamespaceClassSpec{CleanupPolicy: policy},
+		}
+	}
+
+	newNamespace := func(className string, annotations map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "webhook-ns",
+			Labels:      map[string]string{controller.NamespaceClassNameKey: className},
+			Annotations: annotations,
+		}}
+	}
+
+	It("should enable cleanup for a class with the Delete policy", func() {
+		defaulter := newDefaulter(newClass("delete-class", namespacev1alpha1.CleanupPolicyDelete))
+		ns := newNamespace("delete-class", nil)
+
+		Expect(defaulter.Default(ctx, ns)).To(Succeed())
+		Expect(ns.Annotations).To(HaveKeyWithValue(controller.NamespaceClassCleanupKey, "true"))
+		Expect(ns.Annotations).To(HaveKeyWithValue(controller.NamespaceClassCleanupObsoleteKey, "true"))
+	})
+
+	It("should disable cleanup for a class with the Orphan policy", func() {
+		defaulter := newDefaulter(newClass("orphan-class", namespacev1alpha1.CleanupPolicyOrphan))
+		ns := newNamespace("orphan-class", nil)
+
+		Expect(defaulter.Default(ctx, ns)).To(Succeed())
+		Expect(ns.Annotations).To(HaveKeyWithValue(controller.NamespaceClassCleanupKey, "false"))
+		Expect(ns.Annotations).To(HaveKeyWithValue(controller.NamespaceClassCleanupObsoleteKey, "false"))
+	})
+
+	It("should keep cleanup annotations the namespace sets itself", func() {
+		defaulter := newDefaulter(newClass("keep-class", namespacev1alpha1.CleanupPolicyDelete))
+		ns := newNamespace("keep-class", map[string]string{controller.NamespaceClassCleanupKey: "false"})
+
+		Expect(defaulter.Default(ctx, ns)).To(Succeed())
+		Expect(ns.Annotations).To(HaveKeyWithValue(controller.NamespaceClassCleanupKey, "false"))
+		Expect(ns.Annotations).To(HaveKeyWithValue(controller.NamespaceClassCleanupObsoleteKey, "true"))
+	})
+
+	It("should leave namespaces alone without a policy, a class label or a class", func() {
+		defaulter := newDefaulter(newClass("no-policy-class", ""))
+
+		for _, ns := range []*corev1.Namespace{
+			newNamespace("no-policy-class", nil),
+			newNamespace("missing-class", nil),
+			{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"}},
+		} {
+			Expect(defaulter.Default(ctx, ns)).To(Succeed())
+			Expect(ns.Annotations).To(BeEmpty())
+		}
+	})
+
+	It("should read the class from a custom class label", func() {
+		defaulter := newDefaulter(newClass("custom-class", namespacev1alpha1.CleanupPolicyDelete))
+		defaulter.ClassLabelKey = "example.com/class"
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "custom-ns",
+			Labels: map[string]string{"example.com/class": "custom-class"},
+		}}
+
+		Expect(defaulter.Default(ctx, ns)).To(Succeed())
+		Expect(ns.Annotations).To(HaveKeyWithValue(controller.NamespaceClassCleanupKey, "true"))
+	})
+})
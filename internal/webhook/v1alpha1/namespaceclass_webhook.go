@@ -0,0 +1,256 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	namespacev1alpha1 "github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+	"github.com/kardolus/namespaceclass-operator/internal/validation"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var namespaceclasslog = logf.Log.WithName("namespaceclass-resource")
+
+// SetupNamespaceClassWebhookWithManager registers the webhook for NamespaceClass in the manager.
+// Classes with more than maxResources embedded resources are rejected, unless
+// maxResources is zero. With preventOrphaning, deleting a class that namespaces
+// labeled with classLabelKey, or that get it as defaultClass unless they are
+// among excludedNamespaces, still use without cleanup is rejected.
+func SetupNamespaceClassWebhookWithManager(
+	mgr ctrl.Manager,
+	maxResources int,
+	classLabelKey string,
+	preventOrphaning bool,
+	defaultClass string,
+	excludedNamespaces []string,
+) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&namespacev1alpha1.NamespaceClass{}).
+		WithValidator(&NamespaceClassCustomValidator{
+			MaxResources:       maxResources,
+			Client:             mgr.GetClient(),
+			ClassLabelKey:      classLabelKey,
+			PreventOrphaning:   preventOrphaning,
+			DefaultClass:       defaultClass,
+			ExcludedNamespaces: excludedNamespaces,
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-namespace-kardolus-dev-v1alpha1-namespaceclass,mutating=false,failurePolicy=fail,sideEffects=None,groups=namespace.kardolus.dev,resources=namespaceclasses,verbs=create;update,versions=v1alpha1,name=vnamespaceclass-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// Deletions are registered separately and fail open, so that classes can still
+// be deleted while the webhook is down; only --prevent-orphaning rejects them.
+// +kubebuilder:webhook:path=/validate-namespace-kardolus-dev-v1alpha1-namespaceclass,mutating=false,failurePolicy=ignore,sideEffects=None,groups=namespace.kardolus.dev,resources=namespaceclasses,verbs=delete,versions=v1alpha1,name=vnamespaceclass-delete-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// NamespaceClassCustomValidator rejects NamespaceClasses whose embedded
+// resources are not valid Kubernetes objects or set metadata.namespace.
+type NamespaceClassCustomValidator struct {
+	// MaxResources limits the number of embedded resources of a class. Only
+	// spec.resources is counted, since inherited and referenced resources are
+	// not known at admission; the controller enforces the limit on those.
+	// Zero means no limit.
+	MaxResources int
+
+	// PreventOrphaning rejects the deletion of a class while namespaces that
+	// do not opt into cleanup still use it, since their resources would be
+	// left behind. A class annotated with controller.NamespaceClassAllowOrphanKey
+	// can be deleted regardless.
+	PreventOrphaning bool
+	// Client reads the namespaces of a class when PreventOrphaning is set.
+	Client client.Reader
+	// ClassLabelKey is the label that names the class of a namespace.
+	// Defaults to controller.NamespaceClassNameKey.
+	ClassLabelKey string
+	// DefaultClass and ExcludedNamespaces are those of the controller, so that
+	// the namespaces that only get a class by default are found too.
+	DefaultClass       string
+	ExcludedNamespaces []string
+}
+
+var _ webhook.CustomValidator = &NamespaceClassCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type NamespaceClass.
+func (v *NamespaceClassCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	class, ok := obj.(*namespacev1alpha1.NamespaceClass)
+	if !ok {
+		return nil, fmt.Errorf("expected a NamespaceClass object but got %T", obj)
+	}
+	namespaceclasslog.Info("Validation for NamespaceClass upon creation", "name", class.GetName())
+
+	return nil, v.validate(class)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type NamespaceClass.
+func (v *NamespaceClassCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	class, ok := newObj.(*namespacev1alpha1.NamespaceClass)
+	if !ok {
+		return nil, fmt.Errorf("expected a NamespaceClass object for the newObj but got %T", newObj)
+	}
+	namespaceclasslog.Info("Validation for NamespaceClass upon update", "name", class.GetName())
+
+	return nil, v.validate(class)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type NamespaceClass.
+func (v *NamespaceClassCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	class, ok := obj.(*namespacev1alpha1.NamespaceClass)
+	if !ok {
+		return nil, fmt.Errorf("expected a NamespaceClass object but got %T", obj)
+	}
+	if !v.PreventOrphaning {
+		return nil, nil
+	}
+	namespaceclasslog.Info("Validation for NamespaceClass upon deletion", "name", class.GetName())
+
+	orphaned, err := v.orphanedNamespaces(ctx, class)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	if len(orphaned) == 0 {
+		return nil, nil
+	}
+	if class.Annotations[controller.NamespaceClassAllowOrphanKey] == "true" {
+		return admission.Warnings{fmt.Sprintf("the resources of NamespaceClass %q are left behind in namespaces %s",
+			class.Name, strings.Join(orphaned, ", "))}, nil
+	}
+	return nil, apierrors.NewForbidden(namespacev1alpha1.GroupVersion.WithResource("namespaceclasses").GroupResource(),
+		class.Name, fmt.Errorf("namespaces %s still use the class without %s=true, so its resources would be left "+
+			"behind; annotate them, or the class with %s=true to delete it anyway",
+			strings.Join(orphaned, ", "), controller.NamespaceClassCleanupKey, controller.NamespaceClassAllowOrphanKey))
+}
+
+// orphanedNamespaces returns the sorted names of the namespaces that use class
+// and would keep its resources when it is deleted. A namespace uses the class
+// when its class label, or one of the numbered labels it composes further
+// classes with, names it, and, unless it is labeled or excluded, when the
+// namespaceSelector of the class matches it or it is the default class and no
+// other class selects it. Namespaces that are being deleted take the resources
+// with them.
+func (v *NamespaceClassCustomValidator) orphanedNamespaces(
+	ctx context.Context,
+	class *namespacev1alpha1.NamespaceClass,
+) ([]string, error) {
+	var selector labels.Selector
+	if class.Spec.NamespaceSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(class.Spec.NamespaceSelector); err != nil {
+			return nil, err
+		}
+	}
+	var others []labels.Selector
+	if class.Name == v.DefaultClass {
+		var classes namespacev1alpha1.NamespaceClassList
+		if err := v.Client.List(ctx, &classes); err != nil {
+			return nil, err
+		}
+		for _, other := range classes.Items {
+			if other.Name == class.Name || other.Spec.NamespaceSelector == nil {
+				continue
+			}
+			// Classes with an invalid selector select nothing
+			if s, err := metav1.LabelSelectorAsSelector(other.Spec.NamespaceSelector); err == nil {
+				others = append(others, s)
+			}
+		}
+	}
+
+	var namespaces corev1.NamespaceList
+	if err := v.Client.List(ctx, &namespaces); err != nil {
+		return nil, err
+	}
+	var orphaned []string
+	for _, ns := range namespaces.Items {
+		if !v.usesClass(&ns, class.Name, selector, others) {
+			continue
+		}
+		if ns.DeletionTimestamp != nil || ns.Annotations[controller.NamespaceClassCleanupKey] == "true" {
+			continue
+		}
+		orphaned = append(orphaned, ns.Name)
+	}
+	slices.Sort(orphaned)
+	return orphaned, nil
+}
+
+// usesClass reports whether ns uses the class named className, see
+// orphanedNamespaces. selector is that of the class, if any, and others are
+// those of the other classes when it is the default class.
+func (v *NamespaceClassCustomValidator) usesClass(
+	ns *corev1.Namespace,
+	className string,
+	selector labels.Selector,
+	others []labels.Selector,
+) bool {
+	key := v.ClassLabelKey
+	if key == "" {
+		key = controller.NamespaceClassNameKey
+	}
+	if name, labeled := ns.Labels[key]; labeled {
+		if name == className {
+			return true
+		}
+	} else if !slices.Contains(v.ExcludedNamespaces, ns.Name) {
+		set := labels.Set(ns.Labels)
+		if selector != nil && selector.Matches(set) {
+			return true
+		}
+		if className == v.DefaultClass &&
+			!slices.ContainsFunc(others, func(s labels.Selector) bool { return s.Matches(set) }) {
+			return true
+		}
+	}
+	for label, name := range ns.Labels {
+		suffix, ok := strings.CutPrefix(label, key+"-")
+		if !ok || name != className {
+			continue
+		}
+		if n, err := strconv.Atoi(suffix); err == nil && n >= 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *NamespaceClassCustomValidator) validate(class *namespacev1alpha1.NamespaceClass) error {
+	errs := validation.ValidateNamespaceClass(class)
+	if v.MaxResources > 0 && len(class.Spec.Resources) > v.MaxResources {
+		errs = append(errs, field.TooMany(field.NewPath("spec", "resources"), len(class.Spec.Resources), v.MaxResources))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(namespacev1alpha1.GroupVersion.WithKind("NamespaceClass").GroupKind(), class.Name, errs)
+}
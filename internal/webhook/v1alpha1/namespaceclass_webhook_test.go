@@ -0,0 +1,305 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	namespacev1alpha1 "github.com/kardolus/namespaceclass-operator/api/v1alpha1"
+	"github.com/kardolus/namespaceclass-operator/internal/controller"
+)
+
+var _ = Describe("NamespaceClass Webhook", func() {
+	var (
+		validator NamespaceClassCustomValidator
+		ctx       = context.Background()
+	)
+
+	newClass := func(resources ...string) *namespacev1alpha1.NamespaceClass {
+		class := &namespacev1alpha1.NamespaceClass{ObjectMeta: metav1.ObjectMeta{Name: "webhook-class"}}
+		for _, res := range resources {
+			class.Spec.Resources = append(class.Spec.Resources, runtime.RawExtension{Raw: []byte(res)})
+		}
+		return class
+	}
+
+	It("should admit a class with valid resources", func() {
+		class := newClass(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cfg"}}`)
+
+		_, err := validator.ValidateCreate(ctx, class)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject resources that set metadata.namespace", func() {
+		class := newClass(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cfg","namespace":"elsewhere"}}`)
+
+		_, err := validator.ValidateCreate(ctx, class)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.resources[0].metadata.namespace"))
+
+		_, err = validator.ValidateUpdate(ctx, newClass(), class)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+
+	It("should admit names that are valid for their kind", func() {
+		class := newClass(
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"team.settings"}}`,
+			`{"apiVersion":"v1","kind":"Service","metadata":{"name":"backend"}}`,
+			`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"Role","metadata":{"name":"system:viewer"}}`,
+		)
+
+		_, err := validator.ValidateCreate(ctx, class)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject names that are not valid for their kind", func() {
+		class := newClass(
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"Team_Settings"}}`,
+			`{"apiVersion":"v1","kind":"Service","metadata":{"name":"team.backend"}}`,
+			`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"Role","metadata":{"name":"viewer/all"}}`,
+		)
+
+		_, err := validator.ValidateCreate(ctx, class)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(`spec.resources[0].metadata.name: Invalid value: "Team_Settings": not a valid ConfigMap name`))
+		Expect(err.Error()).To(ContainSubstring(`spec.resources[1].metadata.name: Invalid value: "team.backend": not a valid Service name`))
+		Expect(err.Error()).To(ContainSubstring(`spec.resources[2].metadata.name: Invalid value: "viewer/all": not a valid Role name`))
+	})
+
+	It("should admit templated and repeated names", func() {
+		class := newClass(
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .Namespace }}-settings"}}`,
+			`{"apiVersion":"v1","kind":"Service","metadata":{"name":"{{ .Namespace.Labels.team }}"}}`,
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"tier-{{ .Value }}",`+
+				`"annotations":{"`+controller.NamespaceClassRepeatKey+`":"frontend,backend"}}}`,
+		)
+
+		_, err := validator.ValidateCreate(ctx, class)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject templated names that are not valid for their kind around the template", func() {
+		class := newClass(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"Tier_{{ .Value }}"}}`)
+
+		_, err := validator.ValidateCreate(ctx, class)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(`spec.resources[0].metadata.name: Invalid value: "Tier_{{ .Value }}"`))
+	})
+
+	It("should admit a generateName prefix in place of a name", func() {
+		class := newClass(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"generateName":"settings-"}}`)
+
+		_, err := validator.ValidateCreate(ctx, class)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject resources without a name or generateName", func() {
+		invalid := newClass(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"generateName":"Settings_"}}`)
+		_, err := validator.ValidateCreate(ctx, invalid)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(`spec.resources[0].metadata.generateName: Invalid value: "Settings_"`))
+
+		unnamed := newClass(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{}}`)
+		_, err = validator.ValidateCreate(ctx, unnamed)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.resources[0].metadata.name: Required value"))
+	})
+
+	It("should validate the documents of resourcesYAML like resources", func() {
+		class := newClass()
+		class.Spec.ResourcesYAML = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: Settings_2
+`
+		_, err := validator.ValidateCreate(ctx, class)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(`spec.resourcesYAML[1].metadata.name: Invalid value: "Settings_2"`))
+
+		class.Spec.ResourcesYAML = "kind: ConfigMap\nmetadata: [broken"
+		_, err = validator.ValidateCreate(ctx, class)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.resourcesYAML"))
+	})
+
+	It("should reject resources that are not Kubernetes objects", func() {
+		class := newClass(`{"foo":"not a k8s object"}`)
+
+		_, err := validator.ValidateCreate(ctx, class)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("not a valid Kubernetes object"))
+	})
+
+	It("should reject resources without a kind or apiVersion", func() {
+		class := newClass(
+			`{"apiVersion":"v1","metadata":{"name":"cfg"}}`,
+			`{"kind":"ConfigMap","metadata":{"name":"cfg"}}`,
+		)
+
+		_, err := validator.ValidateCreate(ctx, class)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.resources[0].kind: Required value"))
+		Expect(err.Error()).To(ContainSubstring("spec.resources[1].apiVersion: Required value"))
+		Expect(err.Error()).NotTo(ContainSubstring("'Kind' is missing"))
+
+		class = newClass()
+		class.Spec.ResourcesYAML = "apiVersion: v1\nmetadata:\n  name: settings\n"
+		_, err = validator.ValidateCreate(ctx, class)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.resourcesYAML[0].kind: Required value"))
+	})
+
+	It("should admit a class with exactly the maximum number of resources", func() {
+		limited := NamespaceClassCustomValidator{MaxResources: 2}
+		class := newClass(
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cfg-a"}}`,
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cfg-b"}}`,
+		)
+
+		_, err := limited.ValidateCreate(ctx, class)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject a class with more resources than the maximum", func() {
+		limited := NamespaceClassCustomValidator{MaxResources: 1}
+		class := newClass(
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cfg-a"}}`,
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cfg-b"}}`,
+		)
+
+		_, err := limited.ValidateCreate(ctx, class)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.resources: Too many: 2: must have at most 1 items"))
+
+		_, err = limited.ValidateUpdate(ctx, newClass(), class)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+
+	It("should allow deletion by default", func() {
+		_, err := validator.ValidateDelete(ctx, newClass(`{"foo":"bar"}`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("with orphaning prevented", func() {
+		newNamespace := func(name string, labels, annotations map[string]string) *corev1.Namespace {
+			return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations}}
+		}
+		bound := map[string]string{controller.NamespaceClassNameKey: "webhook-class"}
+		cleanup := map[string]string{controller.NamespaceClassCleanupKey: "true"}
+
+		protecting := func(objs ...client.Object) *NamespaceClassCustomValidator {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(namespacev1alpha1.AddToScheme(scheme)).To(Succeed())
+			return &NamespaceClassCustomValidator{
+				PreventOrphaning: true,
+				Client:           fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+			}
+		}
+
+		It("should reject deleting a class that namespaces without cleanup use", func() {
+			v := protecting(
+				newNamespace("team-a", bound, cleanup),
+				newNamespace("team-b", bound, nil),
+				newNamespace("team-c", bound, map[string]string{controller.NamespaceClassCleanupKey: "false"}),
+				newNamespace("unrelated", nil, nil),
+			)
+
+			_, err := v.ValidateDelete(ctx, newClass())
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("namespaces team-b, team-c still use the class"))
+		})
+
+		It("should reject deleting a class that selects namespaces without cleanup", func() {
+			v := protecting(newNamespace("selected", map[string]string{"tier": "web"}, nil))
+			class := newClass()
+			class.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}}
+
+			_, err := v.ValidateDelete(ctx, class)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("namespaces selected still use the class"))
+		})
+
+		It("should reject deleting the default class that unlabeled namespaces get", func() {
+			other := &namespacev1alpha1.NamespaceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "web"},
+				Spec: namespacev1alpha1.NamespaceClassSpec{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}},
+				},
+			}
+			v := protecting(
+				other,
+				newNamespace("defaulted", nil, nil),
+				newNamespace("kube-system", nil, nil),
+				newNamespace("selected-elsewhere", map[string]string{"tier": "web"}, nil),
+				newNamespace("labeled-elsewhere", map[string]string{controller.NamespaceClassNameKey: "web"}, nil),
+			)
+			v.DefaultClass = "webhook-class"
+			v.ExcludedNamespaces = []string{"kube-system"}
+
+			_, err := v.ValidateDelete(ctx, newClass())
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("namespaces defaulted still use the class"))
+		})
+
+		It("should reject deleting a class that namespaces compose without cleanup", func() {
+			v := protecting(
+				newNamespace("composed", map[string]string{
+					controller.NamespaceClassNameKey:        "base",
+					controller.NamespaceClassNameKey + "-2": "webhook-class",
+				}, nil),
+				newNamespace("not-numbered", map[string]string{controller.NamespaceClassNameKey + "-x": "webhook-class"}, nil),
+			)
+
+			_, err := v.ValidateDelete(ctx, newClass())
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("namespaces composed still use the class"))
+		})
+
+		It("should allow deleting a class whose namespaces all opt into cleanup", func() {
+			v := protecting(newNamespace("team-a", bound, cleanup), newNamespace("unrelated", nil, nil))
+
+			_, err := v.ValidateDelete(ctx, newClass())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should allow deleting a class annotated to allow orphans, with a warning", func() {
+			v := protecting(newNamespace("team-b", bound, nil))
+			class := newClass()
+			class.Annotations = map[string]string{controller.NamespaceClassAllowOrphanKey: "true"}
+
+			warnings, err := v.ValidateDelete(ctx, class)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("left behind in namespaces team-b")))
+		})
+	})
+})
@@ -219,10 +219,9 @@ var _ = Describe("namespaceclass operator e2e", Ordered, func() {
 		Expect(utils.DeleteResource("namespaceclass", class)).To(Succeed())
 
 		By("verifying the injected resource is deleted")
-		Eventually(func() string {
-			out, _ := exec.Command("kubectl", "get", "configmap", cm, "-n", ns).CombinedOutput()
-			return string(out)
-		}, time.Minute, 5*time.Second).Should(ContainSubstring("Error from server (NotFound)"))
+		Eventually(func() (bool, error) {
+			return utils.ResourceExists("ConfigMap", cm, ns)
+		}, time.Minute, 5*time.Second).Should(BeFalse())
 
 		_ = utils.DeleteResource("namespace", ns)
 		_ = utils.DeleteEventsForInvolvedObject("cleanup-ns")
@@ -321,10 +320,9 @@ var _ = Describe("namespaceclass operator e2e", Ordered, func() {
 		})).To(Succeed())
 
 		By("verifying the obsolete ConfigMap is removed")
-		Eventually(func() string {
-			out, _ := exec.Command("kubectl", "get", "configmap", obsoleteCM, "-n", ns).CombinedOutput()
-			return string(out)
-		}, time.Minute, 5*time.Second).Should(ContainSubstring("NotFound"))
+		Eventually(func() (bool, error) {
+			return utils.ResourceExists("ConfigMap", obsoleteCM, ns)
+		}, time.Minute, 5*time.Second).Should(BeFalse())
 
 		By("verifying the remaining ConfigMap still exists")
 		cmd := exec.Command("kubectl", "get", "configmap", initialCM, "-n", ns, "-o", "yaml")
@@ -18,15 +18,33 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 
 	. "github.com/onsi/ginkgo/v2" //nolint:golint,revive
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// clusterClient returns a client for the cluster of the current kubeconfig
+// context, shared by the typed helpers.
+var clusterClient = sync.OnceValues(func() (client.Client, error) {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{})
+})
+
 // ApplyNamespaceClass applies a NamespaceClass manifest with a single ConfigMap resource.
 func ApplyNamespaceClass(className, configMapName, value string) error {
 	manifest := fmt.Sprintf(`
@@ -130,6 +148,31 @@ func DeleteResource(kind, name string, namespace ...string) error {
 	return cmd.Run()
 }
 
+// ResourceExists reports whether the object of the given kind and name exists,
+// without parsing kubectl output. The kind is given as "Kind" for the core API
+// group or as "Kind.group", e.g. "Deployment.apps". Leave namespace empty for
+// cluster-scoped objects.
+func ResourceExists(kind, name, namespace string) (bool, error) {
+	c, err := clusterClient()
+	if err != nil {
+		return false, err
+	}
+
+	_, groupKind := schema.ParseKindArg(kind)
+	mapping, err := c.RESTMapper().RESTMapping(groupKind)
+	if err != nil {
+		return false, err
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(mapping.GroupVersionKind)
+	err = c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, obj)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 // GetProjectDir will return the directory where the project is
 func GetProjectDir() (string, error) {
 	wd, err := os.Getwd()